@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tennashi/tabler/internal/claude"
+	"github.com/tennashi/tabler/internal/config"
+	"github.com/tennashi/tabler/internal/prompts"
+	"github.com/tennashi/tabler/internal/redaction"
+	"github.com/tennashi/tabler/internal/service"
+)
+
+// newAIProvider wraps the live provider with record/replay support, usage metering and
+// privacy redaction. library masks the user-supplied fields of the prompts it renders.
+func newAIProvider(
+	recorder claude.UsageRecorder,
+	live claude.Provider,
+	privacy config.Privacy,
+	library *prompts.Library,
+) (claude.Provider, error) {
+	provider, err := claude.NewProviderFromEnv(live)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure AI provider: %w", err)
+	}
+
+	redactor, err := redaction.NewRedactor(privacy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure redaction: %w", err)
+	}
+	session := redaction.NewSession(redactor)
+	library.SetRedactor(session)
+
+	// Placeholders are restored above metering and recording, so fixtures never
+	// contain the masked text either
+	return redaction.NewProvider(claude.NewMeteredProvider(provider, recorder), session), nil
+}
+
+func handleAICommand(taskService *service.TaskService, args []string) error {
+	if len(args) == 0 || args[0] != "usage" {
+		return fmt.Errorf("usage: tabler ai usage [--since <duration>]")
+	}
+
+	// Default to all recorded usage
+	since := time.Time{}
+
+	i := 1
+	for i < len(args) {
+		switch args[i] {
+		case "--since":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--since requires a value")
+			}
+			window, err := parseSince(args[i+1])
+			if err != nil {
+				return err
+			}
+			since = time.Now().Add(-window)
+			i += 2
+		default:
+			return fmt.Errorf("unknown flag: %s", args[i])
+		}
+	}
+
+	summaries, err := taskService.SummarizeAIUsage(since)
+	if err != nil {
+		return fmt.Errorf("failed to summarize AI usage: %w", err)
+	}
+
+	if len(summaries) == 0 {
+		fmt.Println("No AI usage recorded.")
+		return nil
+	}
+
+	fmt.Println(formatUsageSummaries(summaries))
+	return nil
+}
+
+// parseSince parses a look-back window such as "7d", "2w" or "12h"
+func parseSince(value string) (time.Duration, error) {
+	units := map[string]time.Duration{
+		"d": 24 * time.Hour,
+		"w": 7 * 24 * time.Hour,
+	}
+
+	for suffix, unit := range units {
+		if strings.HasSuffix(value, suffix) {
+			n, err := strconv.Atoi(strings.TrimSuffix(value, suffix))
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("invalid duration: %s", value)
+			}
+			return time.Duration(n) * unit, nil
+		}
+	}
+
+	window, err := time.ParseDuration(value)
+	if err != nil || window < 0 {
+		return 0, fmt.Errorf("invalid duration: %s", value)
+	}
+	return window, nil
+}
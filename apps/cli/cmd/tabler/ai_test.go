@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/service"
+)
+
+func TestParseSince(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"7d", 7 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"12h", 12 * time.Hour, false},
+		{"30m", 30 * time.Minute, false},
+		{"soon", 0, true},
+		{"-1d", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			// Act
+			result, err := parseSince(tt.input)
+
+			// Assert
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error for %q", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFormatUsageSummaries(t *testing.T) {
+	t.Run("should format usage as table", func(t *testing.T) {
+		// Arrange
+		summaries := []*service.UsageSummary{
+			{
+				Feature:     "metadata",
+				Calls:       4,
+				Failures:    1,
+				FailureRate: 0.25,
+				P50Latency:  800 * time.Millisecond,
+				P95Latency:  2500 * time.Millisecond,
+			},
+		}
+
+		// Act
+		result := formatUsageSummaries(summaries)
+
+		// Assert
+		lines := strings.Split(result, "\n")
+		if len(lines) != 3 {
+			t.Fatalf("expected header, separator and 1 row, got %d lines", len(lines))
+		}
+		expected := "metadata             4         1   25.0%  800ms    2.5s"
+		if lines[2] != expected {
+			t.Errorf("expected row:\n%q\ngot:\n%q", expected, lines[2])
+		}
+	})
+}
+
+func TestAIUsageCommand(t *testing.T) {
+	t.Run("should report when no usage is recorded", func(t *testing.T) {
+		// Arrange
+		t.Setenv("TABLER_DATA_DIR", t.TempDir())
+		os.Args = []string{"tabler", "ai", "usage", "--since", "7d"}
+
+		// Act
+		output, err := captureOutput(t, run)
+		// Assert
+		if err != nil {
+			t.Fatalf("run() returned error: %v", err)
+		}
+		if !strings.Contains(output, "No AI usage recorded.") {
+			t.Errorf("unexpected output: %q", output)
+		}
+	})
+}
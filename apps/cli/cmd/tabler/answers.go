@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// exitNeedsAnswers is the exit status when non-interactive clarification stops at a question
+const exitNeedsAnswers = 3
+
+// answerFlags collects repeated --answer key=value flags
+type answerFlags map[string]string
+
+func (a answerFlags) String() string {
+	pairs := make([]string, 0, len(a))
+	for key, value := range a {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (a answerFlags) Set(value string) error {
+	key, answer, ok := strings.Cut(value, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("answer must look like key=value, got %q", value)
+	}
+	a[key] = answer
+	return nil
+}
+
+// clarificationAnswers combines answers from a file and --answer flags, flags winning.
+// It returns nil when clarification should stay interactive.
+func clarificationAnswers(file string, flags answerFlags, nonInteractive bool) (map[string]string, error) {
+	if file == "" && len(flags) == 0 && !nonInteractive {
+		return nil, nil
+	}
+
+	answers := make(map[string]string)
+	if file != "" {
+		loaded, err := loadAnswersFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range loaded {
+			answers[key] = value
+		}
+	}
+	for key, value := range flags {
+		answers[key] = value
+	}
+
+	return answers, nil
+}
+
+// loadAnswersFile reads answers from a JSON object or a flat YAML mapping
+func loadAnswersFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is given by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read answers file: %w", err)
+	}
+
+	ext := strings.ToLower(filepath.Ext(path))
+	if ext == ".json" || strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		var answers map[string]string
+		if err := json.Unmarshal(data, &answers); err != nil {
+			return nil, fmt.Errorf("invalid answers file %s: %w", path, err)
+		}
+		return answers, nil
+	}
+
+	answers, err := parseYAMLAnswers(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid answers file %s: %w", path, err)
+	}
+	return answers, nil
+}
+
+// parseYAMLAnswers parses "key: value" lines, the only YAML answers files need
+func parseYAMLAnswers(data string) (map[string]string, error) {
+	answers := make(map[string]string)
+	for i, line := range strings.Split(data, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected key: value", i+1)
+		}
+		answers[key] = unquote(strings.TrimSpace(value))
+	}
+	return answers, nil
+}
+
+// unquote removes matching single or double quotes around a YAML scalar
+func unquote(value string) string {
+	if len(value) >= 2 {
+		first, last := value[0], value[len(value)-1]
+		if (first == '"' || first == '\'') && first == last {
+			return value[1 : len(value)-1]
+		}
+	}
+	return value
+}
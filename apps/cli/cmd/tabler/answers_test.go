@@ -0,0 +1,91 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClarificationAnswers(t *testing.T) {
+	t.Run("should stay interactive without answers", func(t *testing.T) {
+		// Arrange
+		// Skipping confirmations must not turn questions into exit status 3
+		t.Setenv("TABLER_NON_INTERACTIVE", "1")
+
+		// Act
+		answers, err := clarificationAnswers("", answerFlags{}, false)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if answers != nil {
+			t.Errorf("expected nil answers, got %v", answers)
+		}
+	})
+
+	t.Run("should stop at questions with --non-interactive", func(t *testing.T) {
+		// Act
+		answers, err := clarificationAnswers("", answerFlags{}, true)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if answers == nil || len(answers) != 0 {
+			t.Errorf("expected empty answers, got %v", answers)
+		}
+	})
+
+	t.Run("should let flags override the answers file", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "answers.yaml")
+		content := "# answers for the launch task\nwhat: \"launch slides\"\ndeadline: friday\n"
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		flags := answerFlags{}
+		if err := flags.Set("deadline=2030-05-17"); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		answers, err := clarificationAnswers(path, flags, false)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if answers["what"] != "launch slides" || answers["deadline"] != "2030-05-17" {
+			t.Errorf("unexpected answers %v", answers)
+		}
+	})
+
+	t.Run("should read JSON answers files", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "answers.json")
+		if err := os.WriteFile(path, []byte(`{"q1": "slides", "audience": "the team"}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		answers, err := clarificationAnswers(path, answerFlags{}, false)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if answers["q1"] != "slides" || answers["audience"] != "the team" {
+			t.Errorf("unexpected answers %v", answers)
+		}
+	})
+
+	t.Run("should reject malformed answers", func(t *testing.T) {
+		// Arrange
+		flags := answerFlags{}
+
+		// Act
+		err := flags.Set("no separator")
+
+		// Assert
+		if err == nil {
+			t.Error("expected error for answer without '='")
+		}
+	})
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/tennashi/tabler/internal/service"
+	"github.com/tennashi/tabler/internal/task"
+)
+
+const (
+	deleteUsage = "usage: tabler delete [--yes] <task-id>... | tabler delete --yes - (IDs from stdin)"
+	bulkUsage   = "usage: tabler bulk --filter <query> [--yes] <done | delete | tag +tag -tag... | priority 0-3>"
+)
+
+// readTaskIDs returns the task IDs given as arguments, or read from stdin when
+// the only argument is "-", without duplicates
+func readTaskIDs(args []string, stdin io.Reader) ([]string, error) {
+	if len(args) == 1 && args[0] == "-" {
+		var fromStdin []string
+		scanner := bufio.NewScanner(stdin)
+		scanner.Split(bufio.ScanWords)
+		for scanner.Scan() {
+			fromStdin = append(fromStdin, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read task IDs: %w", err)
+		}
+		args = fromStdin
+	}
+
+	var ids []string
+	for _, id := range args {
+		if strings.HasPrefix(id, "-") {
+			return nil, fmt.Errorf("unexpected flag: %s", id)
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// getTasks loads tasks by ID, failing on the first unknown one
+func getTasks(taskService *service.TaskService, ids []string) ([]*task.Task, error) {
+	tasks := make([]*task.Task, 0, len(ids))
+	for _, id := range ids {
+		t, _, err := taskService.GetTask(id)
+		if err != nil {
+			if isNotFoundError(err.Error()) {
+				return nil, errors.New(formatTaskError(ErrTaskNotFound, id))
+			}
+			return nil, fmt.Errorf("failed to get task: %w", err)
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, nil
+}
+
+func completeTasks(taskService *service.TaskService, taskIDs []string) error {
+	if _, err := getTasks(taskService, taskIDs); err != nil {
+		return err
+	}
+
+	unblocked, err := taskService.CompleteTasksAndUnblock(taskIDs...)
+	if err != nil {
+		return fmt.Errorf("failed to complete task: %w", err)
+	}
+
+	for _, id := range taskIDs {
+		fmt.Printf("Task completed: %s\n", id)
+	}
+	for _, t := range unblocked {
+		fmt.Printf("Now actionable: %s (%s)\n", t.Title, t.ID)
+	}
+	return nil
+}
+
+func handleDeleteCommand(taskService *service.TaskService, args []string) error {
+	yes := false
+	if len(args) > 0 && args[0] == "--yes" {
+		yes = true
+		args = args[1:]
+	}
+	taskIDs, err := readTaskIDs(args, os.Stdin)
+	if err != nil || len(taskIDs) == 0 {
+		return errors.New(deleteUsage)
+	}
+	if len(args) == 1 && args[0] == "-" && !yes && os.Getenv("TABLER_NON_INTERACTIVE") != "1" {
+		// stdin is taken by the IDs, so it cannot answer the confirmation
+		return fmt.Errorf("deleting IDs read from stdin needs --yes")
+	}
+
+	tasks, err := getTasks(taskService, taskIDs)
+	if err != nil {
+		return err
+	}
+
+	// Skip confirmation in non-interactive mode (for tests)
+	if !yes && os.Getenv("TABLER_NON_INTERACTIVE") != "1" {
+		confirmed := false
+		if len(tasks) == 1 {
+			confirmed = confirmDeletion(tasks[0].Title, os.Stdin)
+		} else {
+			fmt.Println(formatTaskTitles(tasks))
+			confirmed = confirmAction(fmt.Sprintf("Delete these %d tasks?", len(tasks)), os.Stdin)
+		}
+		if !confirmed {
+			fmt.Println("Deletion cancelled.")
+			return nil
+		}
+	}
+
+	if err := taskService.DeleteTasks(taskIDs); err != nil {
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	for _, id := range taskIDs {
+		fmt.Printf("Task deleted: %s\n", id)
+	}
+	return nil
+}
+
+func handleBulkCommand(taskService *service.TaskService, args []string) error {
+	filter := ""
+	yes := false
+	for len(args) > 0 && strings.HasPrefix(args[0], "--") {
+		switch args[0] {
+		case "--filter":
+			if len(args) < 2 {
+				return errors.New(bulkUsage)
+			}
+			filter = args[1]
+			args = args[2:]
+		case "--yes":
+			yes = true
+			args = args[1:]
+		default:
+			return fmt.Errorf("unknown flag: %s", args[0])
+		}
+	}
+	if strings.TrimSpace(filter) == "" || len(args) == 0 {
+		return errors.New(bulkUsage)
+	}
+
+	apply, description, err := parseBulkAction(taskService, args)
+	if err != nil {
+		return err
+	}
+
+	items, err := taskService.ListTasks(&service.FilterOptions{Query: filter})
+	if err != nil {
+		return fmt.Errorf("failed to list tasks: %w", err)
+	}
+	if len(items) == 0 {
+		fmt.Println("No tasks match the filter.")
+		return nil
+	}
+
+	// Preview what will change and confirm once
+	fmt.Println(formatTasksAsTable(items))
+	if !yes && os.Getenv("TABLER_NON_INTERACTIVE") != "1" {
+		if !confirmAction(fmt.Sprintf("%s %d tasks?", description, len(items)), os.Stdin) {
+			fmt.Println("Bulk operation cancelled.")
+			return nil
+		}
+	}
+
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.Task.ID
+	}
+	if err := apply(ids); err != nil {
+		return fmt.Errorf("bulk operation failed, no task was changed: %w", err)
+	}
+
+	fmt.Printf("%s: %d tasks\n", description, len(ids))
+	return nil
+}
+
+// parseBulkAction reads the action of a bulk command, returning the function
+// that applies it and a description such as "Delete"
+func parseBulkAction(taskService *service.TaskService, args []string) (func(ids []string) error, string, error) {
+	switch args[0] {
+	case "done":
+		if len(args) != 1 {
+			return nil, "", errors.New(bulkUsage)
+		}
+		return func(ids []string) error {
+			_, err := taskService.CompleteTasksAndUnblock(ids...)
+			return err
+		}, "Complete", nil
+	case "delete":
+		if len(args) != 1 {
+			return nil, "", errors.New(bulkUsage)
+		}
+		return taskService.DeleteTasks, "Delete", nil
+	case "tag":
+		var add, remove []string
+		for _, arg := range args[1:] {
+			switch {
+			case strings.HasPrefix(arg, "+") && len(arg) > 1:
+				add = append(add, arg[1:])
+			case strings.HasPrefix(arg, "-") && len(arg) > 1:
+				remove = append(remove, arg[1:])
+			default:
+				return nil, "", fmt.Errorf("tag changes look like +tag or -tag, got %q", arg)
+			}
+		}
+		if len(add) == 0 && len(remove) == 0 {
+			return nil, "", errors.New(bulkUsage)
+		}
+		return func(ids []string) error {
+			return taskService.TagTasks(ids, add, remove)
+		}, "Tag " + strings.Join(args[1:], " ") + " on", nil
+	case "priority":
+		if len(args) != 2 {
+			return nil, "", errors.New(bulkUsage)
+		}
+		priority, err := strconv.Atoi(args[1])
+		if err != nil || priority < 0 || priority > 3 {
+			return nil, "", fmt.Errorf("priority must be between 0 and 3, got %q", args[1])
+		}
+		return func(ids []string) error {
+			return taskService.SetTasksPriority(ids, priority)
+		}, fmt.Sprintf("Set priority %d on", priority), nil
+	default:
+		return nil, "", fmt.Errorf("unknown bulk action: %s\n%s", args[0], bulkUsage)
+	}
+}
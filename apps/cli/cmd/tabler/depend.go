@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tennashi/tabler/internal/service"
+	"github.com/tennashi/tabler/internal/storage"
+)
+
+const dependUsage = "usage: tabler depend <task-id> --on <task-id> | tabler depend <task-id> --remove <task-id>"
+
+func handleDependCommand(taskService *service.TaskService, args []string) error {
+	if len(args) != 3 || (args[1] != "--on" && args[1] != "--remove") {
+		return errors.New(dependUsage)
+	}
+	taskID, dependsOnID := args[0], args[2]
+
+	if args[1] == "--remove" {
+		if err := taskService.RemoveDependency(taskID, dependsOnID); err != nil {
+			if isNotFoundError(err.Error()) {
+				return fmt.Errorf("task %s does not depend on %s", taskID, dependsOnID)
+			}
+			return fmt.Errorf("failed to remove dependency: %w", err)
+		}
+		fmt.Printf("Task %s no longer depends on %s\n", taskID, dependsOnID)
+		return nil
+	}
+
+	for _, id := range []string{taskID, dependsOnID} {
+		if _, _, err := taskService.GetTask(id); err != nil {
+			if isNotFoundError(err.Error()) {
+				return errors.New(formatTaskError(ErrTaskNotFound, id))
+			}
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+	}
+
+	err := taskService.AddDependency(taskID, dependsOnID)
+	var cycleErr *storage.CycleError
+	if errors.As(err, &cycleErr) {
+		return fmt.Errorf("cannot add dependency, tasks would wait for each other: %s",
+			formatDependencyPath(taskService, cycleErr.Path))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add dependency: %w", err)
+	}
+
+	fmt.Printf("Task %s now depends on %s\n", taskID, dependsOnID)
+	return nil
+}
+
+// formatDependencyPath shows a chain of task IDs by their titles
+func formatDependencyPath(taskService *service.TaskService, path []string) string {
+	titles := make([]string, len(path))
+	for i, id := range path {
+		titles[i] = id
+		if t, _, err := taskService.GetTask(id); err == nil {
+			titles[i] = t.Title
+		}
+	}
+	return strings.Join(titles, " -> ")
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/tennashi/tabler/internal/config"
+	"github.com/tennashi/tabler/internal/enrichment"
+	"github.com/tennashi/tabler/internal/metadata"
+	"github.com/tennashi/tabler/internal/service"
+)
+
+func handleEnrichCommand(taskService *service.TaskService, dataDir string, cfg *config.Config, args []string) error {
+	// Without a filter, enrich every untagged task
+	filter := &service.FilterOptions{Untagged: true}
+	batchSize := 0
+	acceptAll := false
+
+	i := 0
+	for i < len(args) {
+		switch args[i] {
+		case "--filter":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--filter requires a value")
+			}
+			parsed, err := parseEnrichFilter(args[i+1])
+			if err != nil {
+				return err
+			}
+			filter = parsed
+			i += 2
+		case "--batch-size":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--batch-size requires a value")
+			}
+			size, err := strconv.Atoi(args[i+1])
+			if err != nil || size < 1 {
+				return fmt.Errorf("invalid batch size: %s", args[i+1])
+			}
+			batchSize = size
+			i += 2
+		case "--yes", "-y":
+			acceptAll = true
+			i++
+		default:
+			return fmt.Errorf("unknown flag: %s", args[i])
+		}
+	}
+
+	candidates, err := taskService.EnrichmentCandidates(filter)
+	if err != nil {
+		return fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	if len(candidates) == 0 {
+		fmt.Println("No tasks to enrich.")
+		return nil
+	}
+
+	library := newPromptLibrary(dataDir)
+	provider, err := newAIProvider(taskService, metadata.NewSubprocessProvider(), cfg.Privacy, library)
+	if err != nil {
+		return err
+	}
+
+	enricher := enrichment.NewEnricher(provider)
+	enricher.SetPrompts(library)
+	enricher.SetTagRules(cfg.Tags.Rules())
+	if batchSize > 0 {
+		enricher.SetBatchSize(batchSize)
+	}
+
+	fmt.Printf("📋 Asking AI to enrich %d tasks...\n", len(candidates))
+
+	proposals, err := enricher.Propose(context.Background(), candidates)
+	if err != nil {
+		return fmt.Errorf("failed to enrich tasks: %w", err)
+	}
+
+	if len(proposals) == 0 {
+		fmt.Println("No changes proposed.")
+		return nil
+	}
+
+	applied, err := reviewProposals(taskService, proposals, acceptAll, bufio.NewReader(os.Stdin))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Tasks enriched: %d of %d\n", applied, len(proposals))
+	return nil
+}
+
+// parseEnrichFilter parses "tag:<name>", "untagged" or "all"
+func parseEnrichFilter(value string) (*service.FilterOptions, error) {
+	switch {
+	case value == "untagged":
+		return &service.FilterOptions{Untagged: true}, nil
+	case value == "all":
+		return &service.FilterOptions{}, nil
+	case strings.HasPrefix(value, "tag:") && len(value) > len("tag:"):
+		return &service.FilterOptions{Tag: strings.TrimPrefix(value, "tag:")}, nil
+	default:
+		return nil, fmt.Errorf("invalid filter: %s (expected tag:<name>, untagged or all)", value)
+	}
+}
+
+// reviewProposals shows each diff and applies the ones the user accepts
+func reviewProposals(
+	taskService *service.TaskService,
+	proposals []*enrichment.Proposal,
+	acceptAll bool,
+	reader *bufio.Reader,
+) (int, error) {
+	applied := 0
+
+	for i, proposal := range proposals {
+		fmt.Println(formatProposal(proposal, i+1, len(proposals)))
+
+		if !acceptAll {
+			switch askChoice("Apply? (y/N/a=all/q=quit): ", reader) {
+			case "y":
+			case "a":
+				acceptAll = true
+			case "q":
+				return applied, nil
+			default:
+				fmt.Println()
+				continue
+			}
+		}
+
+		if err := taskService.ApplyEnrichment(proposal); err != nil {
+			return applied, fmt.Errorf("failed to update task %s: %w", proposal.Candidate.ID, err)
+		}
+		applied++
+		fmt.Println()
+	}
+
+	return applied, nil
+}
@@ -20,10 +20,21 @@ type command struct {
 var availableCommands = []command{
 	{"add", "Add a new task"},
 	{"list", "List all tasks"},
-	{"done", "Mark a task as completed"},
+	{"done", "Mark tasks as completed"},
 	{"show", "Show task details"},
-	{"delete", "Delete a task"},
+	{"delete", "Delete tasks"},
 	{"update", "Update a task"},
+	{"bulk", "Change every task matching a filter at once"},
+	{"tags", "List, rename, merge, delete or normalize tags"},
+	{"view", "Save and show named list filters"},
+	{"depend", "Make a task wait for another task"},
+	{"next", "Recommend the most urgent tasks"},
+	{"enrich", "Suggest metadata for existing tasks with AI"},
+	{"suggest", "Suggest tags from your past tasks"},
+	{"learning", "Show, export or clear learned patterns"},
+	{"talk", "List or resume unfinished talk-mode dialogues"},
+	{"prompts", "List or export AI prompt templates"},
+	{"ai", "Show AI usage statistics"},
 }
 
 func formatTaskError(err error, taskID string) string {
@@ -1,11 +1,18 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/tennashi/tabler/internal/clarification"
+	"github.com/tennashi/tabler/internal/decomposition"
+	"github.com/tennashi/tabler/internal/enrichment"
+	"github.com/tennashi/tabler/internal/learning"
+	"github.com/tennashi/tabler/internal/mode"
 	"github.com/tennashi/tabler/internal/service"
+	"github.com/tennashi/tabler/internal/storage"
 	"github.com/tennashi/tabler/internal/task"
 )
 
@@ -15,6 +22,7 @@ const (
 	taskColumnWidth = 23
 	statusPending   = "[ ]"
 	statusCompleted = "[✓]"
+	statusBlocked   = "[⏳]"
 	dateFormat      = "Jan 2, 2006"
 	dateTimeFormat  = "Jan 2, 2006 3:04 PM"
 
@@ -41,6 +49,92 @@ func formatTasksAsTable(taskItems []*service.TaskItem) string {
 	return formatTasksCompact(taskItems)
 }
 
+// List output formats
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputIDs   = "ids"
+)
+
+var outputFormats = []string{outputTable, outputJSON, outputIDs}
+
+// taskOutput is the JSON shape of a listed task
+type taskOutput struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Tags      []string   `json:"tags"`
+	Priority  int        `json:"priority"`
+	Deadline  *time.Time `json:"deadline"`
+	Completed bool       `json:"completed"`
+	Blocked   bool       `json:"blocked"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func formatTasksAsJSON(taskItems []*service.TaskItem) string {
+	output := make([]taskOutput, 0, len(taskItems))
+	for _, item := range taskItems {
+		t := taskOutput{
+			ID:        item.Task.ID,
+			Title:     item.Task.Title,
+			Tags:      item.Tags,
+			Priority:  item.Task.Priority,
+			Completed: item.Task.Completed,
+			Blocked:   item.Blocked,
+			CreatedAt: item.Task.CreatedAt,
+		}
+		if t.Tags == nil {
+			t.Tags = []string{}
+		}
+		if !item.Task.Deadline.IsZero() {
+			deadline := item.Task.Deadline
+			t.Deadline = &deadline
+		}
+		output = append(output, t)
+	}
+
+	// Marshalling plain values cannot fail
+	data, _ := json.MarshalIndent(output, "", "  ")
+	return string(data)
+}
+
+// formatViewList shows each saved view with its filter and options
+func formatViewList(views []*storage.View) string {
+	lines := make([]string, 0, len(views))
+	for _, view := range views {
+		filter := view.Query
+		if filter == "" {
+			filter = "(all tasks)"
+		}
+
+		var options []string
+		if view.Sort != "" {
+			options = append(options, "sort: "+view.Sort)
+		}
+		if view.Output != "" {
+			options = append(options, "output: "+view.Output)
+		}
+
+		line := fmt.Sprintf("%-16s %s", view.Name, filter)
+		if len(options) > 0 {
+			line += "  [" + strings.Join(options, ", ") + "]"
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatListStatus marks pending tasks waiting on unfinished dependencies
+func formatListStatus(item *service.TaskItem) string {
+	switch {
+	case item.Task.Completed:
+		return statusCompleted
+	case item.Blocked:
+		return statusBlocked
+	default:
+		return statusPending
+	}
+}
+
 func formatTasksCompact(taskItems []*service.TaskItem) string {
 	var result strings.Builder
 
@@ -50,10 +144,7 @@ func formatTasksCompact(taskItems []*service.TaskItem) string {
 
 	// Rows
 	for _, item := range taskItems {
-		status := statusPending
-		if item.Task.Completed {
-			status = statusCompleted
-		}
+		status := formatListStatus(item)
 
 		// Format with fixed width columns
 		result.WriteString(fmt.Sprintf("%-*s %-*s %s\n",
@@ -75,10 +166,7 @@ func formatTasksWithMetadata(taskItems []*service.TaskItem) string {
 
 	// Rows
 	for _, item := range taskItems {
-		status := statusPending
-		if item.Task.Completed {
-			status = statusCompleted
-		}
+		status := formatListStatus(item)
 
 		// Format tags
 		tags := "-"
@@ -119,6 +207,52 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
+// formatRecommendations ranks tasks by urgency, optionally with the score breakdown
+func formatRecommendations(recommendations []*service.Recommendation, explain bool) string {
+	var result strings.Builder
+	for i, recommendation := range recommendations {
+		status := statusPending
+		if recommendation.Waiting {
+			status = statusBlocked
+		}
+		result.WriteString(fmt.Sprintf("%2d. %-*s  %-*s  %s  score %.1f\n",
+			i+1,
+			idDisplayWidth, recommendation.Task.ID[:idDisplayWidth],
+			extTaskColumnWidth, truncateString(recommendation.Task.Title, extTaskColumnWidth),
+			status, recommendation.Score))
+
+		if !explain {
+			continue
+		}
+		if len(recommendation.Breakdown) == 0 {
+			result.WriteString("      nothing makes this task urgent\n")
+		}
+		for _, component := range recommendation.Breakdown {
+			result.WriteString(fmt.Sprintf("      %+6.1f  %-8s  %s\n", component.Points, component.Factor, component.Detail))
+		}
+	}
+	return strings.TrimRight(result.String(), "\n")
+}
+
+// formatTaskTitles lists tasks by short ID and title
+func formatTaskTitles(tasks []*task.Task) string {
+	lines := make([]string, len(tasks))
+	for i, t := range tasks {
+		lines[i] = fmt.Sprintf("  %s  %s", t.ID[:idDisplayWidth], t.Title)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatBlockers lists the unfinished tasks a task is waiting for
+func formatBlockers(blockers []*task.Task) string {
+	var result strings.Builder
+	result.WriteString("Blocked by:")
+	for _, blocker := range blockers {
+		result.WriteString(fmt.Sprintf("\n  %s  %s", blocker.ID[:idDisplayWidth], blocker.Title))
+	}
+	return result.String()
+}
+
 func formatTaskDetails(task *task.Task, tags []string) string {
 	var result strings.Builder
 
@@ -146,6 +280,24 @@ func formatTaskDetails(task *task.Task, tags []string) string {
 		result.WriteString(fmt.Sprintf("Deadline: %s\n", task.Deadline.Format(dateFormat)))
 	}
 
+	// Effort estimate
+	if task.Effort > 0 {
+		result.WriteString(fmt.Sprintf("Effort: ~%s\n", decomposition.FormatEffort(task.Effort)))
+	}
+
+	// AI opt-out
+	if task.NoAI {
+		result.WriteString("AI: Disabled\n")
+	}
+
+	// Notes, one detail per indented line
+	if task.Notes != "" {
+		result.WriteString("Notes:\n")
+		for _, line := range strings.Split(task.Notes, "\n") {
+			result.WriteString(fmt.Sprintf("  %s\n", line))
+		}
+	}
+
 	// Created
 	result.WriteString(fmt.Sprintf("Created: %s\n", formatDateTime(task.CreatedAt)))
 
@@ -171,3 +323,194 @@ func getPriorityName(priority int) string {
 func formatDateTime(t time.Time) string {
 	return t.Format(dateTimeFormat)
 }
+
+func formatUsageSummaries(summaries []*service.UsageSummary) string {
+	var result strings.Builder
+
+	result.WriteString("Feature          Calls  Failures  Fail %  p50      p95\n")
+	result.WriteString("---------------  -----  --------  ------  -------  -------\n")
+
+	for _, summary := range summaries {
+		result.WriteString(fmt.Sprintf("%-15s  %5d  %8d  %5.1f%%  %-7s  %s\n",
+			summary.Feature,
+			summary.Calls,
+			summary.Failures,
+			summary.FailureRate*100,
+			formatLatency(summary.P50Latency),
+			formatLatency(summary.P95Latency)))
+	}
+
+	return strings.TrimRight(result.String(), "\n")
+}
+
+func formatLatency(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+func formatProposal(proposal *enrichment.Proposal, index, total int) string {
+	var result strings.Builder
+
+	candidate := proposal.Candidate
+	result.WriteString(fmt.Sprintf("[%d/%d] %s (%s)\n", index, total, candidate.Title, candidate.ID[:idDisplayWidth]))
+
+	formatChange := func(label, before, after string) {
+		if before != after {
+			result.WriteString(fmt.Sprintf("  %-9s %s → %s\n", label+":", before, after))
+		}
+	}
+
+	formatChange("tags", formatTagList(candidate.Tags), formatTagList(proposal.Tags))
+	formatChange("priority", getPriorityName(candidate.Priority), getPriorityName(proposal.Priority))
+	formatChange("deadline", formatDeadline(candidate.Deadline), formatDeadline(proposal.Deadline))
+
+	return strings.TrimRight(result.String(), "\n")
+}
+
+func formatTagList(tags []string) string {
+	if len(tags) == 0 {
+		return "-"
+	}
+	return strings.Join(tags, ", ")
+}
+
+func formatDeadline(deadline time.Time) string {
+	if deadline.IsZero() {
+		return "-"
+	}
+	return deadline.Format(dateFormat)
+}
+
+func formatTagUsage(usages []*storage.TagUsage) string {
+	var result strings.Builder
+	result.WriteString("Tag                  Tasks  Last used\n")
+	result.WriteString("-------------------  -----  ------------\n")
+	for _, usage := range usages {
+		result.WriteString(fmt.Sprintf("%-19s  %5d  %s\n",
+			truncateString("#"+usage.Tag, 19), usage.Count, usage.LastUsed.Format(dateFormat)))
+	}
+	return strings.TrimRight(result.String(), "\n")
+}
+
+// formatTaskCount says "1 task" or "N tasks"
+func formatTaskCount(count int) string {
+	if count == 1 {
+		return "1 task"
+	}
+	return fmt.Sprintf("%d tasks", count)
+}
+
+// formatTagCount says "1 tag" or "N tags"
+func formatTagCount(count int) string {
+	if count == 1 {
+		return "1 tag"
+	}
+	return fmt.Sprintf("%d tags", count)
+}
+
+func formatTagSuggestions(suggestions []learning.Suggestion) string {
+	lines := make([]string, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		unit := "times"
+		if suggestion.Count == 1 {
+			unit = "time"
+		}
+		lines = append(lines, fmt.Sprintf("#%s (used %d %s)", suggestion.Value, suggestion.Count, unit))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatLearningStatus(status *service.LearningStatus) string {
+	enabled := "disabled"
+	if status.Enabled {
+		enabled = "enabled"
+	}
+
+	retention := "forever"
+	if status.RetentionDays > 0 {
+		retention = fmt.Sprintf("%d days", status.RetentionDays)
+	}
+
+	return fmt.Sprintf("Learning: %s\nPatterns stored: %d\nRetention: %s", enabled, status.Patterns, retention)
+}
+
+func formatDialogueList(sessions []*clarification.DialogueSession) string {
+	blocks := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		var block strings.Builder
+		block.WriteString(fmt.Sprintf("ID: %s\n", session.ID))
+		block.WriteString(fmt.Sprintf("Input: %s\n", session.Input))
+		block.WriteString(fmt.Sprintf("Updated: %s (%d answered)\n", formatDateTime(session.UpdatedAt), len(session.Answered())))
+		block.WriteString(fmt.Sprintf("Next question: %s", session.CurrentQuestion))
+		blocks = append(blocks, block.String())
+	}
+	return strings.Join(blocks, "\n\n")
+}
+
+// pendingQuestionOutput is the JSON a wrapper reads to continue a non-interactive dialogue
+type pendingQuestionOutput struct {
+	SessionID string            `json:"session_id,omitempty"`
+	Input     string            `json:"input"`
+	Questions []pendingQuestion `json:"questions"`
+	Answered  []answeredOutput  `json:"answered"`
+}
+
+type pendingQuestion struct {
+	Key      string `json:"key"`
+	Topic    string `json:"topic,omitempty"`
+	Question string `json:"question"`
+}
+
+type answeredOutput struct {
+	Key      string `json:"key"`
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+func formatPendingQuestion(pending *mode.PendingQuestionError) string {
+	output := pendingQuestionOutput{
+		SessionID: pending.SessionID,
+		Input:     pending.Input,
+		Questions: []pendingQuestion{{Key: pending.Key, Topic: pending.Topic, Question: pending.Question}},
+		Answered:  make([]answeredOutput, 0, len(pending.Answered)),
+	}
+	for i, exchange := range pending.Answered {
+		output.Answered = append(output.Answered, answeredOutput{
+			Key:      fmt.Sprintf("q%d", i+1),
+			Question: exchange.Question,
+			Answer:   exchange.Answer,
+		})
+	}
+
+	// Marshalling plain strings cannot fail
+	data, _ := json.MarshalIndent(output, "", "  ")
+	return string(data)
+}
+
+func formatModeExplanation(input string, explanation *mode.Explanation) string {
+	var result strings.Builder
+
+	chosen, _, hasPrefix := mode.ParseModePrefix(input)
+	if hasPrefix {
+		result.WriteString(fmt.Sprintf("Mode: %s (chosen with a prefix)\n", chosen))
+		result.WriteString(fmt.Sprintf("Auto-detection would choose: %s\n", explanation.Mode))
+	} else {
+		result.WriteString(fmt.Sprintf("Mode: %s (auto-detected; use /q, /t or /p to choose)\n", explanation.Mode))
+	}
+
+	modes := []mode.Mode{mode.QuickMode, mode.TalkMode, mode.PlanningMode}
+	scores := make([]string, 0, len(modes))
+	for _, m := range modes {
+		scores = append(scores, fmt.Sprintf("%s %.2f", m, explanation.Scores[m]))
+	}
+	result.WriteString(fmt.Sprintf("Scores: %s\n", strings.Join(scores, ", ")))
+
+	result.WriteString("Why:")
+	for _, reason := range explanation.SortedReasons() {
+		result.WriteString(fmt.Sprintf("\n  +%.2f %s: %s", reason.Weight, reason.Mode, reason.Text))
+	}
+
+	return result.String()
+}
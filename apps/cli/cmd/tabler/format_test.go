@@ -5,7 +5,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/tennashi/tabler/internal/clarification"
+	"github.com/tennashi/tabler/internal/enrichment"
+	"github.com/tennashi/tabler/internal/learning"
+	"github.com/tennashi/tabler/internal/mode"
 	"github.com/tennashi/tabler/internal/service"
+	"github.com/tennashi/tabler/internal/storage"
 	"github.com/tennashi/tabler/internal/task"
 )
 
@@ -64,6 +69,60 @@ Modified: Jan 15, 2024 2:45 PM`
 			t.Error("expected 'Status: Completed' for completed task")
 		}
 	})
+
+	t.Run("should list notes line by line", func(t *testing.T) {
+		// Arrange
+		task := &task.Task{
+			ID:        "abc123",
+			Title:     "Prepare slides",
+			Notes:     "Audience: team\nMaterials: charts",
+			CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		}
+
+		// Act
+		result := formatTaskDetails(task, nil)
+
+		// Assert
+		if !strings.Contains(result, "Notes:\n  Audience: team\n  Materials: charts\n") {
+			t.Errorf("expected indented notes in:\n%s", result)
+		}
+	})
+
+	t.Run("should mark tasks opted out of AI", func(t *testing.T) {
+		// Arrange
+		task := &task.Task{
+			ID:        "abc123",
+			Title:     "Call lawyer",
+			NoAI:      true,
+			CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		}
+
+		// Act
+		result := formatTaskDetails(task, nil)
+
+		// Assert
+		if !strings.Contains(result, "AI: Disabled") {
+			t.Errorf("expected 'AI: Disabled' in:\n%s", result)
+		}
+	})
+
+	t.Run("should show the effort estimate", func(t *testing.T) {
+		// Arrange
+		task := &task.Task{
+			ID:        "abc123",
+			Title:     "Book venue",
+			Effort:    90 * time.Minute,
+			CreatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+		}
+
+		// Act
+		result := formatTaskDetails(task, nil)
+
+		// Assert
+		if !strings.Contains(result, "Effort: ~1h30m\n") {
+			t.Errorf("expected 'Effort: ~1h30m' in:\n%s", result)
+		}
+	})
 }
 
 func TestFormatTasksAsTable(t *testing.T) {
@@ -154,4 +213,304 @@ def456  Review documentation    [✓]`
 			t.Errorf("expected:\n%s\n\ngot:\n%s", expected, result)
 		}
 	})
+
+	t.Run("should mark tasks waiting on unfinished dependencies", func(t *testing.T) {
+		// Arrange
+		now := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+		taskItems := []*service.TaskItem{
+			{Task: &task.Task{ID: "abc123", Title: "Code review", CreatedAt: now}},
+			{Task: &task.Task{ID: "def456", Title: "Deploy", CreatedAt: now}, Blocked: true},
+		}
+
+		// Act
+		result := formatTasksAsTable(taskItems)
+
+		// Assert
+		expected := `ID      Task                    Status
+---     --------------------    ------
+abc123  Code review             [ ]
+def456  Deploy                  [⏳]`
+
+		if result != expected {
+			t.Errorf("expected:\n%s\n\ngot:\n%s", expected, result)
+		}
+	})
+}
+
+func TestFormatTasksAsJSON(t *testing.T) {
+	t.Run("should describe tasks with empty tags and missing deadlines", func(t *testing.T) {
+		// Arrange
+		created := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+		deadline := time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)
+		taskItems := []*service.TaskItem{
+			{Task: &task.Task{ID: "abc123", Title: "Deploy", Priority: 2, Deadline: deadline, CreatedAt: created},
+				Tags: []string{"work"}, Blocked: true},
+			{Task: &task.Task{ID: "def456", Title: "Water plants", Completed: true, CreatedAt: created}},
+		}
+
+		// Act
+		result := formatTasksAsJSON(taskItems)
+
+		// Assert
+		expected := `[
+  {
+    "id": "abc123",
+    "title": "Deploy",
+    "tags": [
+      "work"
+    ],
+    "priority": 2,
+    "deadline": "2024-01-19T00:00:00Z",
+    "completed": false,
+    "blocked": true,
+    "created_at": "2024-01-15T10:30:00Z"
+  },
+  {
+    "id": "def456",
+    "title": "Water plants",
+    "tags": [],
+    "priority": 0,
+    "deadline": null,
+    "completed": true,
+    "blocked": false,
+    "created_at": "2024-01-15T10:30:00Z"
+  }
+]`
+
+		if result != expected {
+			t.Errorf("expected:\n%s\n\ngot:\n%s", expected, result)
+		}
+	})
+}
+
+func TestFormatRecommendations(t *testing.T) {
+	t.Run("should rank tasks and explain their scores", func(t *testing.T) {
+		// Arrange
+		recommendations := []*service.Recommendation{
+			{
+				TaskItem: &service.TaskItem{Task: &task.Task{ID: "abc123", Title: "Quarterly report"}},
+				Score:    16,
+				Breakdown: []service.ScoreComponent{
+					{Factor: "priority", Detail: "!!", Points: 6},
+					{Factor: "deadline", Detail: "due today", Points: 10},
+				},
+			},
+			{
+				TaskItem: &service.TaskItem{Task: &task.Task{ID: "def456", Title: "Deploy"}},
+				Waiting:  true,
+				Score:    -20,
+				Breakdown: []service.ScoreComponent{
+					{Factor: "blocked", Detail: "waiting on dependencies or subtasks", Points: -20},
+				},
+			},
+		}
+
+		// Act
+		result := formatRecommendations(recommendations, true)
+
+		// Assert
+		expected := ` 1. abc123  Quarterly report                 [ ]  score 16.0
+        +6.0  priority  !!
+       +10.0  deadline  due today
+ 2. def456  Deploy                           [⏳]  score -20.0
+       -20.0  blocked   waiting on dependencies or subtasks`
+
+		if result != expected {
+			t.Errorf("expected:\n%s\n\ngot:\n%s", expected, result)
+		}
+	})
+}
+
+func TestFormatProposal(t *testing.T) {
+	t.Run("should show only changed fields as before and after", func(t *testing.T) {
+		// Arrange
+		proposal := &enrichment.Proposal{
+			Candidate: &enrichment.Candidate{
+				ID:       "abc123def",
+				Title:    "Write report",
+				Tags:     []string{"work"},
+				Priority: 2,
+			},
+			Tags:     []string{"docs", "work"},
+			Priority: 2,
+			Deadline: time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC),
+		}
+
+		// Act
+		result := formatProposal(proposal, 1, 3)
+
+		// Assert
+		expected := `[1/3] Write report (abc123)
+  tags:     work → docs, work
+  deadline: - → Jan 19, 2024`
+
+		if result != expected {
+			t.Errorf("expected:\n%s\n\ngot:\n%s", expected, result)
+		}
+	})
+}
+
+func TestFormatTagUsage(t *testing.T) {
+	t.Run("should show counts and last use", func(t *testing.T) {
+		// Arrange
+		usages := []*storage.TagUsage{
+			{Tag: "work", Count: 12, LastUsed: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)},
+			{Tag: "home", Count: 1, LastUsed: time.Date(2023, 12, 2, 8, 0, 0, 0, time.UTC)},
+		}
+
+		// Act
+		result := formatTagUsage(usages)
+
+		// Assert
+		expected := `Tag                  Tasks  Last used
+-------------------  -----  ------------
+#work                   12  Jan 15, 2024
+#home                    1  Dec 2, 2023`
+
+		if result != expected {
+			t.Errorf("expected:\n%s\n\ngot:\n%s", expected, result)
+		}
+	})
+}
+
+func TestFormatTagSuggestions(t *testing.T) {
+	t.Run("should show each tag with its usage count", func(t *testing.T) {
+		// Arrange
+		suggestions := []learning.Suggestion{
+			{Value: "work", Count: 45},
+			{Value: "workshop", Count: 1},
+		}
+
+		// Act
+		result := formatTagSuggestions(suggestions)
+
+		// Assert
+		expected := "#work (used 45 times)\n#workshop (used 1 time)"
+		if result != expected {
+			t.Errorf("expected:\n%s\n\ngot:\n%s", expected, result)
+		}
+	})
+}
+
+func TestFormatLearningStatus(t *testing.T) {
+	t.Run("should show state, pattern count and retention", func(t *testing.T) {
+		// Arrange
+		status := &service.LearningStatus{Enabled: true, Patterns: 127, RetentionDays: 90}
+
+		// Act
+		result := formatLearningStatus(status)
+
+		// Assert
+		expected := "Learning: enabled\nPatterns stored: 127\nRetention: 90 days"
+		if result != expected {
+			t.Errorf("expected:\n%s\n\ngot:\n%s", expected, result)
+		}
+	})
+}
+
+func TestFormatDialogueList(t *testing.T) {
+	t.Run("should show each session with its next question", func(t *testing.T) {
+		// Arrange
+		sessions := []*clarification.DialogueSession{
+			{
+				ID:              "dlg-1",
+				Input:           "prepare the thing #work",
+				CurrentQuestion: "When is it due?",
+				History: []clarification.Exchange{
+					{Question: "What do you need to prepare?", Answer: "slides"},
+					{Question: "When is it due?"},
+				},
+				UpdatedAt: time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC),
+			},
+		}
+
+		// Act
+		result := formatDialogueList(sessions)
+
+		// Assert
+		expected := `ID: dlg-1
+Input: prepare the thing #work
+Updated: Jan 15, 2024 10:30 AM (1 answered)
+Next question: When is it due?`
+		if result != expected {
+			t.Errorf("expected:\n%s\n\ngot:\n%s", expected, result)
+		}
+	})
+}
+
+func TestFormatPendingQuestion(t *testing.T) {
+	t.Run("should describe the pending question as JSON", func(t *testing.T) {
+		// Arrange
+		pending := &mode.PendingQuestionError{
+			SessionID: "dlg-1",
+			Input:     "work on the thing",
+			Key:       "q2",
+			Topic:     "deadline",
+			Question:  "When is it due?",
+			Answered:  []clarification.Exchange{{Question: "What is it?", Answer: "slides"}},
+		}
+
+		// Act
+		result := formatPendingQuestion(pending)
+
+		// Assert
+		expected := `{
+  "session_id": "dlg-1",
+  "input": "work on the thing",
+  "questions": [
+    {
+      "key": "q2",
+      "topic": "deadline",
+      "question": "When is it due?"
+    }
+  ],
+  "answered": [
+    {
+      "key": "q1",
+      "question": "What is it?",
+      "answer": "slides"
+    }
+  ]
+}`
+		if result != expected {
+			t.Errorf("expected:\n%s\n\ngot:\n%s", expected, result)
+		}
+	})
+}
+
+func TestFormatModeExplanation(t *testing.T) {
+	t.Run("should show chosen mode, detected mode and reasons", func(t *testing.T) {
+		// Arrange
+		explanation := mode.NewModeDetector().Explain("buy milk")
+
+		// Act
+		result := formatModeExplanation("/t buy milk", explanation)
+
+		// Assert
+		expected := `Mode: talk (chosen with a prefix)
+Auto-detection would choose: quick
+Scores: quick 2.50, talk 0.00, planning 0.00
+Why:
+  +2.00 quick: very short input
+  +0.50 quick: default`
+
+		if result != expected {
+			t.Errorf("expected:\n%s\n\ngot:\n%s", expected, result)
+		}
+	})
+	t.Run("should show the auto-detected mode for input without a prefix", func(t *testing.T) {
+		// Arrange
+		explanation := mode.NewModeDetector().Explain("plan the team offsite")
+
+		// Act
+		result := formatModeExplanation("plan the team offsite", explanation)
+
+		// Assert
+		if !strings.HasPrefix(result, "Mode: planning (auto-detected; use /q, /t or /p to choose)\n") {
+			t.Errorf("expected the auto-detected mode, got:\n%s", result)
+		}
+		if strings.Contains(result, "Auto-detection would choose") {
+			t.Errorf("expected no separate detection line, got:\n%s", result)
+		}
+	})
 }
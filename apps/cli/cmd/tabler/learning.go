@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tennashi/tabler/internal/learning"
+	"github.com/tennashi/tabler/internal/service"
+)
+
+// maxSuggestions limits how many suggestions are shown at once
+const maxSuggestions = 5
+
+func handleSuggestCommand(taskService *service.TaskService, args []string) error {
+	if len(args) == 0 || args[0] != "tags" || len(args) > 2 {
+		return fmt.Errorf("usage: tabler suggest tags [partial]")
+	}
+
+	// Without a partial tag, suggest what is usually added at this time of day
+	if len(args) == 1 {
+		now := time.Now()
+		suggestions, err := taskService.SuggestTagsForTime(now, maxSuggestions)
+		if err != nil {
+			return fmt.Errorf("failed to suggest tags: %w", err)
+		}
+		if len(suggestions) == 0 {
+			fmt.Println("No suggestions yet.")
+			return nil
+		}
+		fmt.Printf("Tags you often use at this time of day (%s):\n", learning.TimeOfDay(now))
+		fmt.Println(formatTagSuggestions(suggestions))
+		return nil
+	}
+
+	suggestions, err := taskService.SuggestTags(args[1], maxSuggestions)
+	if err != nil {
+		return fmt.Errorf("failed to suggest tags: %w", err)
+	}
+	if len(suggestions) == 0 {
+		fmt.Println("No suggestions yet.")
+		return nil
+	}
+
+	fmt.Println(formatTagSuggestions(suggestions))
+	return nil
+}
+
+func handleLearningCommand(taskService *service.TaskService, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: tabler learning <status|export [file]|clear [--yes]>")
+	}
+
+	switch args[0] {
+	case "status":
+		status, err := taskService.LearningStatus()
+		if err != nil {
+			return fmt.Errorf("failed to read learning status: %w", err)
+		}
+		fmt.Println(formatLearningStatus(status))
+		return nil
+	case "export":
+		path := fmt.Sprintf("tabler-patterns-%s.json", time.Now().Format("2006-01-02"))
+		if len(args) > 1 {
+			path = args[1]
+		}
+		return exportLearning(taskService, path)
+	case "clear":
+		confirmed := len(args) > 1 && (args[1] == "--yes" || args[1] == "-y")
+		return clearLearning(taskService, confirmed)
+	default:
+		return fmt.Errorf("unknown learning command: %s", args[0])
+	}
+}
+
+func exportLearning(taskService *service.TaskService, path string) error {
+	patterns, err := taskService.ExportLearning()
+	if err != nil {
+		return fmt.Errorf("failed to export patterns: %w", err)
+	}
+
+	export := struct {
+		ExportedAt time.Time           `json:"exported_at"`
+		Patterns   []*learning.Pattern `json:"patterns"`
+	}{
+		ExportedAt: time.Now().UTC(),
+		Patterns:   patterns,
+	}
+	if export.Patterns == nil {
+		export.Patterns = []*learning.Pattern{}
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	fmt.Printf("Exported %d patterns to: %s\n", len(patterns), path)
+	return nil
+}
+
+func clearLearning(taskService *service.TaskService, confirmed bool) error {
+	// Skip confirmation in non-interactive mode (for tests)
+	if !confirmed && os.Getenv("TABLER_NON_INTERACTIVE") != "1" {
+		if askChoice("Clear all learned patterns? (y/N): ", bufio.NewReader(os.Stdin)) != "y" {
+			fmt.Println("Clear cancelled.")
+			return nil
+		}
+	}
+
+	removed, err := taskService.ClearLearning()
+	if err != nil {
+		return fmt.Errorf("failed to clear patterns: %w", err)
+	}
+
+	fmt.Printf("Cleared %d learned patterns.\n", removed)
+	return nil
+}
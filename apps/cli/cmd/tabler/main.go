@@ -7,15 +7,26 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/tennashi/tabler/internal/claude"
+	"github.com/tennashi/tabler/internal/config"
 	"github.com/tennashi/tabler/internal/metadata"
 	"github.com/tennashi/tabler/internal/mode"
+	"github.com/tennashi/tabler/internal/parser"
 	service "github.com/tennashi/tabler/internal/service"
+	"github.com/tennashi/tabler/internal/task"
 )
 
 func main() {
 	if err := run(); err != nil {
+		// Hand unanswered clarification questions to the calling program
+		var pending *mode.PendingQuestionError
+		if errors.As(err, &pending) {
+			fmt.Println(formatPendingQuestion(pending))
+			os.Exit(exitNeedsAnswers)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
@@ -29,13 +40,9 @@ func run() error {
 	command := os.Args[1]
 
 	// Get data directory
-	dataDir := os.Getenv("TABLER_DATA_DIR")
-	if dataDir == "" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			return fmt.Errorf("failed to get home directory: %w", err)
-		}
-		dataDir = filepath.Join(homeDir, ".tabler")
+	dataDir, err := getDataDir()
+	if err != nil {
+		return err
 	}
 
 	// Ensure data directory exists
@@ -43,6 +50,12 @@ func run() error {
 		return fmt.Errorf("failed to create data directory: %w", err)
 	}
 
+	// Load user settings
+	cfg, err := config.Load(dataDir)
+	if err != nil {
+		return err
+	}
+
 	// Initialize service
 	taskService, err := service.NewTaskService(dataDir)
 	if err != nil {
@@ -51,18 +64,22 @@ func run() error {
 	defer func() {
 		_ = taskService.Close()
 	}()
+	taskService.SetExcludedTags(cfg.Privacy.ExcludedTags)
+	taskService.SetLearning(cfg.Learning)
+	taskService.SetUrgency(cfg.Urgency)
+	taskService.SetTagRules(cfg.Tags.Rules())
 
 	switch command {
 	case "add":
-		return handleAddCommand(taskService, os.Args[2:])
+		return handleAddCommand(taskService, cfg, os.Args[2:])
 	case "list":
-		return handleListCommand(taskService, os.Args[2:])
+		return handleListCommand(taskService, cfg, os.Args[2:])
 	case "done":
-		if len(os.Args) < 3 {
-			return fmt.Errorf("usage: tabler done <task-id>")
+		taskIDs, err := readTaskIDs(os.Args[2:], os.Stdin)
+		if err != nil || len(taskIDs) == 0 {
+			return fmt.Errorf("usage: tabler done <task-id>... | tabler done - (IDs from stdin)")
 		}
-		taskID := os.Args[2]
-		return completeTask(taskService, taskID)
+		return completeTasks(taskService, taskIDs)
 	case "show":
 		if len(os.Args) < 3 {
 			return fmt.Errorf("usage: tabler show <task-id>")
@@ -70,11 +87,29 @@ func run() error {
 		taskID := os.Args[2]
 		return showTask(taskService, taskID)
 	case "delete":
-		if len(os.Args) < 3 {
-			return fmt.Errorf("usage: tabler delete <task-id>")
-		}
-		taskID := os.Args[2]
-		return deleteTask(taskService, taskID)
+		return handleDeleteCommand(taskService, os.Args[2:])
+	case "bulk":
+		return handleBulkCommand(taskService, os.Args[2:])
+	case "tags":
+		return handleTagsCommand(taskService, os.Args[2:])
+	case "depend":
+		return handleDependCommand(taskService, os.Args[2:])
+	case "view":
+		return handleViewCommand(taskService, os.Args[2:])
+	case "next":
+		return handleNextCommand(taskService, os.Args[2:])
+	case "enrich":
+		return handleEnrichCommand(taskService, dataDir, cfg, os.Args[2:])
+	case "suggest":
+		return handleSuggestCommand(taskService, os.Args[2:])
+	case "learning":
+		return handleLearningCommand(taskService, os.Args[2:])
+	case "talk":
+		return handleTalkCommand(taskService, cfg, os.Args[2:])
+	case "ai":
+		return handleAICommand(taskService, os.Args[2:])
+	case "prompts":
+		return handlePromptsCommand(dataDir, os.Args[2:])
 	case "update":
 		if len(os.Args) < 4 {
 			return fmt.Errorf("usage: tabler update <task-id> <new description>")
@@ -87,19 +122,44 @@ func run() error {
 	}
 }
 
-func handleAddCommand(taskService *service.TaskService, args []string) error {
+// getDataDir returns the data directory from TABLER_DATA_DIR or ~/.tabler
+func getDataDir() (string, error) {
+	dataDir := os.Getenv("TABLER_DATA_DIR")
+	if dataDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dataDir = filepath.Join(homeDir, ".tabler")
+	}
+	return dataDir, nil
+}
+
+func handleAddCommand(taskService *service.TaskService, cfg *config.Config, args []string) error {
 	// Parse flags for add command
 	addFlags := flag.NewFlagSet("add", flag.ContinueOnError)
 	useAI := addFlags.Bool("ai", false, "Use AI to extract metadata from task description")
 	useTalk := addFlags.Bool("talk", false, "Use interactive dialogue to clarify vague tasks")
-
-	// Find where the task description starts (after flags)
-	var taskDescStart int
-	for i, arg := range args {
+	noAI := addFlags.Bool("no-ai", false, "Never send this task to the AI provider")
+	suggest := addFlags.Bool("suggest", false, "Suggest metadata from similar past tasks")
+	acceptSuggestions := addFlags.Bool("accept-suggestions", false, "Apply metadata from similar past tasks without asking")
+	explainMode := addFlags.Bool("explain-mode", false, "Show which input mode is used and why")
+	answers := answerFlags{}
+	addFlags.Var(answers, "answer", "Answer a clarification question as key=value (repeatable)")
+	answersFile := addFlags.String("answers", "", "Read clarification answers from a JSON or YAML file")
+	nonInteractive := addFlags.Bool("non-interactive", false, "Print unanswered clarification questions as JSON instead of asking")
+
+	// Find where the task description starts (after flags and their values)
+	taskDescStart := len(args)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
 		if !strings.HasPrefix(arg, "-") {
 			taskDescStart = i
 			break
 		}
+		if name := strings.TrimLeft(arg, "-"); (name == "answer" || name == "answers") && i+1 < len(args) {
+			i++
+		}
 	}
 
 	// Parse flags up to the task description
@@ -111,26 +171,69 @@ func handleAddCommand(taskService *service.TaskService, args []string) error {
 
 	// Get task description
 	if taskDescStart >= len(args) {
-		return fmt.Errorf("usage: tabler add [--ai] [--talk] <task description>")
+		return fmt.Errorf("usage: tabler add [--ai] [--talk] [--no-ai] [--suggest|--accept-suggestions] [--explain-mode] " +
+			"[--answer key=value]... [--answers file] [--non-interactive] <task description>")
 	}
 
 	input := strings.Join(args[taskDescStart:], " ")
 
+	// Scripted answers only make sense for talk-mode clarification
+	if *answersFile != "" || len(answers) > 0 || *nonInteractive {
+		if chosen, _, hasPrefix := mode.ParseModePrefix(input); !*useTalk && (!hasPrefix || chosen != mode.TalkMode) {
+			return fmt.Errorf("--answer, --answers and --non-interactive need --talk or a /talk prefix")
+		}
+	}
+	clarified, err := clarificationAnswers(*answersFile, answers, *nonInteractive)
+	if err != nil {
+		return err
+	}
+
+	// Offer metadata from similar past tasks before anything else sees the input
+	if *suggest || *acceptSuggestions {
+		if *useTalk || strings.HasPrefix(input, "/") {
+			return fmt.Errorf("--suggest cannot be combined with --talk or mode prefixes")
+		}
+		suggested, err := applySimilarSuggestion(taskService, input, *acceptSuggestions)
+		if err != nil {
+			return err
+		}
+		input = suggested
+	}
+
+	if *explainMode {
+		explained := input
+		if *useTalk {
+			explained = "/talk " + input
+		}
+		explanation := newModeDetection(taskService, cfg).ExplainMode(explained)
+		fmt.Println(formatModeExplanation(explained, explanation))
+	}
+
+	// Private tasks only use the local parser
+	if *noAI {
+		if *useAI || *useTalk || strings.HasPrefix(input, "/") {
+			return fmt.Errorf("--no-ai cannot be combined with --ai, --talk or mode prefixes")
+		}
+		return addPrivateTask(taskService, input)
+	}
+
 	// If --ai flag is set, create a new service with metadata extraction
 	if *useAI {
 		// Get data directory from the existing service
-		dataDir := os.Getenv("TABLER_DATA_DIR")
-		if dataDir == "" {
-			homeDir, err := os.UserHomeDir()
-			if err != nil {
-				return fmt.Errorf("failed to get home directory: %w", err)
-			}
-			dataDir = filepath.Join(homeDir, ".tabler")
+		dataDir, err := getDataDir()
+		if err != nil {
+			return err
 		}
 
-		// Create metadata service
-		claude := metadata.NewClaudeClient()
-		metadataService := metadata.NewService(claude)
+		// Create metadata service, honoring record/replay settings
+		library := newPromptLibrary(dataDir)
+		provider, err := newAIProvider(taskService, metadata.NewSubprocessProvider(), cfg.Privacy, library)
+		if err != nil {
+			return err
+		}
+		claudeClient := metadata.NewClaudeClientWithProvider(provider)
+		claudeClient.SetPrompts(library)
+		metadataService := metadata.NewService(claudeClient)
 
 		// Create new task service with metadata
 		aiTaskService, err := service.NewTaskServiceWithMetadata(dataDir, metadataService)
@@ -140,34 +243,64 @@ func handleAddCommand(taskService *service.TaskService, args []string) error {
 		defer func() {
 			_ = aiTaskService.Close()
 		}()
+		aiTaskService.SetExcludedTags(cfg.Privacy.ExcludedTags)
+		aiTaskService.SetLearning(cfg.Learning)
+		aiTaskService.SetTagRules(cfg.Tags.Rules())
 
 		// Use the AI-enhanced service
 		taskService = aiTaskService
 
-		fmt.Println("📋 Using AI to extract metadata...")
+		// Keep stdout for the task ID and pending-question JSON
+		fmt.Fprintln(os.Stderr, "📋 Using AI to extract metadata...")
 	}
 
 	// Check if talk mode is forced via flag
 	if *useTalk {
 		// Prepend /talk to use talk mode with clarification
-		return addTaskWithMode(taskService, "/talk "+input)
+		return addTaskWithMode(taskService, cfg, "/talk "+input, clarified)
 	}
 
 	// Check if mode prefixes are used
 	if strings.HasPrefix(input, "/") {
 		// Use mode system for inputs with mode prefixes
-		return addTaskWithMode(taskService, input)
+		return addTaskWithMode(taskService, cfg, input, clarified)
 	}
 
-	// For backward compatibility, use original method without prefix
+	// Auto-detection, learned choices included, picks the mode for unprefixed input.
+	// Quick tasks, AI metadata extraction and tasks excluded from AI keep the local parser.
+	if !*useAI && newModeDetection(taskService, cfg).DetectMode(input) != mode.QuickMode {
+		if _, excluded := taskService.ExcludedTag(parser.Parse(input).Tags); !excluded {
+			return addTaskWithMode(taskService, cfg, input, clarified)
+		}
+	}
 	return addTask(taskService, input)
 }
 
-func addTaskWithMode(service *service.TaskService, input string) error {
-	// Create mode manager with enhanced features
-	modeManager := mode.NewManagerBuilder().
-		WithClarification().
+// newModeDetection creates a mode manager that only detects and explains modes
+func newModeDetection(taskService *service.TaskService, cfg *config.Config) *mode.ModeManager {
+	return mode.NewManagerBuilder().
+		WithHistory(taskService).
+		WithLanguage(cfg.InputLanguage()).
 		Build()
+}
+
+func addTaskWithMode(service *service.TaskService, cfg *config.Config, input string, answers map[string]string) error {
+	// Modes may send the whole input to the AI provider
+	if tag, excluded := service.ExcludedTag(parser.Parse(input).Tags); excluded {
+		return fmt.Errorf("tag #%s is excluded from AI; add the task without a mode prefix", tag)
+	}
+
+	modeManager, err := newModeManager(service, cfg, answers)
+	if err != nil {
+		return err
+	}
+
+	// Modes see the task without its inline #tags, @deadline and !priority
+	_, text, _ := mode.ParseModePrefix(input)
+	inline := parser.Parse(text)
+	if inline.Title != "" {
+		input = strings.TrimSuffix(input, text) + inline.Title
+	}
 
 	// Process task with mode system
 	ctx := context.Background()
@@ -175,6 +308,7 @@ func addTaskWithMode(service *service.TaskService, input string) error {
 	if err != nil {
 		return fmt.Errorf("failed to process task: %w", err)
 	}
+	applyInlineMetadata(task, inline)
 
 	// Store task
 	taskID, err := service.StoreTask(task)
@@ -186,6 +320,52 @@ func addTaskWithMode(service *service.TaskService, input string) error {
 	return nil
 }
 
+// applyInlineMetadata adds the inline tags to t and fills in the deadline and
+// priority its mode left unset
+func applyInlineMetadata(t *task.Task, inline *parser.ParseResult) {
+	for _, tag := range inline.Tags {
+		if !slices.Contains(t.Tags, tag) {
+			t.Tags = append(t.Tags, tag)
+		}
+	}
+	if t.Deadline.IsZero() && inline.Deadline != nil {
+		t.Deadline = *inline.Deadline
+	}
+	if t.Priority == 0 {
+		t.Priority = inline.Priority
+	}
+}
+
+// newModeManager creates a mode manager with clarification, resumable talk dialogues
+// and plan decomposition. Non-nil answers make clarification non-interactive.
+// Plans come back unsaved so addTaskWithMode stores them like any other task.
+func newModeManager(service *service.TaskService, cfg *config.Config, answers map[string]string) (*mode.ModeManager, error) {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// Honor record/replay settings, redact private text and meter AI calls
+	library := newPromptLibrary(dataDir)
+	provider, err := newAIProvider(service, claude.NewClient(), cfg.Privacy, library)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create mode manager with enhanced features
+	return mode.NewManagerBuilder().
+		WithProvider(provider).
+		WithHistory(service).
+		WithDialogueStore(service).
+		WithAnswers(answers).
+		WithLanguage(cfg.InputLanguage()).
+		WithPrompts(library).
+		WithClarification().
+		WithDecomposition(nil).
+		WithPlanningDepth(cfg.Planning.MaxDepth).
+		Build(), nil
+}
+
 func addTask(service *service.TaskService, input string) error {
 	taskID, err := service.CreateTaskFromInput(input)
 	if err != nil {
@@ -199,8 +379,23 @@ func addTask(service *service.TaskService, input string) error {
 	return nil
 }
 
-func handleListCommand(taskService *service.TaskService, args []string) error {
-	filter := &service.FilterOptions{}
+func addPrivateTask(service *service.TaskService, input string) error {
+	taskID, err := service.CreatePrivateTaskFromInput(input)
+	if err != nil {
+		if strings.Contains(err.Error(), "task title cannot be empty") {
+			return errors.New(formatValidationError(ErrEmptyTitle))
+		}
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+
+	fmt.Printf("Task created: %s\n", taskID)
+	return nil
+}
+
+func handleListCommand(taskService *service.TaskService, cfg *config.Config, args []string) error {
+	filter := &service.FilterOptions{Sort: cfg.List.Sort}
+	output := outputTable
+	var terms []string
 
 	// Parse flags
 	i := 0
@@ -212,41 +407,62 @@ func handleListCommand(taskService *service.TaskService, args []string) error {
 			}
 			filter.Tag = args[i+1]
 			i += 2
+		case "--unblocked":
+			filter.HideBlocked = true
+			i++
+		case "--sort":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--sort requires a value such as deadline,-priority")
+			}
+			filter.Sort = args[i+1]
+			i += 2
+		case "--output":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--output requires one of: %s", strings.Join(outputFormats, ", "))
+			}
+			output = args[i+1]
+			i += 2
 		default:
-			return fmt.Errorf("unknown flag: %s", args[i])
+			if strings.HasPrefix(args[i], "--") {
+				return fmt.Errorf("unknown flag: %s", args[i])
+			}
+			// Anything else is part of the filter expression
+			terms = append(terms, args[i])
+			i++
 		}
 	}
+	filter.Query = strings.Join(terms, " ")
 
-	return listTasks(taskService, filter)
+	return listTasks(taskService, filter, output)
 }
 
-func listTasks(taskService *service.TaskService, filter *service.FilterOptions) error {
+func listTasks(taskService *service.TaskService, filter *service.FilterOptions, output string) error {
+	if !slices.Contains(outputFormats, output) {
+		return fmt.Errorf("unknown output format %q (available: %s)", output, strings.Join(outputFormats, ", "))
+	}
+
 	taskItems, err := taskService.ListTasks(filter)
 	if err != nil {
 		return fmt.Errorf("failed to list tasks: %w", err)
 	}
 
-	if len(taskItems) == 0 {
-		fmt.Println("No tasks found.")
-		return nil
-	}
-
-	// Display tasks in table format
-	fmt.Println(formatTasksAsTable(taskItems))
-
-	return nil
-}
-
-func completeTask(service *service.TaskService, taskID string) error {
-	err := service.CompleteTask(taskID)
-	if err != nil {
-		if isNotFoundError(err.Error()) {
-			return errors.New(formatTaskError(ErrTaskNotFound, taskID))
+	switch output {
+	case outputJSON:
+		fmt.Println(formatTasksAsJSON(taskItems))
+	case outputIDs:
+		for _, item := range taskItems {
+			fmt.Println(item.Task.ID)
+		}
+	default:
+		if len(taskItems) == 0 {
+			fmt.Println("No tasks found.")
+			return nil
 		}
-		return fmt.Errorf("failed to complete task: %w", err)
+
+		// Display tasks in table format
+		fmt.Println(formatTasksAsTable(taskItems))
 	}
 
-	fmt.Printf("Task completed: %s\n", taskID)
 	return nil
 }
 
@@ -259,37 +475,17 @@ func showTask(service *service.TaskService, taskID string) error {
 		return fmt.Errorf("failed to get task: %w", err)
 	}
 
-	// Display formatted task details
-	fmt.Println(formatTaskDetails(task, tags))
-
-	return nil
-}
-
-func deleteTask(service *service.TaskService, taskID string) error {
-	// Get task details first to show title in confirmation
-	task, _, err := service.GetTask(taskID)
+	blockers, err := service.Blockers(taskID)
 	if err != nil {
-		if isNotFoundError(err.Error()) {
-			return errors.New(formatTaskError(ErrTaskNotFound, taskID))
-		}
-		return fmt.Errorf("failed to get task: %w", err)
+		return fmt.Errorf("failed to get blockers: %w", err)
 	}
 
-	// Skip confirmation in non-interactive mode (for tests)
-	if os.Getenv("TABLER_NON_INTERACTIVE") != "1" {
-		// Confirm deletion
-		if !confirmDeletion(task.Title, os.Stdin) {
-			fmt.Println("Deletion cancelled.")
-			return nil
-		}
-	}
-
-	err = service.DeleteTask(taskID)
-	if err != nil {
-		return fmt.Errorf("failed to delete task: %w", err)
+	// Display formatted task details
+	fmt.Println(formatTaskDetails(task, tags))
+	if len(blockers) > 0 {
+		fmt.Println(formatBlockers(blockers))
 	}
 
-	fmt.Printf("Task deleted: %s\n", taskID)
 	return nil
 }
 
@@ -2,13 +2,18 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/tennashi/tabler/internal/claude"
 	"github.com/tennashi/tabler/internal/service"
+	"github.com/tennashi/tabler/internal/task"
 )
 
 // captureOutput captures stdout during function execution
@@ -36,6 +41,50 @@ func captureOutput(t *testing.T, fn func() error) (string, error) {
 }
 
 func TestCLI(t *testing.T) {
+	// Auto-detected modes may call the AI provider; only recorded responses are used
+	t.Setenv(claude.ProviderModeEnv, "replay")
+	t.Setenv(claude.FixtureDirEnv, filepath.Join("testdata", "ai"))
+
+	// createTasks stores tasks from inputs and returns their IDs
+	createTasks := func(t *testing.T, tmpDir string, inputs ...string) []string {
+		t.Helper()
+		taskService, err := service.NewTaskService(tmpDir)
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		defer func() {
+			_ = taskService.Close()
+		}()
+
+		var ids []string
+		for _, input := range inputs {
+			id, err := taskService.CreateTaskFromInput(input)
+			if err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	// setStdin makes os.Stdin read content for the rest of the test
+	setStdin := func(t *testing.T, content string) {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "stdin")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		original := os.Stdin
+		os.Stdin = file
+		t.Cleanup(func() {
+			os.Stdin = original
+			_ = file.Close()
+		})
+	}
+
 	t.Run("add command", func(t *testing.T) {
 		t.Run("should create task from input", func(t *testing.T) {
 			// Arrange
@@ -66,6 +115,88 @@ func TestCLI(t *testing.T) {
 		})
 	})
 
+	t.Run("add command in planning mode", func(t *testing.T) {
+		t.Run("should store the selected subtasks of a decomposed plan", func(t *testing.T) {
+			// Arrange
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+			config := `{"planning": {"max_depth": 1}}`
+			if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			setStdin(t, "1,3\n")
+
+			os.Args = []string{"tabler", "add", "/plan organize team offsite"}
+
+			// Act
+			output, err := captureOutput(t, run)
+			// Assert
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			if strings.Contains(output, "Break it down further?") {
+				t.Errorf("expected max_depth 1 to keep the plan flat, got:\n%s", output)
+			}
+
+			taskService, err := service.NewTaskService(tmpDir)
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			defer func() {
+				_ = taskService.Close()
+			}()
+			tasks, err := taskService.ListTasks(nil)
+			if err != nil {
+				t.Fatalf("failed to list tasks: %v", err)
+			}
+			var titles []string
+			for _, item := range tasks {
+				titles = append(titles, item.Task.Title)
+			}
+			expected := []string{"Pick a date and venue", "Plan the agenda and activities"}
+			for _, title := range expected {
+				if !slices.Contains(titles, title) {
+					t.Errorf("expected subtask %q to be stored, got %v", title, titles)
+				}
+			}
+			if len(titles) != 3 || slices.Contains(titles, "Send invitations to the team") {
+				t.Errorf("expected the plan and two selected subtasks stored once, got %v", titles)
+			}
+		})
+	})
+
+	t.Run("add command with auto-detected modes", func(t *testing.T) {
+		t.Run("should route unprefixed input by the modes chosen before", func(t *testing.T) {
+			// Arrange
+			t.Setenv("TABLER_DATA_DIR", t.TempDir())
+			input := "organize garage shelves"
+			os.Args = []string{"tabler", "add", input}
+			before, err := captureOutput(t, run)
+			if err != nil {
+				t.Fatalf("failed to add task: %v", err)
+			}
+			os.Args = []string{"tabler", "add", "/q " + input}
+			if _, err := captureOutput(t, run); err != nil {
+				t.Fatalf("failed to add task: %v", err)
+			}
+
+			os.Args = []string{"tabler", "add", "--explain-mode", input}
+
+			// Act
+			after, err := captureOutput(t, run)
+			// Assert
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			if !strings.Contains(before, "Planning mode") {
+				t.Errorf("expected planning mode before any choice, got:\n%s", before)
+			}
+			if strings.Contains(after, "Planning mode") || !strings.Contains(after, "Mode: quick (auto-detected") {
+				t.Errorf("expected the quick choice to route the task, got:\n%s", after)
+			}
+		})
+	})
+
 	t.Run("list command", func(t *testing.T) {
 		t.Run("should list all tasks", func(t *testing.T) {
 			// Arrange
@@ -96,6 +227,99 @@ func TestCLI(t *testing.T) {
 			}
 		})
 
+		t.Run("should sort tasks with --sort and the configured default", func(t *testing.T) {
+			// Arrange
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+			for _, input := range []string{"Low task !", "High task !!!"} {
+				os.Args = []string{"tabler", "add", input}
+				if _, err := captureOutput(t, run); err != nil {
+					t.Fatalf("failed to create task: %v", err)
+				}
+			}
+			if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(`{"list": {"sort": "priority"}}`), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			order := func(output string) bool {
+				return strings.Index(output, "High task") < strings.Index(output, "Low task")
+			}
+
+			// Act
+			os.Args = []string{"tabler", "list"}
+			byDefault, err := captureOutput(t, run)
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			os.Args = []string{"tabler", "list", "--sort", "-priority"}
+			bySort, err := captureOutput(t, run)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			if order(byDefault) {
+				t.Errorf("expected the configured ascending priority order, got:\n%s", byDefault)
+			}
+			if !order(bySort) {
+				t.Errorf("expected descending priority order, got:\n%s", bySort)
+			}
+		})
+
+		t.Run("should filter tasks with a query expression", func(t *testing.T) {
+			// Arrange
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+			for _, input := range []string{"Write report #work !!", "Tidy desk #work", "Water plants #home !!!"} {
+				os.Args = []string{"tabler", "add", input}
+				if _, err := captureOutput(t, run); err != nil {
+					t.Fatalf("failed to create task: %v", err)
+				}
+			}
+			os.Args = []string{"tabler", "list", "tag:work and (priority>=2 or title:desk)", "--sort", "title"}
+
+			// Act
+			output, err := captureOutput(t, run)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			if !strings.Contains(output, "Write report") || !strings.Contains(output, "Tidy desk") {
+				t.Errorf("expected both work tasks, got:\n%s", output)
+			}
+			if strings.Contains(output, "Water plants") {
+				t.Errorf("expected the home task to be filtered out, got:\n%s", output)
+			}
+		})
+
+		t.Run("should report invalid query expressions", func(t *testing.T) {
+			// Arrange
+			t.Setenv("TABLER_DATA_DIR", t.TempDir())
+			os.Args = []string{"tabler", "list", "tag:work and"}
+
+			// Act
+			_, err := captureOutput(t, run)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), "invalid query") {
+				t.Errorf("expected invalid query error, got %v", err)
+			}
+		})
+
+		t.Run("should reject unknown sort fields", func(t *testing.T) {
+			// Arrange
+			t.Setenv("TABLER_DATA_DIR", t.TempDir())
+			os.Args = []string{"tabler", "list", "--sort", "size"}
+
+			// Act
+			_, err := captureOutput(t, run)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), "unknown sort field") {
+				t.Errorf("expected unknown sort field error, got %v", err)
+			}
+		})
+
 		t.Run("should filter tasks by tag with --tag flag", func(t *testing.T) {
 			// Arrange
 			tmpDir := t.TempDir()
@@ -191,6 +415,147 @@ func TestCLI(t *testing.T) {
 		})
 	})
 
+	t.Run("done command with several tasks", func(t *testing.T) {
+		t.Run("should complete every task given as arguments or on stdin", func(t *testing.T) {
+			tests := []struct {
+				name  string
+				stdin bool
+			}{
+				{name: "arguments"},
+				{name: "stdin", stdin: true},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					// Arrange
+					tmpDir := t.TempDir()
+					t.Setenv("TABLER_DATA_DIR", tmpDir)
+					ids := createTasks(t, tmpDir, "Archive mail", "Clean desk")
+					os.Args = append([]string{"tabler", "done"}, ids...)
+					if tt.stdin {
+						setStdin(t, strings.Join(ids, "\n")+"\n")
+						os.Args = []string{"tabler", "done", "-"}
+					}
+
+					// Act
+					output, err := captureOutput(t, run)
+
+					// Assert
+					if err != nil {
+						t.Fatalf("run() returned error: %v", err)
+					}
+					for _, id := range ids {
+						if !strings.Contains(output, "Task completed: "+id) {
+							t.Errorf("expected %s to be completed, got:\n%s", id, output)
+						}
+					}
+				})
+			}
+		})
+
+		t.Run("should complete nothing when an ID is unknown", func(t *testing.T) {
+			// Arrange
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+			ids := createTasks(t, tmpDir, "Archive mail")
+			os.Args = []string{"tabler", "done", ids[0], "missing"}
+
+			// Act
+			_, err := captureOutput(t, run)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), "Task not found: missing") {
+				t.Fatalf("expected a not found error, got %v", err)
+			}
+			taskService, err := service.NewTaskService(tmpDir)
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			defer func() {
+				_ = taskService.Close()
+			}()
+			if pending, _, _ := taskService.GetTask(ids[0]); pending.Completed {
+				t.Error("expected the known task to stay pending")
+			}
+		})
+	})
+
+	t.Run("bulk command", func(t *testing.T) {
+		t.Run("should apply an action to every matching task", func(t *testing.T) {
+			// Arrange
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+			ids := createTasks(t, tmpDir, "Archive mail #old", "Clean desk #old", "Plan trip #travel")
+			os.Args = []string{"tabler", "bulk", "--filter", "tag:old", "--yes", "tag", "+cleanup", "-old"}
+			if _, err := captureOutput(t, run); err != nil {
+				t.Fatalf("failed to retag: %v", err)
+			}
+			os.Args = []string{"tabler", "bulk", "--filter", "tag:cleanup", "--yes", "priority", "2"}
+
+			// Act
+			output, err := captureOutput(t, run)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			if !strings.Contains(output, "Set priority 2 on: 2 tasks") {
+				t.Errorf("expected a summary, got:\n%s", output)
+			}
+			taskService, err := service.NewTaskService(tmpDir)
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			defer func() {
+				_ = taskService.Close()
+			}()
+			for i, id := range ids {
+				updated, tags, err := taskService.GetTask(id)
+				if err != nil {
+					t.Fatalf("failed to get task: %v", err)
+				}
+				retagged := i < 2
+				if retagged && (updated.Priority != 2 || len(tags) != 1 || tags[0] != "cleanup") {
+					t.Errorf("expected %q to be retagged with priority 2, got %v %d", updated.Title, tags, updated.Priority)
+				}
+				if !retagged && updated.Priority != 0 {
+					t.Errorf("expected %q to be unchanged", updated.Title)
+				}
+			}
+		})
+
+		t.Run("should change nothing when the confirmation is declined", func(t *testing.T) {
+			// Arrange
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+			t.Setenv("TABLER_NON_INTERACTIVE", "")
+			ids := createTasks(t, tmpDir, "Archive mail #old")
+			setStdin(t, "n\n")
+			os.Args = []string{"tabler", "bulk", "--filter", "tag:old", "delete"}
+
+			// Act
+			output, err := captureOutput(t, run)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			if !strings.Contains(output, "Archive mail") || !strings.Contains(output, "Delete 1 tasks?") {
+				t.Errorf("expected a preview and a confirmation, got:\n%s", output)
+			}
+			taskService, err := service.NewTaskService(tmpDir)
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			defer func() {
+				_ = taskService.Close()
+			}()
+			if _, _, err := taskService.GetTask(ids[0]); err != nil {
+				t.Errorf("expected the task to be kept, got %v", err)
+			}
+		})
+	})
+
 	t.Run("show command", func(t *testing.T) {
 		t.Run("should show task details", func(t *testing.T) {
 			// Arrange
@@ -264,6 +629,322 @@ func TestCLI(t *testing.T) {
 		})
 	})
 
+	t.Run("next command", func(t *testing.T) {
+		t.Run("should recommend the first unblocked subtask", func(t *testing.T) {
+			// Arrange
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+
+			taskService, err := service.NewTaskService(tmpDir)
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			now := time.Now()
+			first := &task.Task{ID: "first", Title: "Pick a date", Order: 1, CreatedAt: now, UpdatedAt: now}
+			second := &task.Task{ID: "second", Title: "Send invitations", Order: 2, DependsOn: []string{first.ID},
+				CreatedAt: now, UpdatedAt: now}
+			plan := &task.Task{ID: "plan", Title: "Organize offsite", Subtasks: []*task.Task{first, second},
+				CreatedAt: now, UpdatedAt: now}
+			if _, err := taskService.StoreTask(plan); err != nil {
+				t.Fatalf("failed to store plan: %v", err)
+			}
+			_ = taskService.Close()
+
+			os.Args = []string{"tabler", "next"}
+
+			// Act
+			output, err := captureOutput(t, run)
+			// Assert
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			if !strings.Contains(output, "Task: Pick a date") {
+				t.Errorf("expected the first subtask to be recommended, got:\n%s", output)
+			}
+		})
+
+		t.Run("should rank several tasks with an explanation", func(t *testing.T) {
+			// Arrange
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+
+			taskService, err := service.NewTaskService(tmpDir)
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			for _, input := range []string{"Water plants", "Fix outage !!!"} {
+				if _, err := taskService.CreateTaskFromInput(input); err != nil {
+					t.Fatalf("failed to create task: %v", err)
+				}
+			}
+			_ = taskService.Close()
+
+			os.Args = []string{"tabler", "next", "-n", "2", "--explain"}
+
+			// Act
+			output, err := captureOutput(t, run)
+			// Assert
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			first := strings.Index(output, "Fix outage")
+			second := strings.Index(output, "Water plants")
+			if first < 0 || second < 0 || first > second {
+				t.Errorf("expected the urgent task first, got:\n%s", output)
+			}
+			if !strings.Contains(output, "priority  !!!") {
+				t.Errorf("expected the score breakdown, got:\n%s", output)
+			}
+		})
+	})
+
+	t.Run("tags command", func(t *testing.T) {
+		t.Run("should rename and merge tags", func(t *testing.T) {
+			// Arrange
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+			ids := createTasks(t, tmpDir, "Write report #wrk", "Call client #job", "Water plants #home")
+			for _, args := range [][]string{
+				{"tabler", "tags", "rename", "wrk", "work"},
+				{"tabler", "tags", "merge", "work", "job", "--into", "career"},
+			} {
+				os.Args = args
+				if _, err := captureOutput(t, run); err != nil {
+					t.Fatalf("%v returned error: %v", args, err)
+				}
+			}
+			os.Args = []string{"tabler", "tags"}
+
+			// Act
+			output, err := captureOutput(t, run)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			if !strings.Contains(output, "#career                  2") || strings.Contains(output, "#job") {
+				t.Errorf("expected merged tag counts, got:\n%s", output)
+			}
+			taskService, err := service.NewTaskService(tmpDir)
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			defer func() {
+				_ = taskService.Close()
+			}()
+			if _, tags, _ := taskService.GetTask(ids[0]); len(tags) != 1 || tags[0] != "career" {
+				t.Errorf("expected the renamed task to be tagged career, got %v", tags)
+			}
+		})
+
+		t.Run("should report the tags and tasks a merge changed", func(t *testing.T) {
+			// Arrange
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+			createTasks(t, tmpDir, "Write report #work", "Call client #job", "Plan week #job #work")
+			os.Args = []string{"tabler", "tags", "merge", "work", "job", "missing", "--into", "job"}
+
+			// Act
+			output, err := captureOutput(t, run)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			if !strings.Contains(output, "Merged 1 tag into #job on 2 tasks") {
+				t.Errorf("expected the changed counts, got:\n%s", output)
+			}
+		})
+
+		t.Run("should refuse to rename onto an existing tag", func(t *testing.T) {
+			// Arrange
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+			createTasks(t, tmpDir, "Write report #work", "Call client #job")
+			os.Args = []string{"tabler", "tags", "rename", "job", "work"}
+
+			// Act
+			_, err := captureOutput(t, run)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), "tabler tags merge") {
+				t.Errorf("expected a hint to merge instead, got %v", err)
+			}
+		})
+
+		t.Run("should normalize tags and match descendants", func(t *testing.T) {
+			// Arrange
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+			if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(`{"tags": {"aliases": {"job": "work"}}}`), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			for _, input := range []string{"Fix bug #Work,", "Call client #Job/ClientA", "Book room #workshop"} {
+				os.Args = []string{"tabler", "add", input}
+				if _, err := captureOutput(t, run); err != nil {
+					t.Fatalf("add %q returned error: %v", input, err)
+				}
+			}
+			os.Args = []string{"tabler", "list", "tag:work"}
+
+			// Act
+			output, err := captureOutput(t, run)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			if !strings.Contains(output, "Fix bug") || !strings.Contains(output, "Call client") {
+				t.Errorf("expected work and its descendants, got:\n%s", output)
+			}
+			if strings.Contains(output, "Book room") {
+				t.Errorf("expected #workshop not to match work, got:\n%s", output)
+			}
+			os.Args = []string{"tabler", "tags"}
+			tags, err := captureOutput(t, run)
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			if !strings.Contains(tags, "#work/clienta") || strings.Contains(tags, "Work") {
+				t.Errorf("expected normalized tags, got:\n%s", tags)
+			}
+		})
+	})
+
+	t.Run("view command", func(t *testing.T) {
+		t.Run("should save, list and show a view", func(t *testing.T) {
+			// Arrange
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+			for _, input := range []string{"Plan sprint #sprint !!", "Fix flaky test #sprint", "Water plants #home"} {
+				os.Args = []string{"tabler", "add", input}
+				if _, err := captureOutput(t, run); err != nil {
+					t.Fatalf("failed to create task: %v", err)
+				}
+			}
+			os.Args = []string{"tabler", "view", "save", "this-sprint", "tag:sprint", "and", "not", "done",
+				"--sort", "-priority", "--output", "json"}
+			if _, err := captureOutput(t, run); err != nil {
+				t.Fatalf("failed to save view: %v", err)
+			}
+
+			os.Args = []string{"tabler", "view", "list"}
+			listed, err := captureOutput(t, run)
+			if err != nil {
+				t.Fatalf("failed to list views: %v", err)
+			}
+
+			os.Args = []string{"tabler", "view", "this-sprint"}
+
+			// Act
+			output, err := captureOutput(t, run)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("run() returned error: %v", err)
+			}
+			if !strings.Contains(listed, "this-sprint") || !strings.Contains(listed, "tag:sprint and not done") {
+				t.Errorf("expected the saved view to be listed, got:\n%s", listed)
+			}
+			var tasks []struct {
+				Title string `json:"title"`
+			}
+			if err := json.Unmarshal([]byte(output), &tasks); err != nil {
+				t.Fatalf("expected JSON output, got:\n%s", output)
+			}
+			if len(tasks) != 2 || tasks[0].Title != "Plan sprint" {
+				t.Errorf("expected the sprint tasks by priority, got %+v", tasks)
+			}
+		})
+
+		t.Run("should reject views with an invalid filter", func(t *testing.T) {
+			// Arrange
+			t.Setenv("TABLER_DATA_DIR", t.TempDir())
+			os.Args = []string{"tabler", "view", "save", "broken", "tag:work and"}
+
+			// Act
+			_, err := captureOutput(t, run)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), "invalid query") {
+				t.Errorf("expected invalid query error, got %v", err)
+			}
+		})
+	})
+
+	t.Run("depend command", func(t *testing.T) {
+		setup := func(t *testing.T) (review, deploy string) {
+			tmpDir := t.TempDir()
+			t.Setenv("TABLER_DATA_DIR", tmpDir)
+
+			taskService, err := service.NewTaskService(tmpDir)
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			defer func() {
+				_ = taskService.Close()
+			}()
+			review, err = taskService.CreateTaskFromInput("Code review")
+			if err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			deploy, err = taskService.CreateTaskFromInput("Deploy")
+			if err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			return review, deploy
+		}
+
+		t.Run("should report tasks unblocked by done", func(t *testing.T) {
+			// Arrange
+			review, deploy := setup(t)
+			os.Args = []string{"tabler", "depend", deploy, "--on", review}
+			if _, err := captureOutput(t, run); err != nil {
+				t.Fatalf("depend returned error: %v", err)
+			}
+
+			os.Args = []string{"tabler", "list", "--unblocked"}
+			output, err := captureOutput(t, run)
+			if err != nil {
+				t.Fatalf("list returned error: %v", err)
+			}
+			if strings.Contains(output, "Deploy") {
+				t.Errorf("expected blocked task to be hidden, got:\n%s", output)
+			}
+
+			os.Args = []string{"tabler", "done", review}
+
+			// Act
+			output, err = captureOutput(t, run)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("done returned error: %v", err)
+			}
+			if !strings.Contains(output, "Now actionable: Deploy ("+deploy+")") {
+				t.Errorf("expected deploy to be reported as actionable, got:\n%s", output)
+			}
+		})
+
+		t.Run("should reject a dependency cycle", func(t *testing.T) {
+			// Arrange
+			review, deploy := setup(t)
+			os.Args = []string{"tabler", "depend", deploy, "--on", review}
+			if _, err := captureOutput(t, run); err != nil {
+				t.Fatalf("depend returned error: %v", err)
+			}
+			os.Args = []string{"tabler", "depend", review, "--on", deploy}
+
+			// Act
+			_, err := captureOutput(t, run)
+
+			// Assert
+			if err == nil || !strings.Contains(err.Error(), "Code review -> Deploy -> Code review") {
+				t.Errorf("expected cycle error naming the tasks, got %v", err)
+			}
+		})
+	})
+
 	t.Run("update command", func(t *testing.T) {
 		t.Run("should update task", func(t *testing.T) {
 			// Arrange
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/tennashi/tabler/internal/service"
+)
+
+const nextUsage = "usage: tabler next [-n <count>] [--explain] [--waiting]"
+
+func handleNextCommand(taskService *service.TaskService, args []string) error {
+	count := 1
+	explain := false
+	includeWaiting := false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-n":
+			if i+1 >= len(args) {
+				return errors.New(nextUsage)
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n < 1 {
+				return fmt.Errorf("-n needs a positive number, got %q", args[i+1])
+			}
+			count = n
+			i++
+		case "--explain":
+			explain = true
+		case "--waiting":
+			// List blocked tasks and parents with open subtasks after the actionable ones
+			includeWaiting = true
+		default:
+			return errors.New(nextUsage)
+		}
+	}
+
+	recommendations, err := taskService.RecommendTasks(count, includeWaiting)
+	if errors.Is(err, service.ErrNothingActionable) {
+		fmt.Println("Nothing to do next: there are no actionable tasks.")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to find the next task: %w", err)
+	}
+
+	fmt.Println("Next up:")
+	if count == 1 && !explain {
+		fmt.Println(formatTaskDetails(recommendations[0].Task, recommendations[0].Tags))
+		return nil
+	}
+	fmt.Println(formatRecommendations(recommendations, explain))
+	return nil
+}
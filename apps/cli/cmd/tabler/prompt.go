@@ -8,7 +8,12 @@ import (
 )
 
 func confirmDeletion(taskTitle string, reader io.Reader) bool {
-	fmt.Printf("Delete task \"%s\"? (y/N): ", taskTitle)
+	return confirmAction(fmt.Sprintf("Delete task \"%s\"?", taskTitle), reader)
+}
+
+// confirmAction asks a yes/no question that defaults to no
+func confirmAction(question string, reader io.Reader) bool {
+	fmt.Printf("%s (y/N): ", question)
 
 	scanner := bufio.NewScanner(reader)
 	if scanner.Scan() {
@@ -18,3 +23,15 @@ func confirmDeletion(taskTitle string, reader io.Reader) bool {
 
 	return false
 }
+
+// askChoice shows a question and returns the lowercased first answer word
+func askChoice(question string, reader *bufio.Reader) string {
+	fmt.Print(question)
+
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return ""
+	}
+
+	return strings.ToLower(strings.TrimSpace(line))
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tennashi/tabler/internal/prompts"
+)
+
+// promptsDirName is the data directory subfolder holding prompt overrides
+const promptsDirName = "prompts"
+
+func newPromptLibrary(dataDir string) *prompts.Library {
+	return prompts.NewLibrary(filepath.Join(dataDir, promptsDirName))
+}
+
+func handlePromptsCommand(dataDir string, args []string) error {
+	if len(args) == 0 || args[0] == "list" {
+		return listPrompts(dataDir)
+	}
+
+	switch args[0] {
+	case "export":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: tabler prompts export <name>")
+		}
+		return exportPrompt(dataDir, args[1])
+	default:
+		return fmt.Errorf("usage: tabler prompts [list|export <name>]")
+	}
+}
+
+func listPrompts(dataDir string) error {
+	infos, err := newPromptLibrary(dataDir).List()
+	if err != nil {
+		return fmt.Errorf("failed to list prompts: %w", err)
+	}
+
+	fmt.Println(formatPromptList(infos))
+	return nil
+}
+
+// exportPrompt copies an embedded default into the override directory for editing
+func exportPrompt(dataDir, name string) error {
+	text, err := prompts.DefaultText(name)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(dataDir, promptsDirName)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create prompts directory: %w", err)
+	}
+
+	path := filepath.Join(dir, name+".tmpl")
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600) // #nosec G304 - path is built from known prompt names
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("prompt override already exists: %s", path)
+		}
+		return fmt.Errorf("failed to export prompt: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	if _, err := file.WriteString(text); err != nil {
+		return fmt.Errorf("failed to export prompt: %w", err)
+	}
+
+	fmt.Printf("Prompt exported: %s\n", path)
+	return nil
+}
+
+func formatPromptList(infos []prompts.Info) string {
+	var result strings.Builder
+
+	result.WriteString("Prompt                  Version                            Source\n")
+	result.WriteString("----------------------  ---------------------------------  ------\n")
+	for _, info := range infos {
+		source := "default"
+		if info.Path != "" {
+			source = info.Path
+		}
+		result.WriteString(fmt.Sprintf("%-22s  %-33s  %s\n", info.Name, info.ID, source))
+	}
+
+	return strings.TrimRight(result.String(), "\n")
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tennashi/tabler/internal/service"
+)
+
+// applySimilarSuggestion appends metadata from similar past tasks to input
+// when the user accepts it (or accept is set)
+func applySimilarSuggestion(taskService *service.TaskService, input string, accept bool) (string, error) {
+	suggestion, err := taskService.SuggestFromSimilar(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to find similar tasks: %w", err)
+	}
+
+	if suggestion.IsEmpty() {
+		return input, nil
+	}
+
+	shortcuts := suggestion.Shortcuts(time.Now())
+	fmt.Printf("💡 Similar to %d past tasks: %s\n", suggestion.Matches, shortcuts)
+
+	if !accept {
+		// Never block on input in non-interactive mode (for tests)
+		if os.Getenv("TABLER_NON_INTERACTIVE") == "1" {
+			return input, nil
+		}
+		if answer := askChoice("Apply? (Y/n): ", bufio.NewReader(os.Stdin)); answer != "" && answer != "y" {
+			return input, nil
+		}
+	}
+
+	return input + " " + shortcuts, nil
+}
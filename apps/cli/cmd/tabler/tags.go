@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/tennashi/tabler/internal/service"
+	"github.com/tennashi/tabler/internal/storage"
+)
+
+const tagsUsage = "usage: tabler tags | tabler tags rename <old> <new> | tabler tags merge <tag>... --into <tag> | tabler tags delete [--yes] <tag> | tabler tags normalize"
+
+func handleTagsCommand(taskService *service.TaskService, args []string) error {
+	if len(args) == 0 {
+		return listTags(taskService)
+	}
+
+	switch args[0] {
+	case "rename":
+		if len(args) != 3 {
+			return errors.New(tagsUsage)
+		}
+		count, err := taskService.RenameTag(args[1], args[2])
+		if err != nil {
+			return tagError("rename", args[1], err)
+		}
+		fmt.Printf("Renamed #%s to #%s on %s\n", args[1], args[2], formatTaskCount(count))
+		return nil
+	case "merge":
+		return mergeTags(taskService, args[1:])
+	case "delete":
+		return deleteTag(taskService, args[1:])
+	case "normalize":
+		if len(args) != 1 {
+			return errors.New(tagsUsage)
+		}
+		return normalizeTags(taskService)
+	default:
+		return fmt.Errorf("unknown tags command: %s\n%s", args[0], tagsUsage)
+	}
+}
+
+func listTags(taskService *service.TaskService) error {
+	usages, err := taskService.ListTags()
+	if err != nil {
+		return fmt.Errorf("failed to list tags: %w", err)
+	}
+	if len(usages) == 0 {
+		fmt.Println("No tags yet. Add one with #tag when creating a task.")
+		return nil
+	}
+
+	fmt.Println(formatTagUsage(usages))
+	return nil
+}
+
+func mergeTags(taskService *service.TaskService, args []string) error {
+	var sources []string
+	into := ""
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--into" {
+			if i+1 >= len(args) || into != "" {
+				return errors.New(tagsUsage)
+			}
+			into = args[i+1]
+			i++
+			continue
+		}
+		sources = append(sources, args[i])
+	}
+	if len(sources) == 0 || into == "" {
+		return errors.New(tagsUsage)
+	}
+
+	merge, err := taskService.MergeTags(sources, into)
+	if err != nil {
+		return tagError("merge", sources[0], err)
+	}
+	fmt.Printf("Merged %s into #%s on %s\n", formatTagCount(merge.Tags), into, formatTaskCount(merge.Tasks))
+	return nil
+}
+
+func deleteTag(taskService *service.TaskService, args []string) error {
+	yes := false
+	if len(args) > 0 && args[0] == "--yes" {
+		yes = true
+		args = args[1:]
+	}
+	if len(args) != 1 {
+		return errors.New(tagsUsage)
+	}
+	tag := args[0]
+
+	// Skip confirmation in non-interactive mode (for tests)
+	if !yes && os.Getenv("TABLER_NON_INTERACTIVE") != "1" {
+		if !confirmAction(fmt.Sprintf("Remove #%s from every task?", tag), os.Stdin) {
+			fmt.Println("Deletion cancelled.")
+			return nil
+		}
+	}
+
+	count, err := taskService.DeleteTag(tag)
+	if err != nil {
+		return tagError("delete", tag, err)
+	}
+	fmt.Printf("Removed #%s from %s\n", tag, formatTaskCount(count))
+	return nil
+}
+
+// normalizeTags rewrites tags saved before the current normalization rules
+func normalizeTags(taskService *service.TaskService) error {
+	count, err := taskService.NormalizeTags()
+	if err != nil {
+		return fmt.Errorf("failed to normalize tags: %w", err)
+	}
+	if count == 0 {
+		fmt.Println("All tags are already normalized.")
+		return nil
+	}
+	fmt.Printf("Normalized %d tags\n", count)
+	return nil
+}
+
+// tagError explains failed tag commands
+func tagError(action, tag string, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrTagExists):
+		return fmt.Errorf("cannot %s #%s: %w; use 'tabler tags merge' to combine tags", action, tag, err)
+	case isNotFoundError(err.Error()):
+		return fmt.Errorf("no task uses #%s (see 'tabler tags')", tag)
+	default:
+		return fmt.Errorf("failed to %s tag: %w", action, err)
+	}
+}
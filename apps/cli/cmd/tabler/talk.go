@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/tennashi/tabler/internal/config"
+	"github.com/tennashi/tabler/internal/service"
+)
+
+func handleTalkCommand(taskService *service.TaskService, cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: tabler talk <list|resume [session-id] [--answer key=value]... [--answers file] [--non-interactive]>")
+	}
+
+	switch args[0] {
+	case "list":
+		sessions, err := taskService.ListDialogues()
+		if err != nil {
+			return fmt.Errorf("failed to list talk sessions: %w", err)
+		}
+		if len(sessions) == 0 {
+			fmt.Println("No unfinished talk sessions.")
+			return nil
+		}
+		fmt.Println(formatDialogueList(sessions))
+		return nil
+	case "resume":
+		return handleTalkResume(taskService, cfg, args[1:])
+	default:
+		return fmt.Errorf("unknown talk command: %s", args[0])
+	}
+}
+
+func handleTalkResume(taskService *service.TaskService, cfg *config.Config, args []string) error {
+	resumeFlags := flag.NewFlagSet("talk resume", flag.ContinueOnError)
+	answers := answerFlags{}
+	resumeFlags.Var(answers, "answer", "Answer a clarification question as key=value (repeatable)")
+	answersFile := resumeFlags.String("answers", "", "Read clarification answers from a JSON or YAML file")
+	nonInteractive := resumeFlags.Bool("non-interactive", false, "Print unanswered clarification questions as JSON instead of asking")
+
+	// The session ID may come before the flags
+	id := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		id = args[0]
+		args = args[1:]
+	}
+	if err := resumeFlags.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+	if resumeFlags.NArg() > 1 || (id != "" && resumeFlags.NArg() > 0) {
+		return fmt.Errorf("usage: tabler talk resume [session-id] [--answer key=value]... [--answers file] [--non-interactive]")
+	}
+	if id == "" {
+		id = resumeFlags.Arg(0)
+	}
+
+	clarified, err := clarificationAnswers(*answersFile, answers, *nonInteractive)
+	if err != nil {
+		return err
+	}
+
+	return resumeTalk(taskService, cfg, id, clarified)
+}
+
+// resumeTalk continues a saved dialogue, the most recent one when id is empty
+func resumeTalk(taskService *service.TaskService, cfg *config.Config, id string, answers map[string]string) error {
+	session, err := taskService.GetDialogue(id)
+	if err != nil {
+		if errors.Is(err, service.ErrNoDialogues) {
+			fmt.Println("No unfinished talk sessions.")
+			return nil
+		}
+		if isNotFoundError(err.Error()) {
+			return fmt.Errorf("talk session not found: %s", id)
+		}
+		return fmt.Errorf("failed to load talk session: %w", err)
+	}
+
+	modeManager, err := newModeManager(taskService, cfg, answers)
+	if err != nil {
+		return err
+	}
+
+	task, err := modeManager.Resume(context.Background(), session)
+	if err != nil {
+		return fmt.Errorf("failed to resume talk session: %w", err)
+	}
+
+	taskID, err := taskService.StoreTask(task)
+	if err != nil {
+		return fmt.Errorf("failed to store task: %w", err)
+	}
+
+	fmt.Printf("Task created: %s\n", taskID)
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tennashi/tabler/internal/service"
+)
+
+const viewUsage = "usage: tabler view <name> [--output format] | tabler view save <name> <filter> [--sort spec] [--output format] | tabler view list | tabler view delete <name>"
+
+func handleViewCommand(taskService *service.TaskService, args []string) error {
+	if len(args) == 0 {
+		return errors.New(viewUsage)
+	}
+
+	switch args[0] {
+	case "save":
+		return saveView(taskService, args[1:])
+	case "list":
+		if len(args) != 1 {
+			return errors.New(viewUsage)
+		}
+		return listViews(taskService)
+	case "delete":
+		if len(args) != 2 {
+			return errors.New(viewUsage)
+		}
+		if err := taskService.DeleteView(args[1]); err != nil {
+			if isNotFoundError(err.Error()) {
+				return fmt.Errorf("view not found: %s", args[1])
+			}
+			return fmt.Errorf("failed to delete view: %w", err)
+		}
+		fmt.Printf("View deleted: %s\n", args[1])
+		return nil
+	default:
+		return showView(taskService, args[0], args[1:])
+	}
+}
+
+func saveView(taskService *service.TaskService, args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "--") {
+		return errors.New(viewUsage)
+	}
+	name := args[0]
+	if name == "save" || name == "list" || name == "delete" {
+		return fmt.Errorf("%q is a view command and cannot be used as a view name", name)
+	}
+
+	var terms []string
+	var sort, output string
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "--sort", "--output":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a value", args[i])
+			}
+			if args[i] == "--sort" {
+				sort = args[i+1]
+			} else {
+				output = args[i+1]
+			}
+			i++
+		default:
+			if strings.HasPrefix(args[i], "--") {
+				return fmt.Errorf("unknown flag: %s", args[i])
+			}
+			terms = append(terms, args[i])
+		}
+	}
+	if output != "" && !slices.Contains(outputFormats, output) {
+		return fmt.Errorf("unknown output format %q (available: %s)", output, strings.Join(outputFormats, ", "))
+	}
+
+	if err := taskService.SaveView(name, strings.Join(terms, " "), sort, output); err != nil {
+		return fmt.Errorf("failed to save view: %w", err)
+	}
+
+	fmt.Printf("View saved: %s\n", name)
+	return nil
+}
+
+func showView(taskService *service.TaskService, name string, args []string) error {
+	view, err := taskService.GetView(name)
+	if err != nil {
+		if isNotFoundError(err.Error()) {
+			return fmt.Errorf("view not found: %s (see 'tabler view list')", name)
+		}
+		return fmt.Errorf("failed to get view: %w", err)
+	}
+
+	output := view.Output
+	switch {
+	case len(args) == 2 && args[0] == "--output":
+		output = args[1]
+	case len(args) != 0:
+		return errors.New(viewUsage)
+	}
+	if output == "" {
+		output = outputTable
+	}
+
+	return listTasks(taskService, &service.FilterOptions{Query: view.Query, Sort: view.Sort}, output)
+}
+
+func listViews(taskService *service.TaskService) error {
+	views, err := taskService.ListViews()
+	if err != nil {
+		return fmt.Errorf("failed to list views: %w", err)
+	}
+	if len(views) == 0 {
+		fmt.Println("No saved views. Save one with 'tabler view save <name> <filter>'.")
+		return nil
+	}
+
+	fmt.Println(formatViewList(views))
+	return nil
+}
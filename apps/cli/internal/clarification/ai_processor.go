@@ -0,0 +1,151 @@
+package clarification
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tennashi/tabler/internal/claude"
+	"github.com/tennashi/tabler/internal/prompts"
+)
+
+// dateFormat is the deadline format exchanged with the model
+const dateFormat = "2006-01-02"
+
+// priorityLevels maps model priority names to task priorities
+var priorityLevels = map[string]int{
+	"low":    1,
+	"medium": 2,
+	"high":   3,
+}
+
+// resultSchema declares the structured task the clarification_result prompt must produce
+var resultSchema = &claude.Schema{
+	Fields: []claude.Field{
+		{Name: "title", Type: claude.StringField, Required: true, MaxLength: 200},
+		{Name: "deadline", Type: claude.StringField, Format: dateFormat, AllowEmpty: true},
+		{
+			Name:      "tags",
+			Type:      claude.StringListField,
+			MaxItems:  5,
+			MaxLength: 32,
+			Pattern:   regexp.MustCompile(`^[^\s#,]+$`),
+		},
+		{Name: "priority", Type: claude.StringField, Enum: []string{"low", "medium", "high"}, AllowEmpty: true},
+		{Name: "notes", Type: claude.StringField, AllowEmpty: true},
+	},
+}
+
+type resultResponse struct {
+	Title    string   `json:"title"`
+	Deadline string   `json:"deadline"`
+	Tags     []string `json:"tags"`
+	Priority string   `json:"priority"`
+	Notes    string   `json:"notes"`
+}
+
+// AIResponseProcessor asks the model to turn the whole dialogue into a structured task.
+// Answers are still classified heuristically while the dialogue runs; the model is
+// called once when the final task is built, and the heuristic is used when it fails.
+type AIResponseProcessor struct {
+	executor *claude.StructuredExecutor
+	prompts  *prompts.Library
+	fallback *ResponseProcessorImpl
+	now      func() time.Time
+}
+
+// NewAIResponseProcessor creates a response processor backed by the given provider
+func NewAIResponseProcessor(provider claude.Provider) *AIResponseProcessor {
+	return &AIResponseProcessor{
+		executor: claude.NewStructuredExecutor(provider),
+		prompts:  prompts.Default(),
+		fallback: NewResponseProcessor(),
+		now:      time.Now,
+	}
+}
+
+// SetPrompts sets the prompt library used to build the extraction prompt
+func (p *AIResponseProcessor) SetPrompts(library *prompts.Library) {
+	p.prompts = library
+}
+
+// SetClock sets the time source used to resolve relative deadlines (for testing)
+func (p *AIResponseProcessor) SetClock(now func() time.Time) {
+	p.now = now
+}
+
+// ProcessResponse updates the session based on user response
+func (p *AIResponseProcessor) ProcessResponse(session *DialogueSession, response string) error {
+	return p.fallback.ProcessResponse(session, response)
+}
+
+// ExtractInfo gives the question generator a cheap view of what is known so far
+func (p *AIResponseProcessor) ExtractInfo(session *DialogueSession) map[string]string {
+	return p.fallback.ExtractInfo(session)
+}
+
+// DetectsSkip checks if the user wants to skip clarification
+func (p *AIResponseProcessor) DetectsSkip(response string) bool {
+	return p.fallback.DetectsSkip(response)
+}
+
+// BuildFinalTask returns the model's task title and replaces session.ExtractedInfo
+// with its deadline, tags, priority and notes
+func (p *AIResponseProcessor) BuildFinalTask(session *DialogueSession) string {
+	result, err := p.extract(context.Background(), session)
+	if err != nil {
+		// Offline or unusable responses keep the heuristic result
+		return p.fallback.BuildFinalTask(session)
+	}
+
+	info := make(map[string]string)
+	if result.Deadline != "" {
+		info["deadline"] = result.Deadline
+	}
+	if len(result.Tags) > 0 {
+		tags := make([]string, 0, len(result.Tags))
+		for _, tag := range result.Tags {
+			tags = append(tags, strings.ToLower(tag))
+		}
+		info["tags"] = strings.Join(tags, ",")
+	}
+	if level, ok := priorityLevels[result.Priority]; ok {
+		info["priority"] = strconv.Itoa(level)
+	}
+	if notes := strings.TrimSpace(result.Notes); notes != "" {
+		info["notes"] = notes
+	}
+	session.ExtractedInfo = info
+
+	return strings.TrimSpace(result.Title)
+}
+
+// extract sends the dialogue to the model and decodes the structured task
+func (p *AIResponseProcessor) extract(ctx context.Context, session *DialogueSession) (*resultResponse, error) {
+	rendered, err := p.prompts.Render(prompts.ClarificationResult, struct {
+		CurrentDate   string
+		OriginalInput string
+		History       []Exchange
+	}{
+		CurrentDate:   p.now().Format(dateFormat),
+		OriginalInput: session.OriginalInput,
+		History:       session.History,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = claude.WithFeature(ctx, claude.FeatureClarification)
+	var result resultResponse
+	if err := p.executor.Execute(ctx, rendered.Text, resultSchema, &result); err != nil {
+		return nil, fmt.Errorf("failed to get clarified task from Claude: %w", err)
+	}
+	if strings.TrimSpace(result.Title) == "" {
+		return nil, fmt.Errorf("clarified task has an empty title")
+	}
+
+	return &result, nil
+}
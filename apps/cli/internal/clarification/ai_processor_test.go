@@ -0,0 +1,80 @@
+package clarification
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAIResponseProcessor(t *testing.T) {
+	newSession := func() *DialogueSession {
+		return &DialogueSession{
+			OriginalInput: "prepare for meeting",
+			History: []Exchange{
+				{Question: "What kind of meeting?", Answer: "quarterly review with the board"},
+				{Question: "When is it?", Answer: "next Friday"},
+			},
+			ExtractedInfo: make(map[string]string),
+		}
+	}
+
+	t.Run("BuildFinalTask", func(t *testing.T) {
+		t.Run("should use the structured task from the model", func(t *testing.T) {
+			// Arrange
+			processor := NewAIResponseProcessor(&mockClaudeClient{
+				response: `{"title": "Prepare quarterly review deck", "deadline": "2024-03-08",
+					"tags": ["Work", "meeting"], "priority": "high", "notes": "Audience: the board"}`,
+			})
+			session := newSession()
+
+			// Act
+			title := processor.BuildFinalTask(session)
+
+			// Assert
+			if title != "Prepare quarterly review deck" {
+				t.Errorf("expected model title, got %q", title)
+			}
+			expected := map[string]string{
+				"deadline": "2024-03-08",
+				"tags":     "work,meeting",
+				"priority": "3",
+				"notes":    "Audience: the board",
+			}
+			for key, value := range expected {
+				if session.ExtractedInfo[key] != value {
+					t.Errorf("expected %s %q, got %q", key, value, session.ExtractedInfo[key])
+				}
+			}
+		})
+
+		t.Run("should fall back to heuristics when the model fails", func(t *testing.T) {
+			// Arrange
+			processor := NewAIResponseProcessor(&mockClaudeClient{err: errors.New("offline")})
+			session := newSession()
+
+			// Act
+			title := processor.BuildFinalTask(session)
+
+			// Assert
+			expected := NewResponseProcessor().BuildFinalTask(newSession())
+			if title != expected {
+				t.Errorf("expected heuristic title %q, got %q", expected, title)
+			}
+		})
+
+		t.Run("should fall back to heuristics when the response is invalid", func(t *testing.T) {
+			// Arrange
+			processor := NewAIResponseProcessor(&mockClaudeClient{response: `{"title": "", "priority": "urgent"}`})
+			processor.executor.SetMaxAttempts(1)
+			session := newSession()
+
+			// Act
+			title := processor.BuildFinalTask(session)
+
+			// Assert
+			expected := NewResponseProcessor().BuildFinalTask(newSession())
+			if title != expected {
+				t.Errorf("expected heuristic title %q, got %q", expected, title)
+			}
+		})
+	})
+}
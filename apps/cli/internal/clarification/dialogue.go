@@ -2,22 +2,38 @@ package clarification
 
 import (
 	"context"
+	"time"
 )
 
 // Exchange represents a question-answer pair in dialogue
 type Exchange struct {
-	Question string
-	Answer   string
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
 }
 
 // DialogueSession maintains the state of a clarification dialogue
 type DialogueSession struct {
+	ID              string // set when the session is saved so it can be resumed
+	Input           string // input as typed, including task shortcuts
 	OriginalInput   string
 	CurrentQuestion string
 	History         []Exchange
 	ExtractedInfo   map[string]string
 	IsComplete      bool
 	SkipRequested   bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Answered returns the exchanges the user has answered, in order
+func (s *DialogueSession) Answered() []Exchange {
+	var answered []Exchange
+	for _, exchange := range s.History {
+		if exchange.Answer != "" {
+			answered = append(answered, exchange)
+		}
+	}
+	return answered
 }
 
 // QuestionGenerator creates contextual questions
@@ -35,46 +35,59 @@ func (p *ResponseProcessorImpl) ExtractInfo(session *DialogueSession) map[string
 			continue
 		}
 
-		questionLower := strings.ToLower(exchange.Question)
 		answer := strings.TrimSpace(exchange.Answer)
 
 		// Extract based on question patterns
-		switch {
-		case strings.Contains(questionLower, "what kind") || strings.Contains(questionLower, "what type"):
+		switch key := QuestionKey(exchange.Question); key {
+		case "":
+			// Nothing we know how to file this answer under
+		case "type":
 			// Extract type information
-			if strings.Contains(questionLower, "meeting") {
-				info["type"] = answer + " meeting"
+			if strings.Contains(strings.ToLower(exchange.Question), "meeting") {
+				info[key] = answer + " meeting"
 			} else {
-				info["type"] = answer
+				info[key] = answer
 			}
-
-		case strings.Contains(questionLower, "what") && !strings.Contains(questionLower, "what kind"):
-			// Extract what/subject
-			info["what"] = answer
-
-		case strings.Contains(questionLower, "when") || strings.Contains(questionLower, "deadline"):
+		case "deadline":
 			// Extract timing
-			info["deadline"] = p.normalizeDeadline(answer)
-
-		case strings.Contains(questionLower, "who") || strings.Contains(questionLower, "whom"):
-			// Extract audience/recipient
-			info["audience"] = answer
+			info[key] = p.normalizeDeadline(answer)
+		default:
+			info[key] = answer
+		}
+	}
 
-		case strings.Contains(questionLower, "which"):
-			// Extract specific selection
-			if strings.Contains(questionLower, "project") {
-				info["project"] = answer
-			} else {
-				info["selection"] = answer
-			}
+	return info
+}
 
-		case strings.Contains(questionLower, "prepare") || strings.Contains(questionLower, "need"):
-			// Extract materials/requirements
-			info["materials"] = answer
+// QuestionKey classifies a question by the information it asks for, using the
+// keys ExtractInfo fills in: "type", "what", "deadline", "audience", "project",
+// "selection" or "materials". It returns "" for questions it cannot classify.
+func QuestionKey(question string) string {
+	questionLower := strings.ToLower(question)
+
+	switch {
+	case strings.Contains(questionLower, "what kind") || strings.Contains(questionLower, "what type"):
+		return "type"
+	case strings.Contains(questionLower, "what"):
+		// Extract what/subject
+		return "what"
+	case strings.Contains(questionLower, "when") || strings.Contains(questionLower, "deadline"):
+		return "deadline"
+	case strings.Contains(questionLower, "who") || strings.Contains(questionLower, "whom"):
+		// Extract audience/recipient
+		return "audience"
+	case strings.Contains(questionLower, "which"):
+		// Extract specific selection
+		if strings.Contains(questionLower, "project") {
+			return "project"
 		}
+		return "selection"
+	case strings.Contains(questionLower, "prepare") || strings.Contains(questionLower, "need"):
+		// Extract materials/requirements
+		return "materials"
 	}
 
-	return info
+	return ""
 }
 
 // normalizeDeadline cleans up deadline responses
@@ -183,3 +183,31 @@ func TestResponseProcessor(t *testing.T) {
 		}
 	})
 }
+
+func TestQuestionKey(t *testing.T) {
+	tests := []struct {
+		question string
+		expected string
+	}{
+		{"What kind of meeting is it?", "type"},
+		{"What do you need to prepare?", "what"},
+		{"When is it due?", "deadline"},
+		{"Who is the audience?", "audience"},
+		{"Which project is this for?", "project"},
+		{"Which option do you prefer?", "selection"},
+		{"Do you need slides?", "materials"},
+		{"Anything else?", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.question, func(t *testing.T) {
+			// Act
+			key := QuestionKey(tt.question)
+
+			// Assert
+			if key != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, key)
+			}
+		})
+	}
+}
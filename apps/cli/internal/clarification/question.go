@@ -2,8 +2,11 @@ package clarification
 
 import (
 	"context"
-	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/tennashi/tabler/internal/claude"
+	"github.com/tennashi/tabler/internal/prompts"
 )
 
 // ClaudeClient interface for Claude integration
@@ -13,22 +16,33 @@ type ClaudeClient interface {
 
 // QuestionGeneratorImpl creates contextual questions using Claude
 type QuestionGeneratorImpl struct {
-	claude ClaudeClient
+	claude  ClaudeClient
+	prompts *prompts.Library
 }
 
 // NewQuestionGenerator creates a new question generator
 func NewQuestionGenerator(claude ClaudeClient) *QuestionGeneratorImpl {
 	return &QuestionGeneratorImpl{
-		claude: claude,
+		claude:  claude,
+		prompts: prompts.Default(),
 	}
 }
 
+// SetPrompts sets the prompt library used to build questions
+func (g *QuestionGeneratorImpl) SetPrompts(library *prompts.Library) {
+	g.prompts = library
+}
+
 // GenerateQuestion creates the next clarifying question or signals completion
 func (g *QuestionGeneratorImpl) GenerateQuestion(ctx context.Context, session *DialogueSession) (string, bool, error) {
 	// Build prompt for Claude
-	prompt := g.buildPrompt(session)
+	prompt, err := g.buildPrompt(session)
+	if err != nil {
+		return "", false, err
+	}
 
 	// Call Claude
+	ctx = claude.WithFeature(ctx, claude.FeatureClarification)
 	response, err := g.claude.Execute(ctx, prompt)
 	if err != nil {
 		return "", false, err
@@ -46,44 +60,43 @@ func (g *QuestionGeneratorImpl) GenerateQuestion(ctx context.Context, session *D
 	return question, false, nil
 }
 
+// questionPromptData is the data available to the clarification_question template
+type questionPromptData struct {
+	OriginalInput string
+	History       []Exchange
+	Info          []infoItem
+}
+
+// infoItem is a single piece of gathered information
+type infoItem struct {
+	Key   string
+	Value string
+}
+
 // buildPrompt creates the prompt for Claude
-func (g *QuestionGeneratorImpl) buildPrompt(session *DialogueSession) string {
-	var prompt strings.Builder
-
-	prompt.WriteString("You are helping clarify a vague task. ")
-	prompt.WriteString("Generate ONE clarifying question to gather missing information.\n\n")
-
-	prompt.WriteString(fmt.Sprintf("Original task: \"%s\"\n\n", session.OriginalInput))
-
-	// Include dialogue history
-	if len(session.History) > 0 {
-		prompt.WriteString("Dialogue so far:\n")
-		for _, exchange := range session.History {
-			prompt.WriteString(fmt.Sprintf("Q: %s\n", exchange.Question))
-			if exchange.Answer != "" {
-				prompt.WriteString(fmt.Sprintf("A: %s\n", exchange.Answer))
-			}
-		}
-		prompt.WriteString("\n")
+func (g *QuestionGeneratorImpl) buildPrompt(session *DialogueSession) (string, error) {
+	// Sort keys so the same session always yields the same prompt
+	keys := make([]string, 0, len(session.ExtractedInfo))
+	for key := range session.ExtractedInfo {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
 
-	// Include extracted information
-	if len(session.ExtractedInfo) > 0 {
-		prompt.WriteString("Information gathered:\n")
-		for key, value := range session.ExtractedInfo {
-			prompt.WriteString(fmt.Sprintf("- %s: %s\n", key, value))
-		}
-		prompt.WriteString("\n")
+	info := make([]infoItem, 0, len(keys))
+	for _, key := range keys {
+		info = append(info, infoItem{Key: key, Value: session.ExtractedInfo[key]})
 	}
 
-	prompt.WriteString("Instructions:\n")
-	prompt.WriteString("- If you have enough information to create a clear task, respond with just: COMPLETE\n")
-	prompt.WriteString("- Otherwise, ask ONE specific question to clarify what's missing\n")
-	prompt.WriteString("- Focus on: what, when, who, or specific details\n")
-	prompt.WriteString("- Keep questions short and natural\n")
-	prompt.WriteString("- Do not include any explanation, just the question\n")
+	rendered, err := g.prompts.Render(prompts.ClarificationQuestion, questionPromptData{
+		OriginalInput: session.OriginalInput,
+		History:       session.History,
+		Info:          info,
+	})
+	if err != nil {
+		return "", err
+	}
 
-	return prompt.String()
+	return rendered.Text, nil
 }
 
 // extractQuestion cleans up Claude's response to get just the question
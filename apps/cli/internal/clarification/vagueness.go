@@ -2,19 +2,22 @@ package clarification
 
 import (
 	"strings"
+	"unicode"
+
+	"github.com/tennashi/tabler/internal/language"
 )
 
-// VaguenessDetector identifies when task input needs clarification
-type VaguenessDetector struct {
-	genericWords     []string
-	vagueVerbs       []string
-	minWordCount     int
-	clarityThreshold float64
+// vaguenessVocabulary holds the words that signal vague or specific input in one language
+type vaguenessVocabulary struct {
+	genericWords   []string
+	vagueVerbs     []string
+	dateWords      []string
+	contextPhrases []string
 }
 
-// NewVaguenessDetector creates a new vagueness detector
-func NewVaguenessDetector() *VaguenessDetector {
-	return &VaguenessDetector{
+// vaguenessVocabularies are the supported languages
+var vaguenessVocabularies = map[language.Language]*vaguenessVocabulary{
+	language.English: {
 		genericWords: []string{
 			"thing", "things", "stuff", "it", "that", "this",
 			"something", "everything", "anything",
@@ -23,36 +26,77 @@ func NewVaguenessDetector() *VaguenessDetector {
 			"do", "handle", "deal", "work", "fix", "check",
 			"look", "see", "get", "prepare",
 		},
-		minWordCount:     3,
+		dateWords: []string{
+			"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
+			"today", "tomorrow", "week", "month",
+			"january", "february", "march", "april", "may", "june",
+			"july", "august", "september", "october", "november", "december",
+		},
+		contextPhrases: []string{
+			"send the", "prepare the", "finish the", "complete the",
+			"review the", "update the", "fix the",
+		},
+	},
+	language.Japanese: {
+		genericWords: []string{
+			"それ", "これ", "あれ", "もの", "こと", "何か", "なにか",
+			"色々", "いろいろ", "例の", "あの件", "この件",
+		},
+		vagueVerbs: []string{
+			"やる", "対応", "確認", "処理", "片付け", "チェック", "準備", "見て",
+		},
+		dateWords: []string{
+			"月曜", "火曜", "水曜", "木曜", "金曜", "土曜", "日曜",
+			"今日", "明日", "明後日", "今週", "来週", "今月", "来月", "月末",
+		},
+	},
+}
+
+// VaguenessDetector identifies when task input needs clarification
+type VaguenessDetector struct {
+	language         language.Language
+	clarityThreshold float64
+}
+
+// NewVaguenessDetector creates a new vagueness detector
+func NewVaguenessDetector() *VaguenessDetector {
+	return &VaguenessDetector{
+		language:         language.Auto,
 		clarityThreshold: 0.4,
 	}
 }
 
+// SetLanguage fixes the input language instead of detecting it per input
+func (d *VaguenessDetector) SetLanguage(lang language.Language) {
+	d.language = lang
+}
+
 // DetectVagueness analyzes task input and returns if it needs clarification
 func (d *VaguenessDetector) DetectVagueness(input string) (bool, float64) {
-	lowerInput := strings.ToLower(strings.TrimSpace(input))
-	words := strings.Fields(lowerInput)
+	text := language.Analyze(strings.TrimSpace(input), d.language)
+	vocabulary := vaguenessVocabularies[text.Language]
+	wordCount := contentWordCount(text)
 
 	// Calculate vagueness score (0.0 = clear, 1.0 = very vague)
 	score := 0.0
 
 	// Factor 1: Length
-	score += d.scoreLengthFactor(len(words))
+	score += d.scoreLengthFactor(wordCount)
 
 	// Factor 2: Generic words
-	score += d.scoreGenericWords(words)
+	score += d.scoreGenericWords(text, vocabulary)
 
 	// Factor 3: Vague verbs
-	score += d.scoreVagueVerbs(words)
+	score += d.scoreVagueVerbs(text, vocabulary)
 
 	// Factor 4: Question marks
 	score += d.scoreQuestionMarks(input)
 
 	// Factor 5: Lacks specifics
-	score += d.scoreSpecificity(input, lowerInput, words)
+	score += d.scoreSpecificity(input, text, vocabulary, wordCount)
 
 	// Factor 6: Missing context
-	score += d.scoreMissingContext(lowerInput)
+	score += d.scoreMissingContext(text, vocabulary)
 
 	// Cap score at 1.0
 	if score > 1.0 {
@@ -65,6 +109,25 @@ func (d *VaguenessDetector) DetectVagueness(input string) (bool, float64) {
 	return isVague, score
 }
 
+// contentWordCount counts words, ignoring Japanese particles and verb endings
+func contentWordCount(text *language.Text) int {
+	if text.Language != language.Japanese {
+		return len(text.Words)
+	}
+
+	count := 0
+	for _, word := range text.Words {
+		if !language.IsKana(word) {
+			count++
+		}
+	}
+	// A sentence written only in hiragana is still one word
+	if count == 0 && len(text.Words) > 0 {
+		count = 1
+	}
+	return count
+}
+
 // scoreLengthFactor scores based on task length
 func (d *VaguenessDetector) scoreLengthFactor(wordCount int) float64 {
 	switch wordCount {
@@ -78,15 +141,10 @@ func (d *VaguenessDetector) scoreLengthFactor(wordCount int) float64 {
 }
 
 // scoreGenericWords scores based on generic word usage
-func (d *VaguenessDetector) scoreGenericWords(words []string) float64 {
+func (d *VaguenessDetector) scoreGenericWords(text *language.Text, vocabulary *vaguenessVocabulary) float64 {
 	genericCount := 0
-	for _, word := range words {
-		for _, generic := range d.genericWords {
-			if word == generic {
-				genericCount++
-				break
-			}
-		}
+	for _, generic := range vocabulary.genericWords {
+		genericCount += text.Count(generic)
 	}
 	if genericCount > 0 {
 		return float64(genericCount) * 0.2
@@ -95,12 +153,10 @@ func (d *VaguenessDetector) scoreGenericWords(words []string) float64 {
 }
 
 // scoreVagueVerbs scores based on vague verb usage
-func (d *VaguenessDetector) scoreVagueVerbs(words []string) float64 {
-	for _, word := range words {
-		for _, verb := range d.vagueVerbs {
-			if word == verb {
-				return 0.3
-			}
+func (d *VaguenessDetector) scoreVagueVerbs(text *language.Text, vocabulary *vaguenessVocabulary) float64 {
+	for _, verb := range vocabulary.vagueVerbs {
+		if text.Has(verb) {
+			return 0.3
 		}
 	}
 	return 0.0
@@ -108,54 +164,40 @@ func (d *VaguenessDetector) scoreVagueVerbs(words []string) float64 {
 
 // scoreQuestionMarks scores based on presence of questions
 func (d *VaguenessDetector) scoreQuestionMarks(input string) float64 {
-	if strings.Contains(input, "?") {
+	if strings.ContainsAny(input, "?？") {
 		return 0.4
 	}
 	return 0.0
 }
 
 // scoreSpecificity scores based on lack of specific details
-func (d *VaguenessDetector) scoreSpecificity(input, lowerInput string, words []string) float64 {
-	// Check for numbers
+func (d *VaguenessDetector) scoreSpecificity(input string, text *language.Text, vocabulary *vaguenessVocabulary, wordCount int) float64 {
+	// Check for numbers (including full-width digits)
 	for _, char := range input {
-		if char >= '0' && char <= '9' {
+		if unicode.IsDigit(char) {
 			return 0.0
 		}
 	}
 
 	// Check for dates
-	if d.containsDateWords(lowerInput) {
-		return 0.0
+	for _, dateWord := range vocabulary.dateWords {
+		if strings.Contains(text.Lower, dateWord) {
+			return 0.0
+		}
 	}
 
 	// Check for proper nouns
-	if d.containsProperNouns(input) {
+	if text.Language == language.English && d.containsProperNouns(input) {
 		return 0.0
 	}
 
 	// No specifics found and task is not too short
-	if len(words) > 2 {
+	if wordCount > 2 {
 		return 0.2
 	}
 	return 0.0
 }
 
-// containsDateWords checks if input contains date-related words
-func (d *VaguenessDetector) containsDateWords(lowerInput string) bool {
-	dateWords := []string{
-		"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday",
-		"today", "tomorrow", "week", "month",
-		"january", "february", "march", "april", "may", "june",
-		"july", "august", "september", "october", "november", "december",
-	}
-	for _, dateWord := range dateWords {
-		if strings.Contains(lowerInput, dateWord) {
-			return true
-		}
-	}
-	return false
-}
-
 // containsProperNouns checks for capitalized words (simple heuristic)
 func (d *VaguenessDetector) containsProperNouns(input string) bool {
 	words := strings.Fields(input)
@@ -168,16 +210,11 @@ func (d *VaguenessDetector) containsProperNouns(input string) bool {
 }
 
 // scoreMissingContext scores based on incomplete phrases
-func (d *VaguenessDetector) scoreMissingContext(lowerInput string) float64 {
-	contextPhrases := []string{
-		"send the", "prepare the", "finish the", "complete the",
-		"review the", "update the", "fix the",
-	}
-
-	for _, phrase := range contextPhrases {
-		if idx := strings.Index(lowerInput, phrase); idx != -1 {
+func (d *VaguenessDetector) scoreMissingContext(text *language.Text, vocabulary *vaguenessVocabulary) float64 {
+	for _, phrase := range vocabulary.contextPhrases {
+		if idx := strings.Index(text.Lower, phrase); idx != -1 {
 			// Check what comes after the phrase
-			afterPhrase := lowerInput[idx+len(phrase):]
+			afterPhrase := text.Lower[idx+len(phrase):]
 			afterWords := strings.Fields(afterPhrase)
 			if len(afterWords) <= 1 {
 				return 0.3
@@ -2,6 +2,8 @@ package clarification
 
 import (
 	"testing"
+
+	"github.com/tennashi/tabler/internal/language"
 )
 
 func TestVaguenessDetector(t *testing.T) {
@@ -105,4 +107,46 @@ func TestVaguenessDetector(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("Japanese input", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			input         string
+			expectedVague bool
+		}{
+			{"generic word and vague verb", "あれをやる", true},
+			{"vague verb without object", "準備する", true},
+			{"specific task with date", "明日までに第3四半期の予算レポートを提出", false},
+			{"full-width question mark", "会議の資料はどうする？", true},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				detector := NewVaguenessDetector()
+
+				// Act
+				isVague, score := detector.DetectVagueness(tt.input)
+
+				// Assert
+				if isVague != tt.expectedVague {
+					t.Errorf("expected vague=%v for %q, got %v (score %.1f)", tt.expectedVague, tt.input, isVague, score)
+				}
+			})
+		}
+	})
+
+	t.Run("should use the configured language instead of detecting it", func(t *testing.T) {
+		// Arrange
+		detector := NewVaguenessDetector()
+		detector.SetLanguage(language.English)
+
+		// Act
+		_, score := detector.DetectVagueness("あれをやる")
+
+		// Assert
+		if score > 0.6 {
+			t.Errorf("expected English vocabulary to ignore Japanese words, got score %.1f", score)
+		}
+	})
 }
@@ -0,0 +1,136 @@
+package claude
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// ProviderModeEnv selects how AI calls are served: "live" (default), "record" or "replay"
+	ProviderModeEnv = "TABLER_AI_PROVIDER"
+	// FixtureDirEnv points at the directory holding recorded prompt/response fixtures
+	FixtureDirEnv = "TABLER_AI_FIXTURES"
+)
+
+// ErrFixtureNotFound is returned when replaying a prompt that was never recorded
+var ErrFixtureNotFound = errors.New("fixture not found")
+
+// Fixture is a recorded prompt/response pair
+type Fixture struct {
+	Prompt   string `json:"prompt"`
+	Response string `json:"response"`
+}
+
+// FixtureKey derives the fixture file name for a prompt
+func FixtureKey(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:8])
+}
+
+// RecordingProvider forwards prompts to another provider and saves every successful exchange as a fixture
+type RecordingProvider struct {
+	provider Provider
+	dir      string
+}
+
+// NewRecordingProvider creates a provider that records exchanges into dir
+func NewRecordingProvider(provider Provider, dir string) *RecordingProvider {
+	return &RecordingProvider{provider: provider, dir: dir}
+}
+
+// Execute implements Provider
+func (p *RecordingProvider) Execute(ctx context.Context, prompt string) (string, error) {
+	response, err := p.provider.Execute(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeFixture(p.dir, &Fixture{Prompt: prompt, Response: response}); err != nil {
+		return "", fmt.Errorf("failed to record fixture: %w", err)
+	}
+
+	return response, nil
+}
+
+// ReplayProvider serves responses from recorded fixtures without calling the model
+type ReplayProvider struct {
+	dir string
+}
+
+// NewReplayProvider creates a provider that replays fixtures from dir
+func NewReplayProvider(dir string) *ReplayProvider {
+	return &ReplayProvider{dir: dir}
+}
+
+// Execute implements Provider
+func (p *ReplayProvider) Execute(_ context.Context, prompt string) (string, error) {
+	key := FixtureKey(prompt)
+
+	data, err := os.ReadFile(filepath.Join(p.dir, key+".json")) // #nosec G304 - fixture dir is user-controlled
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("%w: %s (record it with %s=record)", ErrFixtureNotFound, key, ProviderModeEnv)
+		}
+		return "", err
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return "", fmt.Errorf("invalid fixture %s: %w", key, err)
+	}
+
+	return fixture.Response, nil
+}
+
+// NewProviderFromEnv wraps the live provider according to TABLER_AI_PROVIDER
+func NewProviderFromEnv(live Provider) (Provider, error) {
+	mode := os.Getenv(ProviderModeEnv)
+	if mode == "" || mode == "live" {
+		return live, nil
+	}
+
+	dir := os.Getenv(FixtureDirEnv)
+	if dir == "" {
+		return nil, fmt.Errorf("%s must be set when %s=%s", FixtureDirEnv, ProviderModeEnv, mode)
+	}
+
+	switch mode {
+	case "record":
+		return NewRecordingProvider(live, dir), nil
+	case "replay":
+		return NewReplayProvider(dir), nil
+	default:
+		return nil, fmt.Errorf("unknown %s: %s (expected live, record or replay)", ProviderModeEnv, mode)
+	}
+}
+
+// NewFixtureProvider serves tests from the fixtures in dir. Unlike
+// NewProviderFromEnv it replays by default and only calls live, recording its
+// answers into dir, when TABLER_AI_PROVIDER=record.
+func NewFixtureProvider(dir string, live Provider) Provider {
+	if os.Getenv(ProviderModeEnv) == "record" {
+		return NewRecordingProvider(live, dir)
+	}
+	return NewReplayProvider(dir)
+}
+
+// writeFixture saves a fixture under its prompt key
+func writeFixture(dir string, fixture *Fixture) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, FixtureKey(fixture.Prompt)+".json")
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
@@ -0,0 +1,142 @@
+package claude_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tennashi/tabler/internal/claude"
+)
+
+func TestRecordReplay(t *testing.T) {
+	t.Run("replays recorded responses", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		live := &mockProvider{responses: []string{"recorded answer"}}
+		recorder := claude.NewRecordingProvider(live, dir)
+		if _, err := recorder.Execute(context.Background(), "What is next?"); err != nil {
+			t.Fatalf("failed to record: %v", err)
+		}
+		replay := claude.NewReplayProvider(dir)
+
+		// Act
+		response, err := replay.Execute(context.Background(), "What is next?")
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if response != "recorded answer" {
+			t.Errorf("expected %q, got %q", "recorded answer", response)
+		}
+	})
+
+	t.Run("reports missing fixtures", func(t *testing.T) {
+		// Arrange
+		replay := claude.NewReplayProvider(t.TempDir())
+
+		// Act
+		_, err := replay.Execute(context.Background(), "never recorded")
+
+		// Assert
+		if !errors.Is(err, claude.ErrFixtureNotFound) {
+			t.Errorf("expected ErrFixtureNotFound, got %v", err)
+		}
+	})
+
+	t.Run("does not record failed calls", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		live := &mockProvider{err: errors.New("claude CLI not found")}
+		recorder := claude.NewRecordingProvider(live, dir)
+
+		// Act
+		_, err := recorder.Execute(context.Background(), "prompt")
+
+		// Assert
+		if err == nil {
+			t.Fatal("expected error from live provider")
+		}
+		if _, err := claude.NewReplayProvider(dir).Execute(context.Background(), "prompt"); !errors.Is(err, claude.ErrFixtureNotFound) {
+			t.Errorf("expected no fixture to be recorded, got %v", err)
+		}
+	})
+}
+
+func TestNewProviderFromEnv(t *testing.T) {
+	live := &mockProvider{responses: []string{"live"}}
+
+	t.Run("uses live provider by default", func(t *testing.T) {
+		// Arrange
+		t.Setenv(claude.ProviderModeEnv, "")
+
+		// Act
+		provider, err := claude.NewProviderFromEnv(live)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if provider != live {
+			t.Error("expected live provider")
+		}
+	})
+
+	t.Run("selects replay provider", func(t *testing.T) {
+		// Arrange
+		t.Setenv(claude.ProviderModeEnv, "replay")
+		t.Setenv(claude.FixtureDirEnv, t.TempDir())
+
+		// Act
+		provider, err := claude.NewProviderFromEnv(live)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := provider.(*claude.ReplayProvider); !ok {
+			t.Errorf("expected ReplayProvider, got %T", provider)
+		}
+	})
+
+	t.Run("requires fixture directory", func(t *testing.T) {
+		// Arrange
+		t.Setenv(claude.ProviderModeEnv, "record")
+		t.Setenv(claude.FixtureDirEnv, "")
+
+		// Act
+		_, err := claude.NewProviderFromEnv(live)
+
+		// Assert
+		if err == nil {
+			t.Error("expected error when fixture directory is missing")
+		}
+	})
+}
+
+func TestNewFixtureProvider(t *testing.T) {
+	live := &mockProvider{responses: []string{"live"}}
+
+	t.Run("replays by default", func(t *testing.T) {
+		// Arrange
+		t.Setenv(claude.ProviderModeEnv, "")
+
+		// Act
+		provider := claude.NewFixtureProvider(t.TempDir(), live)
+
+		// Assert
+		if _, ok := provider.(*claude.ReplayProvider); !ok {
+			t.Errorf("expected ReplayProvider, got %T", provider)
+		}
+	})
+
+	t.Run("records against the live provider", func(t *testing.T) {
+		// Arrange
+		t.Setenv(claude.ProviderModeEnv, "record")
+
+		// Act
+		provider := claude.NewFixtureProvider(t.TempDir(), live)
+
+		// Assert
+		if _, ok := provider.(*claude.RecordingProvider); !ok {
+			t.Errorf("expected RecordingProvider, got %T", provider)
+		}
+	})
+}
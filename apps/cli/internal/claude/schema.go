@@ -0,0 +1,306 @@
+package claude
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// FieldType identifies the JSON type expected for a schema field
+type FieldType int
+
+const (
+	// StringField expects a JSON string
+	StringField FieldType = iota
+	// StringListField expects a JSON array of strings
+	StringListField
+	// NumberField expects a JSON number
+	NumberField
+	// ObjectListField expects a JSON array of objects described by Items
+	ObjectListField
+	// NumberListField expects a JSON array of numbers, each within Min and Max
+	NumberListField
+)
+
+// Field declares the constraints for a single response field
+type Field struct {
+	Name     string
+	Type     FieldType
+	Required bool
+
+	// AllowEmpty accepts "" for string fields even when Enum or Format is set
+	AllowEmpty bool
+	// Enum lists the allowed values for strings and string list items
+	Enum []string
+	// Format is a time layout that strings must parse with (e.g. "2006-01-02")
+	Format string
+	// Pattern must match strings and string list items
+	Pattern *regexp.Regexp
+	// MaxLength limits the length of strings and string list items
+	MaxLength int
+
+	// MinItems and MaxItems bound list lengths (0 means unbounded)
+	MinItems int
+	MaxItems int
+
+	// Min and Max bound numbers and number list items when either is non-zero
+	Min float64
+	Max float64
+
+	// Items describes the objects in an ObjectListField
+	Items *Schema
+}
+
+// Schema declares the expected shape of a JSON object returned by the model
+type Schema struct {
+	Fields []Field
+}
+
+// ValidationError lists every schema violation found in a response
+type ValidationError struct {
+	Violations []string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return "response does not match schema: " + strings.Join(e.Violations, "; ")
+}
+
+// Validate checks raw JSON against the schema
+func (s *Schema) Validate(data []byte) error {
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return &ValidationError{Violations: []string{"response is not a JSON object: " + err.Error()}}
+	}
+
+	violations := s.validateObject(object, "")
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+
+	return nil
+}
+
+// Describe renders the schema constraints as prompt-friendly text
+func (s *Schema) Describe() string {
+	var b strings.Builder
+	s.describe(&b, "")
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (s *Schema) describe(b *strings.Builder, indent string) {
+	for _, field := range s.Fields {
+		b.WriteString(fmt.Sprintf("%s- %s: %s", indent, field.Name, field.typeName()))
+
+		var rules []string
+		if field.Required {
+			rules = append(rules, "required")
+		}
+		if len(field.Enum) > 0 {
+			rules = append(rules, "one of "+strings.Join(field.Enum, "/"))
+		}
+		if field.Format != "" {
+			rules = append(rules, "format "+field.Format)
+		}
+		if field.AllowEmpty {
+			rules = append(rules, "may be empty")
+		}
+		if field.Pattern != nil {
+			rules = append(rules, "must match "+field.Pattern.String())
+		}
+		if field.MaxLength > 0 {
+			rules = append(rules, fmt.Sprintf("at most %d characters", field.MaxLength))
+		}
+		if field.MinItems > 0 {
+			rules = append(rules, fmt.Sprintf("at least %d items", field.MinItems))
+		}
+		if field.MaxItems > 0 {
+			rules = append(rules, fmt.Sprintf("at most %d items", field.MaxItems))
+		}
+		if field.hasRange() {
+			rules = append(rules, fmt.Sprintf("between %g and %g", field.Min, field.Max))
+		}
+		if len(rules) > 0 {
+			b.WriteString(" (" + strings.Join(rules, ", ") + ")")
+		}
+		b.WriteString("\n")
+
+		if field.Type == ObjectListField && field.Items != nil {
+			field.Items.describe(b, indent+"  ")
+		}
+	}
+}
+
+func (f *Field) typeName() string {
+	switch f.Type {
+	case StringField:
+		return "string"
+	case StringListField:
+		return "array of strings"
+	case NumberField:
+		return "number"
+	case ObjectListField:
+		return "array of objects"
+	case NumberListField:
+		return "array of numbers"
+	default:
+		return "value"
+	}
+}
+
+func (f *Field) hasRange() bool {
+	return f.Min != 0 || f.Max != 0
+}
+
+// validateObject checks a decoded object and returns violations prefixed with path
+func (s *Schema) validateObject(object map[string]interface{}, path string) []string {
+	var violations []string
+
+	for i := range s.Fields {
+		field := &s.Fields[i]
+		name := path + field.Name
+
+		value, present := object[field.Name]
+		if !present || value == nil {
+			if field.Required {
+				violations = append(violations, fmt.Sprintf("%s is required", name))
+			}
+			continue
+		}
+
+		violations = append(violations, field.validate(value, name)...)
+	}
+
+	return violations
+}
+
+func (f *Field) validate(value interface{}, name string) []string {
+	switch f.Type {
+	case StringField:
+		s, ok := value.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a string", name)}
+		}
+		if s == "" {
+			if f.Required && !f.AllowEmpty {
+				return []string{fmt.Sprintf("%s must not be empty", name)}
+			}
+			return nil
+		}
+		return f.validateString(s, name)
+
+	case StringListField:
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s must be an array of strings", name)}
+		}
+		violations := f.validateCount(len(items), name)
+		for i, item := range items {
+			itemName := fmt.Sprintf("%s[%d]", name, i)
+			s, ok := item.(string)
+			if !ok {
+				violations = append(violations, fmt.Sprintf("%s must be a string", itemName))
+				continue
+			}
+			if s == "" {
+				violations = append(violations, fmt.Sprintf("%s must not be empty", itemName))
+				continue
+			}
+			violations = append(violations, f.validateString(s, itemName)...)
+		}
+		return violations
+
+	case NumberField:
+		n, ok := value.(float64)
+		if !ok {
+			return []string{fmt.Sprintf("%s must be a number", name)}
+		}
+		if f.hasRange() && (n < f.Min || n > f.Max) {
+			return []string{fmt.Sprintf("%s must be between %g and %g", name, f.Min, f.Max)}
+		}
+		return nil
+
+	case NumberListField:
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s must be an array of numbers", name)}
+		}
+		violations := f.validateCount(len(items), name)
+		for i, item := range items {
+			itemName := fmt.Sprintf("%s[%d]", name, i)
+			n, ok := item.(float64)
+			if !ok {
+				violations = append(violations, fmt.Sprintf("%s must be a number", itemName))
+				continue
+			}
+			if f.hasRange() && (n < f.Min || n > f.Max) {
+				violations = append(violations, fmt.Sprintf("%s must be between %g and %g", itemName, f.Min, f.Max))
+			}
+		}
+		return violations
+
+	case ObjectListField:
+		items, ok := value.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s must be an array of objects", name)}
+		}
+		violations := f.validateCount(len(items), name)
+		for i, item := range items {
+			itemName := fmt.Sprintf("%s[%d]", name, i)
+			object, ok := item.(map[string]interface{})
+			if !ok {
+				violations = append(violations, fmt.Sprintf("%s must be an object", itemName))
+				continue
+			}
+			if f.Items != nil {
+				violations = append(violations, f.Items.validateObject(object, itemName+".")...)
+			}
+		}
+		return violations
+
+	default:
+		return nil
+	}
+}
+
+func (f *Field) validateString(s, name string) []string {
+	var violations []string
+
+	if len(f.Enum) > 0 && !containsString(f.Enum, s) {
+		violations = append(violations, fmt.Sprintf("%s must be one of %s, got %q", name, strings.Join(f.Enum, "/"), s))
+	}
+	if f.Format != "" {
+		if _, err := time.Parse(f.Format, s); err != nil {
+			violations = append(violations, fmt.Sprintf("%s must use format %s, got %q", name, f.Format, s))
+		}
+	}
+	if f.Pattern != nil && !f.Pattern.MatchString(s) {
+		violations = append(violations, fmt.Sprintf("%s must match %s, got %q", name, f.Pattern.String(), s))
+	}
+	if f.MaxLength > 0 && len([]rune(s)) > f.MaxLength {
+		violations = append(violations, fmt.Sprintf("%s must be at most %d characters", name, f.MaxLength))
+	}
+
+	return violations
+}
+
+func (f *Field) validateCount(count int, name string) []string {
+	if f.MinItems > 0 && count < f.MinItems {
+		return []string{fmt.Sprintf("%s must have at least %d items, got %d", name, f.MinItems, count)}
+	}
+	if f.MaxItems > 0 && count > f.MaxItems {
+		return []string{fmt.Sprintf("%s must have at most %d items, got %d", name, f.MaxItems, count)}
+	}
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,190 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// defaultMaxAttempts is how many times a structured prompt is tried before giving up
+const defaultMaxAttempts = 3
+
+// Provider executes prompts against a language model
+type Provider interface {
+	Execute(ctx context.Context, prompt string) (string, error)
+}
+
+// MaskingProvider is a provider whose prompts carry placeholders for masked text
+type MaskingProvider interface {
+	Provider
+	// ExecuteMasked returns the response with placeholders left in place
+	ExecuteMasked(ctx context.Context, prompt string) (string, error)
+	// Restore puts the masked text back in place of the placeholders in text
+	Restore(text string) string
+}
+
+// InvalidResponseError reports that the model kept returning output that did not match the schema
+type InvalidResponseError struct {
+	Attempts     int
+	LastResponse string
+	Err          error
+}
+
+// Error implements the error interface
+func (e *InvalidResponseError) Error() string {
+	return fmt.Sprintf("invalid model response after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap allows errors.Is and errors.As to work
+func (e *InvalidResponseError) Unwrap() error {
+	return e.Err
+}
+
+// StructuredExecutor runs prompts whose responses must be JSON matching a schema
+type StructuredExecutor struct {
+	provider    Provider
+	maxAttempts int
+}
+
+// NewStructuredExecutor creates a structured executor on top of a provider
+func NewStructuredExecutor(provider Provider) *StructuredExecutor {
+	return &StructuredExecutor{
+		provider:    provider,
+		maxAttempts: defaultMaxAttempts,
+	}
+}
+
+// SetMaxAttempts sets how many attempts are made before returning InvalidResponseError
+func (e *StructuredExecutor) SetMaxAttempts(attempts int) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	e.maxAttempts = attempts
+}
+
+// Execute sends the prompt, validates the response against schema and decodes it into result.
+// Invalid responses are sent back to the model with a repair prompt until maxAttempts is reached.
+// With a MaskingProvider, repairs keep the placeholders and masked text is only
+// restored in the decoded strings, so it never has to be valid JSON.
+func (e *StructuredExecutor) Execute(ctx context.Context, prompt string, schema *Schema, result interface{}) error {
+	execute := e.provider.Execute
+	restore := func(text string) string { return text }
+	if masking, ok := e.provider.(MaskingProvider); ok {
+		execute = masking.ExecuteMasked
+		restore = masking.Restore
+	}
+
+	currentPrompt := prompt
+	var lastResponse string
+	var lastErr error
+
+	for attempt := 1; attempt <= e.maxAttempts; attempt++ {
+		response, err := execute(ctx, currentPrompt)
+		if err != nil {
+			// Provider failures are not something the model can repair
+			return err
+		}
+		lastResponse = response
+
+		payload := ExtractJSON(response)
+		if err := schema.Validate([]byte(payload)); err != nil {
+			lastErr = err
+			currentPrompt = buildRepairPrompt(prompt, response, schema, err)
+			continue
+		}
+
+		if err := json.Unmarshal([]byte(payload), result); err != nil {
+			lastErr = err
+			currentPrompt = buildRepairPrompt(prompt, response, schema, err)
+			continue
+		}
+
+		restoreStrings(reflect.ValueOf(result), restore)
+		return nil
+	}
+
+	return &InvalidResponseError{
+		Attempts:     e.maxAttempts,
+		LastResponse: restore(lastResponse),
+		Err:          lastErr,
+	}
+}
+
+// restoreStrings applies restore to every string reachable from v, in place
+func restoreStrings(v reflect.Value, restore func(string) string) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(restore(v.String()))
+		}
+	case reflect.Pointer:
+		if !v.IsNil() {
+			restoreStrings(v.Elem(), restore)
+		}
+	case reflect.Interface:
+		// Values held in interfaces cannot be set, so restore a copy
+		if !v.IsNil() && v.CanSet() {
+			restored := reflect.New(v.Elem().Type()).Elem()
+			restored.Set(v.Elem())
+			restoreStrings(restored, restore)
+			v.Set(restored)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			restoreStrings(v.Field(i), restore)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			restoreStrings(v.Index(i), restore)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			restored := reflect.New(v.Type().Elem()).Elem()
+			restored.Set(v.MapIndex(key))
+			restoreStrings(restored, restore)
+			v.SetMapIndex(key, restored)
+		}
+	}
+}
+
+// ExtractJSON returns the JSON object contained in a model response,
+// tolerating surrounding markdown fences or prose
+func ExtractJSON(response string) string {
+	response = strings.TrimSpace(response)
+
+	// Strip ```json ... ``` or ``` ... ``` fences
+	if start := strings.Index(response, "```"); start != -1 {
+		body := response[start+3:]
+		body = strings.TrimPrefix(body, "json")
+		if end := strings.Index(body, "```"); end != -1 {
+			return strings.TrimSpace(body[:end])
+		}
+	}
+
+	// Fall back to the outermost braces
+	start := strings.Index(response, "{")
+	end := strings.LastIndex(response, "}")
+	if start != -1 && end > start {
+		return response[start : end+1]
+	}
+
+	return response
+}
+
+// buildRepairPrompt asks the model to fix a response that failed validation
+func buildRepairPrompt(originalPrompt, response string, schema *Schema, validationErr error) string {
+	var b strings.Builder
+
+	b.WriteString(originalPrompt)
+	b.WriteString("\n\nYour previous response was invalid:\n")
+	b.WriteString(response)
+	b.WriteString("\n\nProblems:\n")
+	b.WriteString(validationErr.Error())
+	b.WriteString("\n\nThe response must be a single JSON object with these fields:\n")
+	b.WriteString(schema.Describe())
+	b.WriteString("\n\nReturn ONLY the corrected JSON (no markdown, no explanation).")
+
+	return b.String()
+}
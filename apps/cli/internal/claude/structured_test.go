@@ -0,0 +1,210 @@
+package claude_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/tennashi/tabler/internal/claude"
+)
+
+var testSchema = &claude.Schema{
+	Fields: []claude.Field{
+		{Name: "title", Type: claude.StringField, Required: true},
+		{Name: "priority", Type: claude.StringField, Enum: []string{"low", "medium", "high"}, AllowEmpty: true},
+		{Name: "deadline", Type: claude.StringField, Format: "2006-01-02", AllowEmpty: true},
+		{Name: "tags", Type: claude.StringListField, MaxItems: 2, Pattern: regexp.MustCompile(`^[a-z]+$`)},
+		{Name: "steps", Type: claude.NumberListField, Min: 1, Max: 10},
+	},
+}
+
+type testResult struct {
+	Title    string   `json:"title"`
+	Priority string   `json:"priority"`
+	Deadline string   `json:"deadline"`
+	Tags     []string `json:"tags"`
+}
+
+func TestSchema(t *testing.T) {
+	t.Run("Validate", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			input     string
+			wantValid bool
+		}{
+			{"valid object", `{"title": "Write report", "priority": "high", "deadline": "2024-01-16", "tags": ["work"]}`, true},
+			{"empty optional values", `{"title": "Write report", "priority": "", "deadline": ""}`, true},
+			{"missing required field", `{"priority": "high"}`, false},
+			{"unknown priority", `{"title": "Write report", "priority": "urgent"}`, false},
+			{"bad date format", `{"title": "Write report", "deadline": "tomorrow"}`, false},
+			{"too many tags", `{"title": "Write report", "tags": ["a", "b", "c"]}`, false},
+			{"tag with spaces", `{"title": "Write report", "tags": ["two words"]}`, false},
+			{"valid step numbers", `{"title": "Write report", "steps": [1, 2]}`, true},
+			{"step out of range", `{"title": "Write report", "steps": [0]}`, false},
+			{"step that is not a number", `{"title": "Write report", "steps": ["1"]}`, false},
+			{"not an object", `Write report`, false},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Act
+				err := testSchema.Validate([]byte(tt.input))
+
+				// Assert
+				if tt.wantValid && err != nil {
+					t.Errorf("expected valid, got %v", err)
+				}
+				if !tt.wantValid && err == nil {
+					t.Error("expected validation error")
+				}
+			})
+		}
+	})
+}
+
+func TestStructuredExecutor(t *testing.T) {
+	t.Run("decodes fenced JSON", func(t *testing.T) {
+		// Arrange
+		provider := &mockProvider{responses: []string{"```json\n{\"title\": \"Write report\"}\n```"}}
+		executor := claude.NewStructuredExecutor(provider)
+
+		// Act
+		var result testResult
+		err := executor.Execute(context.Background(), "prompt", testSchema, &result)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Title != "Write report" {
+			t.Errorf("expected title %q, got %q", "Write report", result.Title)
+		}
+	})
+
+	t.Run("retries with repair prompt", func(t *testing.T) {
+		// Arrange
+		provider := &mockProvider{responses: []string{
+			`{"title": "Write report", "priority": "urgent"}`,
+			`{"title": "Write report", "priority": "high"}`,
+		}}
+		executor := claude.NewStructuredExecutor(provider)
+
+		// Act
+		var result testResult
+		err := executor.Execute(context.Background(), "prompt", testSchema, &result)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(provider.prompts) != 2 {
+			t.Fatalf("expected 2 calls, got %d", len(provider.prompts))
+		}
+		if !strings.Contains(provider.prompts[1], "priority must be one of low/medium/high") {
+			t.Errorf("expected repair prompt to explain the problem, got %q", provider.prompts[1])
+		}
+		if result.Priority != "high" {
+			t.Errorf("expected priority %q, got %q", "high", result.Priority)
+		}
+	})
+
+	t.Run("returns InvalidResponseError after max attempts", func(t *testing.T) {
+		// Arrange
+		provider := &mockProvider{responses: []string{"not json"}}
+		executor := claude.NewStructuredExecutor(provider)
+		executor.SetMaxAttempts(2)
+
+		// Act
+		var result testResult
+		err := executor.Execute(context.Background(), "prompt", testSchema, &result)
+
+		// Assert
+		var invalidErr *claude.InvalidResponseError
+		if !errors.As(err, &invalidErr) {
+			t.Fatalf("expected InvalidResponseError, got %v", err)
+		}
+		if invalidErr.Attempts != 2 {
+			t.Errorf("expected 2 attempts, got %d", invalidErr.Attempts)
+		}
+		if invalidErr.LastResponse != "not json" {
+			t.Errorf("expected last response %q, got %q", "not json", invalidErr.LastResponse)
+		}
+	})
+
+	t.Run("restores masked text in decoded fields only", func(t *testing.T) {
+		// Arrange
+		provider := &maskingProvider{
+			mockProvider: mockProvider{responses: []string{
+				`{"title": "Open [REDACTED_1]", "priority": "urgent"}`,
+				`{"title": "Open [REDACTED_1]", "priority": "high"}`,
+			}},
+			restorer: strings.NewReplacer("[REDACTED_1]", `C:\share\"Q4"`),
+		}
+		executor := claude.NewStructuredExecutor(provider)
+
+		// Act
+		var result testResult
+		err := executor.Execute(context.Background(), "prompt", testSchema, &result)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Title != `Open C:\share\"Q4"` {
+			t.Errorf("expected restored title, got %q", result.Title)
+		}
+		if len(provider.prompts) != 2 || strings.Contains(provider.prompts[1], "Q4") {
+			t.Errorf("expected the repair prompt to keep placeholders, got %q", provider.prompts)
+		}
+	})
+
+	t.Run("does not retry provider failures", func(t *testing.T) {
+		// Arrange
+		provider := &mockProvider{err: errors.New("claude CLI not found")}
+		executor := claude.NewStructuredExecutor(provider)
+
+		// Act
+		var result testResult
+		err := executor.Execute(context.Background(), "prompt", testSchema, &result)
+
+		// Assert
+		if err == nil || err.Error() != "claude CLI not found" {
+			t.Errorf("expected provider error, got %v", err)
+		}
+		if len(provider.prompts) != 1 {
+			t.Errorf("expected 1 call, got %d", len(provider.prompts))
+		}
+	})
+}
+
+// maskingProvider returns canned responses with placeholders left in place
+type maskingProvider struct {
+	mockProvider
+	restorer *strings.Replacer
+}
+
+func (m *maskingProvider) ExecuteMasked(ctx context.Context, prompt string) (string, error) {
+	return m.mockProvider.Execute(ctx, prompt)
+}
+
+func (m *maskingProvider) Restore(text string) string {
+	return m.restorer.Replace(text)
+}
+
+// mockProvider returns canned responses in order, repeating the last one
+type mockProvider struct {
+	responses []string
+	err       error
+	prompts   []string
+}
+
+func (m *mockProvider) Execute(_ context.Context, prompt string) (string, error) {
+	m.prompts = append(m.prompts, prompt)
+	if m.err != nil {
+		return "", m.err
+	}
+	i := len(m.prompts) - 1
+	if i >= len(m.responses) {
+		i = len(m.responses) - 1
+	}
+	return m.responses[i], nil
+}
@@ -0,0 +1,84 @@
+package claude
+
+import (
+	"context"
+	"time"
+)
+
+// Features that call the model, used to attribute usage
+const (
+	FeatureMetadata      = "metadata"
+	FeatureClarification = "clarification"
+	FeatureDecomposition = "decomposition"
+	FeatureEnrichment    = "enrichment"
+)
+
+type featureKey struct{}
+
+// WithFeature records which feature is making AI calls in the context
+func WithFeature(ctx context.Context, feature string) context.Context {
+	return context.WithValue(ctx, featureKey{}, feature)
+}
+
+// FeatureFromContext retrieves the feature making AI calls
+func FeatureFromContext(ctx context.Context) string {
+	if feature, ok := ctx.Value(featureKey{}).(string); ok {
+		return feature
+	}
+	return "unknown"
+}
+
+// Usage describes a single provider call
+type Usage struct {
+	Feature       string
+	StartedAt     time.Time
+	Latency       time.Duration
+	PromptBytes   int
+	ResponseBytes int
+	Success       bool
+	Error         string
+}
+
+// UsageRecorder persists provider call statistics
+type UsageRecorder interface {
+	RecordUsage(usage *Usage) error
+}
+
+// MeteredProvider records latency, size and outcome of every call to another provider
+type MeteredProvider struct {
+	provider Provider
+	recorder UsageRecorder
+	now      func() time.Time
+}
+
+// NewMeteredProvider creates a provider that reports each call to recorder
+func NewMeteredProvider(provider Provider, recorder UsageRecorder) *MeteredProvider {
+	return &MeteredProvider{
+		provider: provider,
+		recorder: recorder,
+		now:      time.Now,
+	}
+}
+
+// Execute implements Provider
+func (p *MeteredProvider) Execute(ctx context.Context, prompt string) (string, error) {
+	start := p.now()
+	response, err := p.provider.Execute(ctx, prompt)
+
+	usage := &Usage{
+		Feature:       FeatureFromContext(ctx),
+		StartedAt:     start,
+		Latency:       p.now().Sub(start),
+		PromptBytes:   len(prompt),
+		ResponseBytes: len(response),
+		Success:       err == nil,
+	}
+	if err != nil {
+		usage.Error = err.Error()
+	}
+
+	// Accounting must never break the AI call itself
+	_ = p.recorder.RecordUsage(usage)
+
+	return response, err
+}
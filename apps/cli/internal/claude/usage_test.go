@@ -0,0 +1,68 @@
+package claude_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tennashi/tabler/internal/claude"
+)
+
+func TestMeteredProvider(t *testing.T) {
+	t.Run("records successful call", func(t *testing.T) {
+		// Arrange
+		recorder := &mockRecorder{}
+		provider := claude.NewMeteredProvider(&mockProvider{responses: []string{"COMPLETE"}}, recorder)
+		ctx := claude.WithFeature(context.Background(), claude.FeatureClarification)
+
+		// Act
+		_, err := provider.Execute(ctx, "prompt")
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(recorder.usages) != 1 {
+			t.Fatalf("expected 1 usage record, got %d", len(recorder.usages))
+		}
+		usage := recorder.usages[0]
+		if usage.Feature != claude.FeatureClarification {
+			t.Errorf("expected feature %q, got %q", claude.FeatureClarification, usage.Feature)
+		}
+		if usage.PromptBytes != 6 || usage.ResponseBytes != 8 {
+			t.Errorf("expected sizes 6/8, got %d/%d", usage.PromptBytes, usage.ResponseBytes)
+		}
+		if !usage.Success {
+			t.Error("expected call to be recorded as successful")
+		}
+	})
+
+	t.Run("records failed call and returns error", func(t *testing.T) {
+		// Arrange
+		recorder := &mockRecorder{err: errors.New("disk full")}
+		provider := claude.NewMeteredProvider(&mockProvider{err: errors.New("timeout")}, recorder)
+
+		// Act
+		_, err := provider.Execute(context.Background(), "prompt")
+
+		// Assert
+		if err == nil || err.Error() != "timeout" {
+			t.Errorf("expected provider error, got %v", err)
+		}
+		if len(recorder.usages) != 1 || recorder.usages[0].Success {
+			t.Fatal("expected failed call to be recorded")
+		}
+		if recorder.usages[0].Feature != "unknown" {
+			t.Errorf("expected unknown feature, got %q", recorder.usages[0].Feature)
+		}
+	})
+}
+
+type mockRecorder struct {
+	usages []*claude.Usage
+	err    error
+}
+
+func (m *mockRecorder) RecordUsage(usage *claude.Usage) error {
+	m.usages = append(m.usages, usage)
+	return m.err
+}
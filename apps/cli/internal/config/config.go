@@ -0,0 +1,209 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/tennashi/tabler/internal/language"
+	"github.com/tennashi/tabler/internal/parser"
+)
+
+// fileName is the configuration file inside the data directory
+const fileName = "config.json"
+
+// Config holds user settings read from <data dir>/config.json
+type Config struct {
+	// Language is the task input language: "auto" (default), "en" or "ja"
+	Language string   `json:"language"`
+	Privacy  Privacy  `json:"privacy"`
+	Learning Learning `json:"learning"`
+	Urgency  Urgency  `json:"urgency"`
+	List     List     `json:"list"`
+	Tags     Tags     `json:"tags"`
+	Planning Planning `json:"planning"`
+}
+
+// InputLanguage returns the configured input language
+func (c *Config) InputLanguage() language.Language {
+	lang, err := language.Parse(c.Language)
+	if err != nil {
+		// Load rejects unsupported languages, so only hand-built configs get here
+		return language.Auto
+	}
+	return lang
+}
+
+// Privacy controls what task text may be sent to the AI provider
+type Privacy struct {
+	// RedactEmails masks email addresses before prompts leave the machine
+	RedactEmails bool `json:"redact_emails"`
+	// RedactURLs masks http(s) and www links
+	RedactURLs bool `json:"redact_urls"`
+	// RedactPhones masks phone numbers
+	RedactPhones bool `json:"redact_phones"`
+	// Patterns are extra regular expressions whose matches are masked
+	Patterns []string `json:"patterns"`
+	// PrivateTags are tags whose names are masked in prompts
+	PrivateTags []string `json:"private_tags"`
+	// ExcludedTags mark tasks that are never sent to the AI provider
+	ExcludedTags []string `json:"excluded_tags"`
+}
+
+// Learning controls the local pattern tracker
+type Learning struct {
+	// Enabled turns pattern recording on or off
+	Enabled bool `json:"enabled"`
+	// RetentionDays drops patterns unused for this many days (0 keeps them forever)
+	RetentionDays int `json:"retention_days"`
+	// ExcludedTags are never learned
+	ExcludedTags []string `json:"excluded_tags"`
+}
+
+// Urgency weighs what makes a task urgent when recommending what to do next
+type Urgency struct {
+	// Priority is added per priority level
+	Priority float64 `json:"priority"`
+	// Deadline is added to overdue tasks and fades out over DeadlineHorizonDays
+	Deadline float64 `json:"deadline"`
+	// DeadlineHorizonDays is how many days ahead a deadline starts to count
+	DeadlineHorizonDays int `json:"deadline_horizon_days"`
+	// Age is added per week since the task was created, for up to four weeks
+	Age float64 `json:"age"`
+	// Blocked is subtracted from tasks waiting on dependencies or subtasks
+	Blocked float64 `json:"blocked"`
+	// Tags are added for each matching tag, e.g. {"work": 2, "someday": -3}; a
+	// weight on a tag also counts for its descendants
+	Tags map[string]float64 `json:"tags"`
+}
+
+// List controls how `tabler list` shows tasks
+type List struct {
+	// Sort is the default sort spec, e.g. "deadline,-priority"; empty lists the newest first
+	Sort string `json:"sort"`
+}
+
+// Tags controls how tag names are normalized when tasks are saved and filtered
+type Tags struct {
+	// Lowercase folds tags to lower case so #Work and #work are one tag
+	Lowercase bool `json:"lowercase"`
+	// StripPunctuation trims punctuation around tags, e.g. "#work," becomes work
+	StripPunctuation bool `json:"strip_punctuation"`
+	// Aliases replace a tag with another, e.g. {"job": "work"}; descendants follow
+	Aliases map[string]string `json:"aliases"`
+}
+
+// Planning controls how planning mode breaks tasks down
+type Planning struct {
+	// MaxDepth limits how many levels of subtasks a plan may have; 1 keeps plans flat
+	MaxDepth int `json:"max_depth"`
+}
+
+// Rules returns the tag settings as normalization rules
+func (t Tags) Rules() parser.TagRules {
+	return parser.TagRules{
+		Lowercase:        t.Lowercase,
+		StripPunctuation: t.StripPunctuation,
+		Aliases:          t.Aliases,
+	}
+}
+
+// Default returns the settings used when no config file exists
+func Default() *Config {
+	return &Config{
+		Language: string(language.Auto),
+		Privacy: Privacy{
+			RedactEmails: true,
+			RedactURLs:   true,
+			RedactPhones: true,
+		},
+		Learning: Learning{
+			Enabled:       true,
+			RetentionDays: 90,
+		},
+		Urgency: Urgency{
+			Priority:            3,
+			Deadline:            10,
+			DeadlineHorizonDays: 14,
+			Age:                 1,
+			Blocked:             20,
+		},
+		Tags: Tags{
+			Lowercase:        true,
+			StripPunctuation: true,
+		},
+		Planning: Planning{
+			MaxDepth: 2,
+		},
+	}
+}
+
+// Path returns the config file location for a data directory
+func Path(dataDir string) string {
+	return filepath.Join(dataDir, fileName)
+}
+
+// Load reads the config file from dataDir, falling back to defaults for missing settings
+func Load(dataDir string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(Path(dataDir)) // #nosec G304 - data dir is user-controlled
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", Path(dataDir), err)
+	}
+
+	if _, err := language.Parse(cfg.Language); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", Path(dataDir), err)
+	}
+
+	if cfg.Urgency.DeadlineHorizonDays < 1 {
+		return nil, fmt.Errorf("invalid config %s: urgency.deadline_horizon_days must be at least 1", Path(dataDir))
+	}
+
+	// Weights are looked up by stored tag names
+	weights, err := normalizeTagWeights(cfg.Urgency.Tags, cfg.Tags.Rules())
+	if err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", Path(dataDir), err)
+	}
+	cfg.Urgency.Tags = weights
+
+	if cfg.Planning.MaxDepth < 1 {
+		return nil, fmt.Errorf("invalid config %s: planning.max_depth must be at least 1", Path(dataDir))
+	}
+
+	return cfg, nil
+}
+
+// normalizeTagWeights normalizes the tags of urgency weights with rules,
+// rejecting tags that become empty or the same as another
+func normalizeTagWeights(weights map[string]float64, rules parser.TagRules) (map[string]float64, error) {
+	if weights == nil {
+		return nil, nil
+	}
+
+	normalized := make(map[string]float64, len(weights))
+	sources := make(map[string]string, len(weights))
+	for _, tag := range slices.Sorted(maps.Keys(weights)) {
+		name := parser.NormalizeTag(tag, rules)
+		if name == "" {
+			return nil, fmt.Errorf("urgency.tags has an empty tag %q", tag)
+		}
+		if source, ok := sources[name]; ok {
+			return nil, fmt.Errorf("urgency.tags has both %q and %q for tag %s", source, tag, name)
+		}
+		normalized[name] = weights[tag]
+		sources[name] = tag
+	}
+	return normalized, nil
+}
@@ -0,0 +1,213 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/tennashi/tabler/internal/config"
+	"github.com/tennashi/tabler/internal/language"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("should return defaults when no config file exists", func(t *testing.T) {
+		// Act
+		cfg, err := config.Load(t.TempDir())
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !cfg.Privacy.RedactEmails || !cfg.Privacy.RedactURLs || !cfg.Privacy.RedactPhones {
+			t.Errorf("expected redaction to be enabled by default, got %+v", cfg.Privacy)
+		}
+	})
+
+	t.Run("should keep defaults for settings missing from the file", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		content := `{"privacy": {"redact_urls": false, "excluded_tags": ["secret"]}}`
+		if err := os.WriteFile(config.Path(dir), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		cfg, err := config.Load(dir)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Privacy.RedactURLs {
+			t.Error("expected URL redaction to be disabled")
+		}
+		if !cfg.Privacy.RedactEmails {
+			t.Error("expected email redaction to stay enabled")
+		}
+		if len(cfg.Privacy.ExcludedTags) != 1 || cfg.Privacy.ExcludedTags[0] != "secret" {
+			t.Errorf("expected excluded tags [secret], got %v", cfg.Privacy.ExcludedTags)
+		}
+		if cfg.InputLanguage() != language.Auto {
+			t.Errorf("expected auto language, got %s", cfg.InputLanguage())
+		}
+	})
+
+	t.Run("should merge urgency weights with the defaults", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		content := `{"urgency": {"age": 0, "tags": {"work": 2}}}`
+		if err := os.WriteFile(config.Path(dir), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		cfg, err := config.Load(dir)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Urgency.Age != 0 || cfg.Urgency.Tags["work"] != 2 {
+			t.Errorf("expected configured weights, got %+v", cfg.Urgency)
+		}
+		if cfg.Urgency.Priority != config.Default().Urgency.Priority {
+			t.Errorf("expected default priority weight, got %v", cfg.Urgency.Priority)
+		}
+	})
+
+	t.Run("should normalize the tags of urgency weights", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		content := `{"urgency": {"tags": {"Work": 2, "#Home,": -1}}}`
+		if err := os.WriteFile(config.Path(dir), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		cfg, err := config.Load(dir)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(cfg.Urgency.Tags) != 2 || cfg.Urgency.Tags["work"] != 2 || cfg.Urgency.Tags["home"] != -1 {
+			t.Errorf("expected weights for work and home, got %v", cfg.Urgency.Tags)
+		}
+	})
+
+	t.Run("should reject urgency weights for the same tag", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		if err := os.WriteFile(config.Path(dir), []byte(`{"urgency": {"tags": {"Work": 1, "work": 2}}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		_, err := config.Load(dir)
+
+		// Assert
+		if err == nil {
+			t.Error("expected an error for two weights on one tag")
+		}
+	})
+
+	t.Run("should read tag rules on top of the defaults", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		content := `{"tags": {"strip_punctuation": false, "aliases": {"job": "work"}}}`
+		if err := os.WriteFile(config.Path(dir), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		cfg, err := config.Load(dir)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		rules := cfg.Tags.Rules()
+		if !rules.Lowercase || rules.StripPunctuation {
+			t.Errorf("expected lower-casing only, got %+v", rules)
+		}
+		if rules.Aliases["job"] != "work" {
+			t.Errorf("expected alias job -> work, got %v", rules.Aliases)
+		}
+	})
+
+	t.Run("should reject a deadline horizon shorter than a day", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		if err := os.WriteFile(config.Path(dir), []byte(`{"urgency": {"deadline_horizon_days": 0}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		_, err := config.Load(dir)
+
+		// Assert
+		if err == nil {
+			t.Error("expected an error for a zero deadline horizon")
+		}
+	})
+
+	t.Run("should read the planning depth", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		if err := os.WriteFile(config.Path(dir), []byte(`{"planning": {"max_depth": 3}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		cfg, err := config.Load(dir)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if cfg.Planning.MaxDepth != 3 {
+			t.Errorf("expected max depth 3, got %d", cfg.Planning.MaxDepth)
+		}
+	})
+
+	t.Run("should reject a planning depth below one", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		if err := os.WriteFile(config.Path(dir), []byte(`{"planning": {"max_depth": 0}}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		_, err := config.Load(dir)
+
+		// Assert
+		if err == nil {
+			t.Error("expected an error for a zero planning depth")
+		}
+	})
+
+	t.Run("should reject unsupported languages", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		if err := os.WriteFile(config.Path(dir), []byte(`{"language": "fr"}`), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		_, err := config.Load(dir)
+
+		// Assert
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("should reject malformed config", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		if err := os.WriteFile(config.Path(dir), []byte("{"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		_, err := config.Load(dir)
+
+		// Assert
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -3,75 +3,189 @@ package decomposition
 import (
 	"context"
 	"fmt"
-	"strings"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/tennashi/tabler/internal/claude"
+	"github.com/tennashi/tabler/internal/prompts"
 )
 
+// defaultRationale is used when Claude does not explain its breakdown
+const defaultRationale = "Task broken down into actionable steps"
+
+// decompositionSchema declares the response the decomposition prompt must produce
+var decompositionSchema = &claude.Schema{
+	Fields: []claude.Field{
+		{
+			Name:     "subtasks",
+			Type:     claude.ObjectListField,
+			Required: true,
+			MinItems: 1,
+			MaxItems: 10,
+			Items: &claude.Schema{
+				Fields: []claude.Field{
+					{Name: "title", Type: claude.StringField, Required: true, MaxLength: 200},
+					{Name: "effort_minutes", Type: claude.NumberField, Min: 1, Max: 10080},
+					{Name: "order", Type: claude.NumberField, Min: 1, Max: 100},
+					{Name: "depends_on", Type: claude.NumberListField, Min: 1, Max: 100, MaxItems: 10},
+				},
+			},
+		},
+		{Name: "rationale", Type: claude.StringField, MaxLength: 500},
+	},
+}
+
+// regenerationSchema declares the response the subtask regeneration prompt must produce
+var regenerationSchema = &claude.Schema{
+	Fields: []claude.Field{
+		{Name: "subtask", Type: claude.StringField, Required: true, MaxLength: 200},
+	},
+}
+
 // ClaudeClient defines the interface for Claude interaction
 type ClaudeClient interface {
 	Execute(ctx context.Context, prompt string) (string, error)
 }
 
-// DecompositionResult contains the decomposed subtasks
+// DecompositionResult contains the decomposed subtasks in their suggested order
 type DecompositionResult struct {
 	OriginalTask string
-	Subtasks     []string
+	Subtasks     []Subtask
 	Rationale    string
+	PromptID     string
 }
 
 // TaskDecomposer generates subtask suggestions using Claude
 type TaskDecomposer struct {
-	claude ClaudeClient
+	executor *claude.StructuredExecutor
+	prompts  *prompts.Library
 }
 
 // NewTaskDecomposer creates a new task decomposer
-func NewTaskDecomposer(claude ClaudeClient) *TaskDecomposer {
+func NewTaskDecomposer(claudeClient ClaudeClient) *TaskDecomposer {
 	return &TaskDecomposer{
-		claude: claude,
+		executor: claude.NewStructuredExecutor(claudeClient),
+		prompts:  prompts.Default(),
 	}
 }
 
+// SetPrompts sets the prompt library used to build decomposition prompts
+func (d *TaskDecomposer) SetPrompts(library *prompts.Library) {
+	d.prompts = library
+}
+
+type decompositionResponse struct {
+	Subtasks  []subtaskResponse `json:"subtasks"`
+	Rationale string            `json:"rationale"`
+}
+
+type subtaskResponse struct {
+	Title         string    `json:"title"`
+	EffortMinutes float64   `json:"effort_minutes"`
+	Order         float64   `json:"order"`
+	DependsOn     []float64 `json:"depends_on"`
+}
+
 // Decompose breaks down a complex task into subtasks
 func (d *TaskDecomposer) Decompose(ctx context.Context, task string) (*DecompositionResult, error) {
 	// Create prompt for Claude
-	prompt := fmt.Sprintf(`Break down this task into clear, actionable subtasks:
-Task: "%s"
-
-Please provide 3-7 specific subtasks that would complete this task.
-Format each subtask as a numbered list item.
-Keep each subtask concise and actionable.`, task)
-
-	// Call Claude
-	response, err := d.claude.Execute(ctx, prompt)
+	prompt, err := d.prompts.Render(prompts.Decomposition, struct{ Task string }{Task: task})
 	if err != nil {
+		return nil, err
+	}
+
+	// Call Claude and validate the response
+	ctx = claude.WithFeature(ctx, claude.FeatureDecomposition)
+	var response decompositionResponse
+	if err := d.executor.Execute(ctx, prompt.Text, decompositionSchema, &response); err != nil {
 		return nil, fmt.Errorf("failed to get decomposition from Claude: %w", err)
 	}
 
-	// Parse response
-	subtasks := d.parseSubtasks(response)
+	rationale := response.Rationale
+	if rationale == "" {
+		rationale = defaultRationale
+	}
 
 	return &DecompositionResult{
 		OriginalTask: task,
-		Subtasks:     subtasks,
-		Rationale:    "Task broken down into actionable steps",
+		Subtasks:     orderSubtasks(response.Subtasks),
+		Rationale:    rationale,
+		PromptID:     prompt.ID,
 	}, nil
 }
 
-// parseSubtasks extracts subtasks from Claude's response
-func (d *TaskDecomposer) parseSubtasks(response string) []string {
-	var subtasks []string
-	lines := strings.Split(response, "\n")
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		// Look for numbered items (1. , 2. , etc.)
-		if len(line) > 2 && line[0] >= '1' && line[0] <= '9' && line[1] == '.' {
-			// Extract the task text after the number
-			taskText := strings.TrimSpace(line[2:])
-			if taskText != "" {
-				subtasks = append(subtasks, taskText)
-			}
+// orderSubtasks sorts subtasks by their suggested order and numbers them from 1.
+// Dependencies refer to order numbers; ones on unknown or later subtasks are
+// dropped so the result never contains a cycle.
+func orderSubtasks(responses []subtaskResponse) []Subtask {
+	type ordered struct {
+		order    int
+		response subtaskResponse
+	}
+	steps := make([]ordered, len(responses))
+	for i, response := range responses {
+		order := int(response.Order)
+		if order == 0 {
+			order = i + 1
 		}
+		steps[i] = ordered{order: order, response: response}
 	}
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].order < steps[j].order
+	})
 
+	// Map order numbers to subtask IDs, the first subtask winning duplicates
+	ids := make(map[int]int, len(steps))
+	for i, step := range steps {
+		if _, ok := ids[step.order]; !ok {
+			ids[step.order] = i + 1
+		}
+	}
+
+	subtasks := make([]Subtask, len(steps))
+	for i, step := range steps {
+		id := i + 1
+		subtask := Subtask{
+			ID:     id,
+			Title:  step.response.Title,
+			Effort: time.Duration(step.response.EffortMinutes) * time.Minute,
+		}
+		for _, order := range step.response.DependsOn {
+			dependency, ok := ids[int(order)]
+			if ok && dependency < id && !slices.Contains(subtask.DependsOn, dependency) {
+				subtask.DependsOn = append(subtask.DependsOn, dependency)
+			}
+		}
+		subtasks[i] = subtask
+	}
 	return subtasks
 }
+
+type regenerationResponse struct {
+	Subtask string `json:"subtask"`
+}
+
+// Regenerate asks Claude for a replacement of the subtask at index, keeping the others in view
+func (d *TaskDecomposer) Regenerate(ctx context.Context, task string, subtasks []string, index int) (string, error) {
+	if index < 0 || index >= len(subtasks) {
+		return "", fmt.Errorf("subtask index out of bounds: %d", index)
+	}
+
+	prompt, err := d.prompts.Render(prompts.SubtaskRegeneration, struct {
+		Task     string
+		Subtasks []string
+		Index    int
+	}{Task: task, Subtasks: subtasks, Index: index})
+	if err != nil {
+		return "", err
+	}
+
+	ctx = claude.WithFeature(ctx, claude.FeatureDecomposition)
+	var response regenerationResponse
+	if err := d.executor.Execute(ctx, prompt.Text, regenerationSchema, &response); err != nil {
+		return "", fmt.Errorf("failed to regenerate subtask with Claude: %w", err)
+	}
+
+	return response.Subtask, nil
+}
@@ -2,7 +2,13 @@ package decomposition
 
 import (
 	"context"
+	"errors"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	tablerclaude "github.com/tennashi/tabler/internal/claude"
 )
 
 func TestTaskDecomposer(t *testing.T) {
@@ -12,14 +18,17 @@ func TestTaskDecomposer(t *testing.T) {
 			claude := &mockClaudeClient{
 				executeFunc: func(_ context.Context, _ string) (string, error) {
 					// Simulate Claude response
-					return `Here are the subtasks for "organize conference":
-
-1. Book venue for conference
-2. Create conference schedule and agenda
-3. Invite speakers and confirm attendance
-4. Setup registration system
-5. Arrange catering and refreshments
-6. Prepare conference materials and badges`, nil
+					return `{
+  "subtasks": [
+    {"title": "Book venue for conference", "effort_minutes": 120, "order": 1, "depends_on": []},
+    {"title": "Create conference schedule and agenda", "effort_minutes": 180, "order": 2, "depends_on": [1]},
+    {"title": "Invite speakers and confirm attendance", "effort_minutes": 240, "order": 3, "depends_on": [2]},
+    {"title": "Setup registration system", "effort_minutes": 90, "order": 4, "depends_on": [1]},
+    {"title": "Arrange catering and refreshments", "effort_minutes": 60, "order": 5, "depends_on": [1]},
+    {"title": "Prepare conference materials and badges", "effort_minutes": 120, "order": 6, "depends_on": [3, 4]}
+  ],
+  "rationale": "Covers venue, content, people and logistics"
+}`, nil
 				},
 			}
 			decomposer := NewTaskDecomposer(claude)
@@ -34,8 +43,137 @@ func TestTaskDecomposer(t *testing.T) {
 			if len(result.Subtasks) != 6 {
 				t.Errorf("expected 6 subtasks, got %d", len(result.Subtasks))
 			}
-			if result.Subtasks[0] != "Book venue for conference" {
-				t.Errorf("expected first subtask to be %q, got %q", "Book venue for conference", result.Subtasks[0])
+			if result.Subtasks[0].Title != "Book venue for conference" {
+				t.Errorf("expected first subtask to be %q, got %q", "Book venue for conference", result.Subtasks[0].Title)
+			}
+			if result.Subtasks[0].Effort != 2*time.Hour {
+				t.Errorf("expected effort of 2h, got %v", result.Subtasks[0].Effort)
+			}
+			if !slices.Equal(result.Subtasks[5].DependsOn, []int{3, 4}) {
+				t.Errorf("expected last subtask to depend on 3 and 4, got %v", result.Subtasks[5].DependsOn)
+			}
+		})
+
+		t.Run("should sort subtasks by suggested order and drop impossible dependencies", func(t *testing.T) {
+			// Arrange
+			claude := &mockClaudeClient{
+				executeFunc: func(_ context.Context, _ string) (string, error) {
+					return `{"subtasks": [
+						{"title": "Send invitations", "order": 2, "depends_on": [1, 2, 9]},
+						{"title": "Pick a date", "order": 1, "depends_on": [2]}
+					]}`, nil
+				},
+			}
+			decomposer := NewTaskDecomposer(claude)
+
+			// Act
+			result, err := decomposer.Decompose(context.Background(), "organize offsite")
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Subtasks[0].Title != "Pick a date" || result.Subtasks[1].Title != "Send invitations" {
+				t.Fatalf("expected subtasks in suggested order, got %+v", result.Subtasks)
+			}
+			if len(result.Subtasks[0].DependsOn) != 0 {
+				t.Errorf("expected first subtask to depend on nothing, got %v", result.Subtasks[0].DependsOn)
+			}
+			if !slices.Equal(result.Subtasks[1].DependsOn, []int{result.Subtasks[0].ID}) {
+				t.Errorf("expected second subtask to depend on the first, got %v", result.Subtasks[1].DependsOn)
+			}
+		})
+
+		t.Run("should ask Claude to repair invalid output", func(t *testing.T) {
+			// Arrange
+			var prompts []string
+			claude := &mockClaudeClient{
+				executeFunc: func(_ context.Context, prompt string) (string, error) {
+					prompts = append(prompts, prompt)
+					if len(prompts) == 1 {
+						return "1. Book venue\n2. Invite speakers", nil
+					}
+					return `{"subtasks": [{"title": "Book venue"}, {"title": "Invite speakers"}]}`, nil
+				},
+			}
+			decomposer := NewTaskDecomposer(claude)
+
+			// Act
+			result, err := decomposer.Decompose(context.Background(), "organize conference")
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(prompts) != 2 {
+				t.Fatalf("expected 2 calls to Claude, got %d", len(prompts))
+			}
+			if !strings.Contains(prompts[1], "Your previous response was invalid") {
+				t.Errorf("expected repair prompt, got %q", prompts[1])
+			}
+			if len(result.Subtasks) != 2 {
+				t.Errorf("expected 2 subtasks, got %d", len(result.Subtasks))
+			}
+			if result.Rationale != defaultRationale {
+				t.Errorf("expected default rationale, got %q", result.Rationale)
+			}
+		})
+
+		t.Run("should return typed error when output stays invalid", func(t *testing.T) {
+			// Arrange
+			claude := &mockClaudeClient{
+				executeFunc: func(_ context.Context, _ string) (string, error) {
+					return `{"subtasks": []}`, nil
+				},
+			}
+			decomposer := NewTaskDecomposer(claude)
+
+			// Act
+			_, err := decomposer.Decompose(context.Background(), "organize conference")
+
+			// Assert
+			var invalidErr *tablerclaude.InvalidResponseError
+			if !errors.As(err, &invalidErr) {
+				t.Fatalf("expected InvalidResponseError, got %v", err)
+			}
+		})
+	})
+
+	t.Run("Regenerate", func(t *testing.T) {
+		t.Run("should mark the subtask to replace in the prompt", func(t *testing.T) {
+			// Arrange
+			var prompt string
+			claude := &mockClaudeClient{
+				executeFunc: func(_ context.Context, p string) (string, error) {
+					prompt = p
+					return `{"subtask": "Shortlist three venues"}`, nil
+				},
+			}
+			decomposer := NewTaskDecomposer(claude)
+
+			// Act
+			subtask, err := decomposer.Regenerate(context.Background(), "organize conference",
+				[]string{"Book venue", "Invite speakers"}, 0)
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if subtask != "Shortlist three venues" {
+				t.Errorf("expected regenerated subtask, got %q", subtask)
+			}
+			if !strings.Contains(prompt, "> Book venue") || !strings.Contains(prompt, "  Invite speakers") {
+				t.Errorf("expected the first subtask to be marked, got %q", prompt)
+			}
+		})
+
+		t.Run("should reject an index outside the list", func(t *testing.T) {
+			// Arrange
+			decomposer := NewTaskDecomposer(&mockClaudeClient{})
+
+			// Act
+			_, err := decomposer.Regenerate(context.Background(), "organize conference", []string{"Book venue"}, 1)
+
+			// Assert
+			if err == nil {
+				t.Error("expected error for out of bounds index")
 			}
 		})
 	})
@@ -2,40 +2,85 @@ package decomposition
 
 import (
 	"strings"
+
+	"github.com/tennashi/tabler/internal/language"
 )
 
-// ComplexityDetector identifies tasks that would benefit from decomposition
-type ComplexityDetector struct {
+// complexityVocabulary holds the signals of a complex task in one language
+type complexityVocabulary struct {
 	complexVerbs []string
+	// longLength is the character count above which a task likely holds several actions
+	longLength int
 }
 
-// NewComplexityDetector creates a new complexity detector
-func NewComplexityDetector() *ComplexityDetector {
-	return &ComplexityDetector{
+// complexityVocabularies are the supported languages
+var complexityVocabularies = map[language.Language]*complexityVocabulary{
+	language.English: {
 		complexVerbs: []string{
 			"plan", "organize", "prepare", "develop", "implement",
 			"create", "build", "design", "establish", "setup",
 			"research", "analyze", "review", "refactor", "migrate",
 		},
-	}
+		longLength: 50,
+	},
+	language.Japanese: {
+		complexVerbs: []string{
+			"計画", "企画", "準備", "開発", "実装",
+			"作成", "構築", "設計", "設立", "導入",
+			"調査", "分析", "レビュー", "リファクタ", "移行",
+		},
+		// Japanese packs more meaning into each character
+		longLength: 25,
+	},
+}
+
+// ComplexityDetector identifies tasks that would benefit from decomposition
+type ComplexityDetector struct {
+	language language.Language
+}
+
+// NewComplexityDetector creates a new complexity detector
+func NewComplexityDetector() *ComplexityDetector {
+	return &ComplexityDetector{language: language.Auto}
+}
+
+// SetLanguage fixes the input language instead of detecting it per input
+func (d *ComplexityDetector) SetLanguage(lang language.Language) {
+	d.language = lang
 }
 
 // DetectComplexity analyzes task and returns whether it's complex and why
 func (d *ComplexityDetector) DetectComplexity(input string) (bool, string) {
-	lowerInput := strings.ToLower(input)
+	text := language.Analyze(input, d.language)
+	vocabulary := complexityVocabularies[text.Language]
 
 	// Check for complex verbs
-	for _, verb := range d.complexVerbs {
-		if strings.Contains(lowerInput, verb) {
+	for _, verb := range vocabulary.complexVerbs {
+		if containsVerb(text, verb) {
 			return true, "contains complex verb: " + verb
 		}
 	}
 
-	// Check task length (more than 50 chars suggests multiple actions)
-	if len(input) > 50 {
+	// Check task length (long tasks suggest multiple actions)
+	if text.Length() > vocabulary.longLength {
 		return true, "task is long and may contain multiple actions"
 	}
 
 	// Not complex
 	return false, ""
 }
+
+// containsVerb matches verb forms such as "planning" for "plan" in English
+// and the verb anywhere in Japanese text
+func containsVerb(text *language.Text, verb string) bool {
+	if text.Language == language.Japanese {
+		return text.Has(verb)
+	}
+
+	for _, word := range text.Words {
+		if strings.HasPrefix(word, verb) {
+			return true
+		}
+	}
+	return false
+}
@@ -42,6 +42,24 @@ func TestComplexityDetector(t *testing.T) {
 				expectedComplex: true,
 				expectedReason:  "task is long and may contain multiple actions",
 			},
+			{
+				name:            "Japanese complex verb",
+				input:           "社員旅行を企画する",
+				expectedComplex: true,
+				expectedReason:  "contains complex verb: 企画",
+			},
+			{
+				name:            "Japanese simple task",
+				input:           "牛乳を買う",
+				expectedComplex: false,
+				expectedReason:  "",
+			},
+			{
+				name:            "Japanese long task",
+				input:           "認証システムのバグを全部直して単体テストも追加してからリリースノートを書く",
+				expectedComplex: true,
+				expectedReason:  "task is long and may contain multiple actions",
+			},
 		}
 
 		for _, tt := range tests {
@@ -0,0 +1,396 @@
+package decomposition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tennashi/tabler/internal/parser"
+)
+
+// ErrUnknownCommand is returned by Editor.Apply for input that is not an edit command
+var ErrUnknownCommand = errors.New("unknown edit command")
+
+// EditHelp describes the commands Editor.Apply understands
+const EditHelp = `Edit commands (numbers refer to the list above):
+  rename N <title>            change the title of subtask N
+  merge N M...                combine subtasks into the first one given
+  split N <title> | <title>   replace subtask N with several subtasks
+  move N M                    move subtask N to position M
+  add <title> [#tag !! @date] add a subtask of your own
+  set N [#tag] [!!] [@date]   set tags, priority or deadline of subtask N
+  delete N                    remove subtask N
+  regen N                     ask for a new suggestion for subtask N
+`
+
+// Subtask is a proposed subtask that can be edited before it is created
+type Subtask struct {
+	// ID identifies the subtask within its decomposition and survives edits
+	ID    int
+	Title string
+	// Effort is the estimated effort, 0 when unknown
+	Effort time.Duration
+	// DependsOn lists the IDs of subtasks that must be done first
+	DependsOn []int
+	Tags      []string
+	Priority  int
+	Deadline  *time.Time
+}
+
+// NewSubtasks creates independent subtasks from titles, numbered from 1
+func NewSubtasks(titles []string) []Subtask {
+	subtasks := make([]Subtask, len(titles))
+	for i, title := range titles {
+		subtasks[i] = Subtask{ID: i + 1, Title: title}
+	}
+	return subtasks
+}
+
+// Regenerator suggests a replacement for a single subtask
+type Regenerator interface {
+	Regenerate(ctx context.Context, task string, subtasks []string, index int) (string, error)
+}
+
+// Editor applies edit commands to a decomposition before anything is stored
+type Editor struct {
+	task        string
+	subtasks    []Subtask
+	regenerator Regenerator
+}
+
+// NewEditor creates an editor for the subtasks of a decomposition result
+func NewEditor(result *DecompositionResult) *Editor {
+	subtasks := make([]Subtask, len(result.Subtasks))
+	for i, subtask := range result.Subtasks {
+		subtask.DependsOn = slices.Clone(subtask.DependsOn)
+		subtask.Tags = slices.Clone(subtask.Tags)
+		subtasks[i] = subtask
+	}
+	return &Editor{
+		task:     result.OriginalTask,
+		subtasks: subtasks,
+	}
+}
+
+// SetRegenerator enables the regen command
+func (e *Editor) SetRegenerator(regenerator Regenerator) {
+	e.regenerator = regenerator
+}
+
+// Task returns the task being decomposed
+func (e *Editor) Task() string {
+	return e.task
+}
+
+// Subtasks returns the subtasks in their current order
+func (e *Editor) Subtasks() []Subtask {
+	return e.subtasks
+}
+
+// Apply runs a single edit command such as "rename 2 Book the venue"
+func (e *Editor) Apply(ctx context.Context, command string) error {
+	name, args, _ := strings.Cut(strings.TrimSpace(command), " ")
+	args = strings.TrimSpace(args)
+
+	switch strings.ToLower(name) {
+	case "rename":
+		return e.rename(args)
+	case "merge":
+		return e.merge(args)
+	case "split":
+		return e.split(args)
+	case "move":
+		return e.move(args)
+	case "add":
+		return e.add(args)
+	case "set":
+		return e.set(args)
+	case "delete":
+		return e.delete(args)
+	case "regen":
+		return e.regenerate(ctx, args)
+	default:
+		return ErrUnknownCommand
+	}
+}
+
+// rename changes the title of a subtask
+func (e *Editor) rename(args string) error {
+	index, title, err := e.indexAndText(args)
+	if err != nil {
+		return err
+	}
+	if title == "" {
+		return fmt.Errorf("rename needs a new title")
+	}
+
+	e.subtasks[index].Title = title
+	return nil
+}
+
+// merge folds the given subtasks into the first one, keeping all their tags and
+// dependencies, the highest priority, the earliest deadline and the total effort
+func (e *Editor) merge(args string) error {
+	var indices []int
+	for _, field := range strings.FieldsFunc(args, isListSeparator) {
+		index, err := e.parseIndex(field)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(indices, index) {
+			indices = append(indices, index)
+		}
+	}
+	if len(indices) < 2 {
+		return fmt.Errorf("merge needs at least two subtasks")
+	}
+
+	target := &e.subtasks[indices[0]]
+	for _, index := range indices[1:] {
+		source := e.subtasks[index]
+		target.Title += " and " + source.Title
+		for _, tag := range source.Tags {
+			if !slices.Contains(target.Tags, tag) {
+				target.Tags = append(target.Tags, tag)
+			}
+		}
+		target.Priority = max(target.Priority, source.Priority)
+		target.Effort += source.Effort
+		for _, dependency := range source.DependsOn {
+			if !slices.Contains(target.DependsOn, dependency) {
+				target.DependsOn = append(target.DependsOn, dependency)
+			}
+		}
+		if source.Deadline != nil && (target.Deadline == nil || source.Deadline.Before(*target.Deadline)) {
+			target.Deadline = source.Deadline
+		}
+	}
+
+	// Whatever depended on a merged subtask now depends on the target
+	targetID := target.ID
+	mergedIDs := make([]int, 0, len(indices)-1)
+	for _, index := range indices[1:] {
+		mergedIDs = append(mergedIDs, e.subtasks[index].ID)
+	}
+	e.replaceDependency(mergedIDs, []int{targetID})
+	target.DependsOn = slices.DeleteFunc(target.DependsOn, func(id int) bool { return id == targetID })
+
+	// Remove from the back so earlier indices stay valid
+	merged := slices.Clone(indices[1:])
+	slices.Sort(merged)
+	for i := len(merged) - 1; i >= 0; i-- {
+		e.subtasks = slices.Delete(e.subtasks, merged[i], merged[i]+1)
+	}
+	return nil
+}
+
+// split replaces a subtask with several, each keeping its tags, priority, deadline
+// and dependencies; the first part keeps its ID and subtasks that depended on it
+// now wait for every part
+func (e *Editor) split(args string) error {
+	index, text, err := e.indexAndText(args)
+	if err != nil {
+		return err
+	}
+
+	original := e.subtasks[index]
+	var parts []Subtask
+	for _, title := range strings.Split(text, "|") {
+		title = strings.TrimSpace(title)
+		if title == "" {
+			continue
+		}
+		part := original
+		part.Title = title
+		part.Tags = slices.Clone(original.Tags)
+		part.DependsOn = slices.Clone(original.DependsOn)
+		parts = append(parts, part)
+	}
+	if len(parts) < 2 {
+		return fmt.Errorf("split needs at least two titles separated by '|'")
+	}
+
+	partIDs := []int{original.ID}
+	for i := 1; i < len(parts); i++ {
+		parts[i].ID = e.nextID() + i - 1
+		partIDs = append(partIDs, parts[i].ID)
+	}
+	e.replaceDependency([]int{original.ID}, partIDs)
+
+	e.subtasks = slices.Replace(e.subtasks, index, index+1, parts...)
+	return nil
+}
+
+// move changes the position of a subtask
+func (e *Editor) move(args string) error {
+	fields := strings.Fields(args)
+	if len(fields) != 2 {
+		return fmt.Errorf("move needs a subtask and a position")
+	}
+	from, err := e.parseIndex(fields[0])
+	if err != nil {
+		return err
+	}
+	to, err := e.parseIndex(fields[1])
+	if err != nil {
+		return err
+	}
+
+	subtask := e.subtasks[from]
+	e.subtasks = slices.Delete(e.subtasks, from, from+1)
+	e.subtasks = slices.Insert(e.subtasks, to, subtask)
+	return nil
+}
+
+// add appends a subtask written by the user, honouring shortcuts
+func (e *Editor) add(args string) error {
+	parsed := parser.Parse(args)
+	title := strings.TrimSpace(parsed.Title)
+	if title == "" {
+		return fmt.Errorf("add needs a title")
+	}
+
+	e.subtasks = append(e.subtasks, Subtask{
+		ID:       e.nextID(),
+		Title:    title,
+		Tags:     parsed.Tags,
+		Priority: parsed.Priority,
+		Deadline: parsed.Deadline,
+	})
+	return nil
+}
+
+// set updates the tags, priority or deadline of a subtask from shortcuts
+func (e *Editor) set(args string) error {
+	index, text, err := e.indexAndText(args)
+	if err != nil {
+		return err
+	}
+
+	parsed := parser.Parse(text)
+	if strings.TrimSpace(parsed.Title) != "" {
+		return fmt.Errorf("set only takes #tags, !priority and @deadline, got %q", parsed.Title)
+	}
+	if len(parsed.Tags) == 0 && parsed.Priority == 0 && parsed.Deadline == nil {
+		return fmt.Errorf("set needs #tags, !priority or @deadline")
+	}
+
+	subtask := &e.subtasks[index]
+	if len(parsed.Tags) > 0 {
+		subtask.Tags = parsed.Tags
+	}
+	if parsed.Priority > 0 {
+		subtask.Priority = parsed.Priority
+	}
+	if parsed.Deadline != nil {
+		subtask.Deadline = parsed.Deadline
+	}
+	return nil
+}
+
+// delete removes a subtask
+func (e *Editor) delete(args string) error {
+	index, err := e.parseIndex(args)
+	if err != nil {
+		return err
+	}
+
+	// Dependents keep waiting for whatever the removed subtask waited for
+	removed := e.subtasks[index]
+	e.replaceDependency([]int{removed.ID}, removed.DependsOn)
+	e.subtasks = slices.Delete(e.subtasks, index, index+1)
+	return nil
+}
+
+// regenerate replaces the title of a subtask with a new suggestion
+func (e *Editor) regenerate(ctx context.Context, args string) error {
+	if e.regenerator == nil {
+		return fmt.Errorf("regenerating subtasks is not available")
+	}
+	index, err := e.parseIndex(args)
+	if err != nil {
+		return err
+	}
+
+	titles := make([]string, len(e.subtasks))
+	for i, subtask := range e.subtasks {
+		titles[i] = subtask.Title
+	}
+
+	title, err := e.regenerator.Regenerate(ctx, e.task, titles, index)
+	if err != nil {
+		return err
+	}
+
+	e.subtasks[index].Title = title
+	return nil
+}
+
+// replaceDependency makes subtasks that depend on any of ids depend on
+// replacements instead, never on themselves
+func (e *Editor) replaceDependency(ids, replacements []int) {
+	for i := range e.subtasks {
+		subtask := &e.subtasks[i]
+		if !slices.ContainsFunc(subtask.DependsOn, func(id int) bool { return slices.Contains(ids, id) }) {
+			continue
+		}
+
+		var dependsOn []int
+		for _, id := range subtask.DependsOn {
+			if !slices.Contains(ids, id) && !slices.Contains(dependsOn, id) {
+				dependsOn = append(dependsOn, id)
+			}
+		}
+		for _, id := range replacements {
+			if id != subtask.ID && !slices.Contains(dependsOn, id) {
+				dependsOn = append(dependsOn, id)
+			}
+		}
+		subtask.DependsOn = dependsOn
+	}
+}
+
+// nextID returns an ID no subtask uses yet
+func (e *Editor) nextID() int {
+	next := 1
+	for _, subtask := range e.subtasks {
+		next = max(next, subtask.ID+1)
+	}
+	return next
+}
+
+// indexAndText splits "N rest" into a zero-based index and the rest
+func (e *Editor) indexAndText(args string) (int, string, error) {
+	number, text, _ := strings.Cut(args, " ")
+	index, err := e.parseIndex(number)
+	if err != nil {
+		return 0, "", err
+	}
+	return index, strings.TrimSpace(text), nil
+}
+
+// parseIndex converts a one-based subtask number into a zero-based index
+func (e *Editor) parseIndex(number string) (int, error) {
+	number = strings.TrimSpace(number)
+	if number == "" {
+		return 0, fmt.Errorf("missing subtask number")
+	}
+
+	num, err := strconv.Atoi(number)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number: %s", number)
+	}
+	if num < 1 || num > len(e.subtasks) {
+		return 0, fmt.Errorf("number out of bounds: %d", num)
+	}
+	return num - 1, nil
+}
+
+// isListSeparator reports whether r separates numbers in a list like "2,3 4"
+func isListSeparator(r rune) bool {
+	return r == ',' || r == ' '
+}
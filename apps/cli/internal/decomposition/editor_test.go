@@ -0,0 +1,276 @@
+package decomposition
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestEditor(t *testing.T) {
+	newEditor := func() *Editor {
+		return NewEditor(&DecompositionResult{
+			OriginalTask: "organize conference",
+			Subtasks:     NewSubtasks([]string{"Book venue", "Invite speakers", "Setup registration"}),
+		})
+	}
+	titles := func(e *Editor) []string {
+		var result []string
+		for _, subtask := range e.Subtasks() {
+			result = append(result, subtask.Title)
+		}
+		return result
+	}
+
+	t.Run("Apply", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			commands []string
+			expected []string
+		}{
+			{
+				name:     "rename",
+				commands: []string{"rename 2 Invite keynote speakers"},
+				expected: []string{"Book venue", "Invite keynote speakers", "Setup registration"},
+			},
+			{
+				name:     "merge",
+				commands: []string{"merge 1,3"},
+				expected: []string{"Book venue and Setup registration", "Invite speakers"},
+			},
+			{
+				name:     "split",
+				commands: []string{"split 1 Shortlist venues | Sign venue contract"},
+				expected: []string{"Shortlist venues", "Sign venue contract", "Invite speakers", "Setup registration"},
+			},
+			{
+				name:     "move",
+				commands: []string{"move 3 1"},
+				expected: []string{"Setup registration", "Book venue", "Invite speakers"},
+			},
+			{
+				name:     "add",
+				commands: []string{"add Order badges #print"},
+				expected: []string{"Book venue", "Invite speakers", "Setup registration", "Order badges"},
+			},
+			{
+				name:     "delete",
+				commands: []string{"delete 2"},
+				expected: []string{"Book venue", "Setup registration"},
+			},
+			{
+				name:     "several edits in a row",
+				commands: []string{"delete 1", "add Book hotel rooms", "move 3 1"},
+				expected: []string{"Book hotel rooms", "Invite speakers", "Setup registration"},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				editor := newEditor()
+
+				// Act
+				for _, command := range tt.commands {
+					if err := editor.Apply(context.Background(), command); err != nil {
+						t.Fatalf("unexpected error for %q: %v", command, err)
+					}
+				}
+
+				// Assert
+				if got := titles(editor); !slices.Equal(got, tt.expected) {
+					t.Errorf("expected %v, got %v", tt.expected, got)
+				}
+			})
+		}
+
+		t.Run("should set tags, priority and deadline from shortcuts", func(t *testing.T) {
+			// Arrange
+			editor := newEditor()
+
+			// Act
+			err := editor.Apply(context.Background(), "set 1 #venue !!! @2030-05-17")
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			subtask := editor.Subtasks()[0]
+			if !slices.Equal(subtask.Tags, []string{"venue"}) {
+				t.Errorf("expected tags [venue], got %v", subtask.Tags)
+			}
+			if subtask.Priority != 3 {
+				t.Errorf("expected priority 3, got %d", subtask.Priority)
+			}
+			if subtask.Deadline == nil || subtask.Deadline.Format("2006-01-02") != "2030-05-17" {
+				t.Errorf("expected deadline 2030-05-17, got %v", subtask.Deadline)
+			}
+		})
+
+		t.Run("should keep the strongest metadata when merging", func(t *testing.T) {
+			// Arrange
+			editor := newEditor()
+			early := time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)
+			late := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+			editor.subtasks[0] = Subtask{Title: "Book venue", Tags: []string{"venue"}, Priority: 1, Deadline: &late}
+			editor.subtasks[1] = Subtask{Title: "Invite speakers", Tags: []string{"people"}, Priority: 2, Deadline: &early}
+
+			// Act
+			err := editor.Apply(context.Background(), "merge 1 2")
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			merged := editor.Subtasks()[0]
+			if !slices.Equal(merged.Tags, []string{"venue", "people"}) {
+				t.Errorf("expected tags of both subtasks, got %v", merged.Tags)
+			}
+			if merged.Priority != 2 {
+				t.Errorf("expected highest priority 2, got %d", merged.Priority)
+			}
+			if merged.Deadline == nil || !merged.Deadline.Equal(early) {
+				t.Errorf("expected earliest deadline, got %v", merged.Deadline)
+			}
+		})
+
+		t.Run("should keep dependencies on the right subtasks", func(t *testing.T) {
+			dependencies := func(e *Editor) map[string][]int {
+				result := make(map[string][]int)
+				for _, subtask := range e.Subtasks() {
+					result[subtask.Title] = subtask.DependsOn
+				}
+				return result
+			}
+
+			tests := []struct {
+				name     string
+				command  string
+				expected map[string][]int
+			}{
+				{
+					name:     "delete passes dependencies on",
+					command:  "delete 2",
+					expected: map[string][]int{"Book venue": nil, "Setup registration": {1}},
+				},
+				{
+					name:    "split makes dependents wait for every part",
+					command: "split 1 Shortlist venues | Sign venue contract",
+					expected: map[string][]int{
+						"Shortlist venues": nil, "Sign venue contract": nil,
+						"Invite speakers": {1, 4}, "Setup registration": {2},
+					},
+				},
+				{
+					name:     "merge redirects dependents to the merged subtask",
+					command:  "merge 1 2",
+					expected: map[string][]int{"Book venue and Invite speakers": {}, "Setup registration": {1}},
+				},
+			}
+
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					// Arrange
+					editor := newEditor()
+					editor.subtasks[1].DependsOn = []int{1}
+					editor.subtasks[2].DependsOn = []int{2}
+
+					// Act
+					err := editor.Apply(context.Background(), tt.command)
+
+					// Assert
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					got := dependencies(editor)
+					if len(got) != len(tt.expected) {
+						t.Fatalf("expected %v, got %v", tt.expected, got)
+					}
+					for title, expected := range tt.expected {
+						if !slices.Equal(got[title], expected) {
+							t.Errorf("expected %q to depend on %v, got %v", title, expected, got[title])
+						}
+					}
+				})
+			}
+		})
+
+		t.Run("should regenerate a single subtask", func(t *testing.T) {
+			// Arrange
+			editor := newEditor()
+			regenerator := &mockRegenerator{title: "Shortlist three venues"}
+			editor.SetRegenerator(regenerator)
+
+			// Act
+			err := editor.Apply(context.Background(), "regen 1")
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := []string{"Shortlist three venues", "Invite speakers", "Setup registration"}
+			if got := titles(editor); !slices.Equal(got, expected) {
+				t.Errorf("expected %v, got %v", expected, got)
+			}
+			if regenerator.index != 0 || regenerator.task != "organize conference" {
+				t.Errorf("unexpected regeneration request: %q #%d", regenerator.task, regenerator.index)
+			}
+		})
+
+		t.Run("should report unknown commands", func(t *testing.T) {
+			// Arrange
+			editor := newEditor()
+
+			// Act
+			err := editor.Apply(context.Background(), "1,2")
+
+			// Assert
+			if !errors.Is(err, ErrUnknownCommand) {
+				t.Errorf("expected ErrUnknownCommand, got %v", err)
+			}
+		})
+
+		invalid := []string{
+			"rename 4 Too far",
+			"rename 1",
+			"merge 1",
+			"split 1 Only one part",
+			"move 1",
+			"add #tag-only",
+			"set 1 not a shortcut",
+			"delete zero",
+			"regen 1",
+		}
+		for _, command := range invalid {
+			t.Run("should reject "+command, func(t *testing.T) {
+				// Arrange
+				editor := newEditor()
+
+				// Act
+				err := editor.Apply(context.Background(), command)
+
+				// Assert
+				if err == nil || errors.Is(err, ErrUnknownCommand) {
+					t.Errorf("expected validation error, got %v", err)
+				}
+				if got := titles(editor); len(got) != 3 {
+					t.Errorf("expected subtasks to be unchanged, got %v", got)
+				}
+			})
+		}
+	})
+}
+
+// mockRegenerator records the subtask it was asked to regenerate
+type mockRegenerator struct {
+	title string
+	task  string
+	index int
+}
+
+func (m *mockRegenerator) Regenerate(_ context.Context, task string, _ []string, index int) (string, error) {
+	m.task = task
+	m.index = index
+	return m.title, nil
+}
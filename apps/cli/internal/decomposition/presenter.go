@@ -2,8 +2,10 @@ package decomposition
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // InteractivePresenter formats decomposition results for interactive display
@@ -14,26 +16,85 @@ func NewInteractivePresenter() *InteractivePresenter {
 	return &InteractivePresenter{}
 }
 
+// selectionPrompt asks the user to pick or edit subtasks
+const selectionPrompt = "\nSelect subtasks to create (e.g., '1,3-5' or 'all' or 'none'), or type 'help' to edit them: "
+
 // Present formats the decomposition result for display
 func (p *InteractivePresenter) Present(result *DecompositionResult) string {
+	return p.PresentSubtasks(result.OriginalTask, result.Subtasks)
+}
+
+// PresentSubtasks formats edited subtasks, including their tags, priority, deadline,
+// effort and the numbers of the subtasks they depend on
+func (p *InteractivePresenter) PresentSubtasks(task string, subtasks []Subtask) string {
 	var b strings.Builder
 
 	// Header
 	b.WriteString("Task decomposition for: ")
-	b.WriteString(result.OriginalTask)
+	b.WriteString(task)
 	b.WriteString("\n\n")
 
 	// Subtasks
-	for i, subtask := range result.Subtasks {
-		b.WriteString(fmt.Sprintf("[%d] %s\n", i+1, subtask))
+	for i, subtask := range subtasks {
+		b.WriteString(fmt.Sprintf("[%d] %s", i+1, subtask.Title))
+		for _, tag := range subtask.Tags {
+			b.WriteString(" #" + tag)
+		}
+		if subtask.Priority > 0 {
+			b.WriteString(" " + strings.Repeat("!", subtask.Priority))
+		}
+		if subtask.Deadline != nil {
+			b.WriteString(" @" + subtask.Deadline.Format("Jan 2"))
+		}
+		if details := subtaskDetails(subtask, subtasks); details != "" {
+			b.WriteString(" (" + details + ")")
+		}
+		b.WriteString("\n")
 	}
 
 	// Instructions
-	b.WriteString("\nSelect subtasks to create (e.g., '1,3-5' or 'all' or 'none'): ")
+	b.WriteString(selectionPrompt)
 
 	return b.String()
 }
 
+// subtaskDetails describes the effort and dependencies of a subtask, referring to
+// other subtasks by their current number
+func subtaskDetails(subtask Subtask, subtasks []Subtask) string {
+	var details []string
+	if subtask.Effort > 0 {
+		details = append(details, "~"+FormatEffort(subtask.Effort))
+	}
+
+	var after []string
+	for i, other := range subtasks {
+		if slices.Contains(subtask.DependsOn, other.ID) {
+			after = append(after, strconv.Itoa(i+1))
+		}
+	}
+	if len(after) > 0 {
+		details = append(details, "after "+strings.Join(after, ", "))
+	}
+
+	return strings.Join(details, ", ")
+}
+
+// FormatEffort renders an effort estimate compactly, e.g. "45m", "2h" or "1h30m"
+func FormatEffort(effort time.Duration) string {
+	effort = effort.Round(time.Minute)
+	hours := int(effort / time.Hour)
+	minutes := int((effort % time.Hour) / time.Minute)
+
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	}
+}
+
 // ParseSelection parses user input for subtask selection
 func (p *InteractivePresenter) ParseSelection(input string, total int) ([]int, error) {
 	input = strings.TrimSpace(input)
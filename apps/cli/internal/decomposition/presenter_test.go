@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestInteractivePresenter(t *testing.T) {
@@ -12,14 +13,14 @@ func TestInteractivePresenter(t *testing.T) {
 			// Arrange
 			result := &DecompositionResult{
 				OriginalTask: "organize conference",
-				Subtasks: []string{
+				Subtasks: NewSubtasks([]string{
 					"Book venue for conference",
 					"Create conference schedule and agenda",
 					"Invite speakers and confirm attendance",
 					"Setup registration system",
 					"Arrange catering and refreshments",
 					"Prepare conference materials and badges",
-				},
+				}),
 				Rationale: "Task broken down into actionable steps",
 			}
 			presenter := NewInteractivePresenter()
@@ -35,9 +36,9 @@ func TestInteractivePresenter(t *testing.T) {
 
 			// Check that output contains all subtasks
 			for i, subtask := range result.Subtasks {
-				expectedLine := formatSubtaskLine(i+1, subtask)
+				expectedLine := formatSubtaskLine(i+1, subtask.Title)
 				if !strings.Contains(output, expectedLine) {
-					t.Errorf("output should contain subtask %d: %s", i+1, subtask)
+					t.Errorf("output should contain subtask %d: %s", i+1, subtask.Title)
 				}
 			}
 
@@ -48,6 +49,29 @@ func TestInteractivePresenter(t *testing.T) {
 		})
 	})
 
+	t.Run("PresentSubtasks", func(t *testing.T) {
+		t.Run("should show metadata, effort and dependencies of edited subtasks", func(t *testing.T) {
+			// Arrange
+			deadline := time.Date(2030, 5, 17, 0, 0, 0, 0, time.UTC)
+			subtasks := []Subtask{
+				{ID: 1, Title: "Book venue", Tags: []string{"venue"}, Priority: 2, Deadline: &deadline},
+				{ID: 2, Title: "Invite speakers", Effort: 90 * time.Minute, DependsOn: []int{1}},
+			}
+			presenter := NewInteractivePresenter()
+
+			// Act
+			output := presenter.PresentSubtasks("organize conference", subtasks)
+
+			// Assert
+			if !strings.Contains(output, "[1] Book venue #venue !! @May 17\n") {
+				t.Errorf("expected first subtask with metadata, got %q", output)
+			}
+			if !strings.Contains(output, "[2] Invite speakers (~1h30m, after 1)\n") {
+				t.Errorf("expected second subtask with effort and dependency, got %q", output)
+			}
+		})
+	})
+
 	t.Run("ParseSelection", func(t *testing.T) {
 		presenter := NewInteractivePresenter()
 
@@ -0,0 +1,267 @@
+package enrichment
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/tennashi/tabler/internal/claude"
+	"github.com/tennashi/tabler/internal/parser"
+	"github.com/tennashi/tabler/internal/prompts"
+)
+
+const (
+	// defaultBatchSize is how many tasks are sent in a single prompt
+	defaultBatchSize = 20
+	// dateFormat is the deadline format exchanged with the model
+	dateFormat = "2006-01-02"
+)
+
+// priorityLevels maps model priority names to task priorities
+var priorityLevels = map[string]int{
+	"low":    1,
+	"medium": 2,
+	"high":   3,
+}
+
+// enrichmentSchema declares the response the enrichment prompt must produce
+var enrichmentSchema = &claude.Schema{
+	Fields: []claude.Field{
+		{
+			Name:     "tasks",
+			Type:     claude.ObjectListField,
+			Required: true,
+			Items: &claude.Schema{
+				Fields: []claude.Field{
+					{Name: "id", Type: claude.StringField, Required: true},
+					{
+						Name:      "tags",
+						Type:      claude.StringListField,
+						MaxItems:  10,
+						MaxLength: 32,
+						Pattern:   regexp.MustCompile(`^[^\s#,]+$`),
+					},
+					{Name: "priority", Type: claude.StringField, Enum: []string{"low", "medium", "high"}, AllowEmpty: true},
+					{Name: "deadline", Type: claude.StringField, Format: dateFormat, AllowEmpty: true},
+				},
+			},
+		},
+	},
+}
+
+// Candidate is an existing task considered for enrichment
+type Candidate struct {
+	ID       string
+	Title    string
+	Tags     []string
+	Priority int
+	Deadline time.Time
+}
+
+// Proposal is a suggested metadata change for one task
+type Proposal struct {
+	Candidate *Candidate
+	Tags      []string
+	Priority  int
+	Deadline  time.Time
+}
+
+// HasChanges reports whether the proposal differs from the current task
+func (p *Proposal) HasChanges() bool {
+	return !equalTags(p.Tags, p.Candidate.Tags) ||
+		p.Priority != p.Candidate.Priority ||
+		!p.Deadline.Equal(p.Candidate.Deadline)
+}
+
+// Enricher asks the model for missing metadata on existing tasks in batches
+type Enricher struct {
+	executor  *claude.StructuredExecutor
+	prompts   *prompts.Library
+	tagRules  parser.TagRules
+	batchSize int
+	now       func() time.Time
+}
+
+// NewEnricher creates an enricher backed by the given provider
+func NewEnricher(provider claude.Provider) *Enricher {
+	return &Enricher{
+		executor:  claude.NewStructuredExecutor(provider),
+		prompts:   prompts.Default(),
+		tagRules:  parser.DefaultTagRules(),
+		batchSize: defaultBatchSize,
+		now:       time.Now,
+	}
+}
+
+// SetPrompts sets the prompt library used to build enrichment prompts
+func (e *Enricher) SetPrompts(library *prompts.Library) {
+	e.prompts = library
+}
+
+// SetTagRules sets the rules suggested tags are normalized with
+func (e *Enricher) SetTagRules(rules parser.TagRules) {
+	e.tagRules = rules
+}
+
+// SetBatchSize sets how many tasks are sent per prompt
+func (e *Enricher) SetBatchSize(size int) {
+	if size < 1 {
+		size = 1
+	}
+	e.batchSize = size
+}
+
+// SetClock sets the time source used for the current date (for testing)
+func (e *Enricher) SetClock(now func() time.Time) {
+	e.now = now
+}
+
+// promptTask is a task as presented to the model
+type promptTask struct {
+	Ref      string
+	Title    string
+	Tags     []string
+	Priority string
+	Deadline string
+}
+
+type enrichmentResponse struct {
+	Tasks []struct {
+		ID       string   `json:"id"`
+		Tags     []string `json:"tags"`
+		Priority string   `json:"priority"`
+		Deadline string   `json:"deadline"`
+	} `json:"tasks"`
+}
+
+// Propose returns a proposal for every candidate whose metadata the model would change
+func (e *Enricher) Propose(ctx context.Context, candidates []*Candidate) ([]*Proposal, error) {
+	ctx = claude.WithFeature(ctx, claude.FeatureEnrichment)
+
+	var proposals []*Proposal
+	for start := 0; start < len(candidates); start += e.batchSize {
+		end := start + e.batchSize
+		if end > len(candidates) {
+			end = len(candidates)
+		}
+
+		batch, err := e.proposeBatch(ctx, candidates[start:end])
+		if err != nil {
+			return nil, err
+		}
+		proposals = append(proposals, batch...)
+	}
+
+	return proposals, nil
+}
+
+// proposeBatch sends one batch of candidates in a single prompt
+func (e *Enricher) proposeBatch(ctx context.Context, batch []*Candidate) ([]*Proposal, error) {
+	// Refer to tasks by short references instead of full IDs
+	refs := make(map[string]*Candidate, len(batch))
+	tasks := make([]promptTask, 0, len(batch))
+	for i, c := range batch {
+		ref := fmt.Sprintf("t%d", i+1)
+		refs[ref] = c
+		tasks = append(tasks, promptTask{
+			Ref:      ref,
+			Title:    c.Title,
+			Tags:     c.Tags,
+			Priority: priorityName(c.Priority),
+			Deadline: formatDate(c.Deadline),
+		})
+	}
+
+	rendered, err := e.prompts.Render(prompts.Enrichment, struct {
+		CurrentDate string
+		Tasks       []promptTask
+	}{
+		CurrentDate: e.now().Format(dateFormat),
+		Tasks:       tasks,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response enrichmentResponse
+	if err := e.executor.Execute(ctx, rendered.Text, enrichmentSchema, &response); err != nil {
+		return nil, fmt.Errorf("failed to get enrichment from Claude: %w", err)
+	}
+
+	var proposals []*Proposal
+	for _, suggestion := range response.Tasks {
+		candidate, ok := refs[suggestion.ID]
+		if !ok {
+			// Ignore tasks the model invented
+			continue
+		}
+
+		proposal := &Proposal{
+			Candidate: candidate,
+			Tags:      mergeTags(candidate.Tags, parser.NormalizeTags(suggestion.Tags, e.tagRules)),
+			Priority:  candidate.Priority,
+			Deadline:  candidate.Deadline,
+		}
+		if level, ok := priorityLevels[suggestion.Priority]; ok {
+			proposal.Priority = level
+		}
+		if deadline, err := time.Parse(dateFormat, suggestion.Deadline); err == nil {
+			proposal.Deadline = deadline
+		}
+
+		if proposal.HasChanges() {
+			proposals = append(proposals, proposal)
+		}
+	}
+
+	return proposals, nil
+}
+
+// mergeTags keeps existing tags and adds suggested ones, sorted and deduplicated
+func mergeTags(current, suggested []string) []string {
+	seen := make(map[string]bool, len(current)+len(suggested))
+	merged := make([]string, 0, len(current)+len(suggested))
+	for _, tag := range append(append([]string{}, current...), suggested...) {
+		if !seen[tag] {
+			seen[tag] = true
+			merged = append(merged, tag)
+		}
+	}
+	sort.Strings(merged)
+	return merged
+}
+
+func equalTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	sortedA := append([]string{}, a...)
+	sortedB := append([]string{}, b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func priorityName(priority int) string {
+	for name, level := range priorityLevels {
+		if level == priority {
+			return name
+		}
+	}
+	return ""
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateFormat)
+}
@@ -0,0 +1,199 @@
+package enrichment_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/claude"
+	"github.com/tennashi/tabler/internal/enrichment"
+	"github.com/tennashi/tabler/internal/parser"
+)
+
+func TestEnricher(t *testing.T) {
+	fixedNow := func() time.Time {
+		return time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("Propose", func(t *testing.T) {
+		t.Run("should merge suggested tags and set missing priority and deadline", func(t *testing.T) {
+			// Arrange
+			provider := &mockProvider{responses: []string{
+				`{"tasks": [{"id": "t1", "tags": ["docs", "work"], "priority": "high", "deadline": "2024-01-19"}]}`,
+			}}
+			enricher := enrichment.NewEnricher(provider)
+			enricher.SetClock(fixedNow)
+			candidate := &enrichment.Candidate{ID: "abc123", Title: "Write quarterly report by Friday", Tags: []string{"work"}}
+
+			// Act
+			proposals, err := enricher.Propose(context.Background(), []*enrichment.Candidate{candidate})
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(proposals) != 1 {
+				t.Fatalf("expected 1 proposal, got %d", len(proposals))
+			}
+			proposal := proposals[0]
+			if strings.Join(proposal.Tags, ",") != "docs,work" {
+				t.Errorf("expected tags docs,work, got %v", proposal.Tags)
+			}
+			if proposal.Priority != 3 {
+				t.Errorf("expected priority 3, got %d", proposal.Priority)
+			}
+			if !proposal.Deadline.Equal(time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)) {
+				t.Errorf("expected deadline 2024-01-19, got %v", proposal.Deadline)
+			}
+			if !strings.Contains(provider.prompts[0], "Today is 2024-01-15") {
+				t.Errorf("expected prompt to include current date, got %q", provider.prompts[0])
+			}
+		})
+
+		t.Run("should normalize suggested tags with the tag rules", func(t *testing.T) {
+			// Arrange
+			provider := &mockProvider{responses: []string{
+				`{"tasks": [{"id": "t1", "tags": ["Work.", "Job/Docs"], "priority": "", "deadline": ""}]}`,
+			}}
+			enricher := enrichment.NewEnricher(provider)
+			enricher.SetTagRules(parser.TagRules{
+				Lowercase:        true,
+				StripPunctuation: true,
+				Aliases:          map[string]string{"job": "work"},
+			})
+			candidate := &enrichment.Candidate{ID: "abc123", Title: "Write quarterly report", Tags: []string{"work"}}
+
+			// Act
+			proposals, err := enricher.Propose(context.Background(), []*enrichment.Candidate{candidate})
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(proposals) != 1 {
+				t.Fatalf("expected 1 proposal, got %d", len(proposals))
+			}
+			if tags := strings.Join(proposals[0].Tags, ","); tags != "work,work/docs" {
+				t.Errorf("expected tags work,work/docs, got %v", tags)
+			}
+		})
+
+		t.Run("should keep existing values when the model leaves them empty", func(t *testing.T) {
+			// Arrange
+			deadline := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+			provider := &mockProvider{responses: []string{
+				`{"tasks": [{"id": "t1", "tags": ["home"], "priority": "", "deadline": ""}]}`,
+			}}
+			enricher := enrichment.NewEnricher(provider)
+			candidate := &enrichment.Candidate{ID: "abc123", Title: "Fix sink", Priority: 2, Deadline: deadline}
+
+			// Act
+			proposals, err := enricher.Propose(context.Background(), []*enrichment.Candidate{candidate})
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(proposals) != 1 {
+				t.Fatalf("expected 1 proposal, got %d", len(proposals))
+			}
+			if proposals[0].Priority != 2 || !proposals[0].Deadline.Equal(deadline) {
+				t.Errorf("expected priority and deadline to be kept, got %d %v", proposals[0].Priority, proposals[0].Deadline)
+			}
+		})
+
+		t.Run("should skip unchanged tasks and unknown ids", func(t *testing.T) {
+			// Arrange
+			provider := &mockProvider{responses: []string{
+				`{"tasks": [{"id": "t1", "tags": ["work"]}, {"id": "t9", "tags": ["ghost"]}]}`,
+			}}
+			enricher := enrichment.NewEnricher(provider)
+			candidate := &enrichment.Candidate{ID: "abc123", Title: "Write report", Tags: []string{"work"}}
+
+			// Act
+			proposals, err := enricher.Propose(context.Background(), []*enrichment.Candidate{candidate})
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(proposals) != 0 {
+				t.Errorf("expected no proposals, got %d", len(proposals))
+			}
+		})
+
+		t.Run("should send one prompt per batch", func(t *testing.T) {
+			// Arrange
+			provider := &mockProvider{responses: []string{`{"tasks": []}`}}
+			enricher := enrichment.NewEnricher(provider)
+			enricher.SetBatchSize(2)
+			candidates := []*enrichment.Candidate{
+				{ID: "task-1", Title: "One"},
+				{ID: "task-2", Title: "Two"},
+				{ID: "task-3", Title: "Three"},
+			}
+
+			// Act
+			_, err := enricher.Propose(context.Background(), candidates)
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(provider.prompts) != 2 {
+				t.Fatalf("expected 2 prompts, got %d", len(provider.prompts))
+			}
+			if !strings.Contains(provider.prompts[1], "title: Three") || strings.Contains(provider.prompts[1], "title: One") {
+				t.Errorf("expected second prompt to hold only the last task, got %q", provider.prompts[1])
+			}
+		})
+
+		t.Run("should attribute calls to the enrichment feature", func(t *testing.T) {
+			// Arrange
+			provider := &mockProvider{responses: []string{`{"tasks": []}`}}
+			enricher := enrichment.NewEnricher(provider)
+
+			// Act
+			_, err := enricher.Propose(context.Background(), []*enrichment.Candidate{{ID: "task-1", Title: "One"}})
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if provider.feature != claude.FeatureEnrichment {
+				t.Errorf("expected feature %q, got %q", claude.FeatureEnrichment, provider.feature)
+			}
+		})
+
+		t.Run("should return provider errors", func(t *testing.T) {
+			// Arrange
+			provider := &mockProvider{err: errors.New("claude CLI not found")}
+			enricher := enrichment.NewEnricher(provider)
+
+			// Act
+			_, err := enricher.Propose(context.Background(), []*enrichment.Candidate{{ID: "task-1", Title: "One"}})
+
+			// Assert
+			if err == nil {
+				t.Error("expected error")
+			}
+		})
+	})
+}
+
+// mockProvider returns canned responses in order, repeating the last one
+type mockProvider struct {
+	responses []string
+	err       error
+	prompts   []string
+	feature   string
+}
+
+func (m *mockProvider) Execute(ctx context.Context, prompt string) (string, error) {
+	m.prompts = append(m.prompts, prompt)
+	m.feature = claude.FeatureFromContext(ctx)
+	if m.err != nil {
+		return "", m.err
+	}
+	i := len(m.prompts) - 1
+	if i >= len(m.responses) {
+		i = len(m.responses) - 1
+	}
+	return m.responses[i], nil
+}
@@ -0,0 +1,166 @@
+package language
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Language identifies the vocabulary used to analyze task text
+type Language string
+
+const (
+	// Auto detects the language from each input
+	Auto     Language = "auto"
+	English  Language = "en"
+	Japanese Language = "ja"
+)
+
+// Parse validates a language setting; an empty setting means Auto
+func Parse(setting string) (Language, error) {
+	switch Language(strings.ToLower(setting)) {
+	case "", Auto:
+		return Auto, nil
+	case English:
+		return English, nil
+	case Japanese:
+		return Japanese, nil
+	default:
+		return "", fmt.Errorf("unsupported language: %s (expected auto, en or ja)", setting)
+	}
+}
+
+// Detect guesses the language of text from its script
+func Detect(text string) Language {
+	for _, r := range text {
+		if unicode.In(r, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+			return Japanese
+		}
+	}
+	return English
+}
+
+// Resolve returns lang, or the language detected from text when lang is Auto
+func Resolve(lang Language, text string) Language {
+	if lang == "" || lang == Auto {
+		return Detect(text)
+	}
+	return lang
+}
+
+// script classifies runes so Japanese text can be split without spaces
+type script int
+
+const (
+	scriptOther script = iota
+	scriptLatin
+	scriptHiragana
+	scriptKatakana
+	scriptHan
+)
+
+func scriptOf(r rune) script {
+	switch {
+	case unicode.Is(unicode.Hiragana, r):
+		return scriptHiragana
+	case unicode.Is(unicode.Katakana, r) || r == 'ー':
+		return scriptKatakana
+	case unicode.Is(unicode.Han, r) || r == '々':
+		return scriptHan
+	case unicode.IsLetter(r) || unicode.IsDigit(r):
+		return scriptLatin
+	default:
+		return scriptOther
+	}
+}
+
+// Tokenize splits text into lowercased words. Spaces and punctuation separate words,
+// and so does every change of script, which roughly separates Japanese kanji words,
+// katakana loanwords and hiragana particles.
+func Tokenize(text string) []string {
+	var tokens []string
+	var current strings.Builder
+	currentScript := scriptOther
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range strings.ToLower(text) {
+		s := scriptOf(r)
+		if s != currentScript {
+			flush()
+			currentScript = s
+		}
+		if s != scriptOther {
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// IsKana reports whether word is written only in hiragana, such as particles and endings
+func IsKana(word string) bool {
+	for _, r := range word {
+		if scriptOf(r) != scriptHiragana {
+			return false
+		}
+	}
+	return word != ""
+}
+
+// Text is input prepared for vocabulary matching in one language
+type Text struct {
+	Language Language
+	Lower    string
+	Words    []string
+}
+
+// Analyze prepares text for matching, detecting the language when lang is Auto
+func Analyze(text string, lang Language) *Text {
+	return &Text{
+		Language: Resolve(lang, text),
+		Lower:    strings.ToLower(text),
+		Words:    Tokenize(text),
+	}
+}
+
+// Has reports whether the text contains word. English matches whole words;
+// Japanese has no word boundaries, so it matches anywhere in the text.
+func (t *Text) Has(word string) bool {
+	if t.Language == Japanese {
+		return strings.Contains(t.Lower, word)
+	}
+
+	for _, w := range t.Words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns how many times word occurs in the text
+func (t *Text) Count(word string) int {
+	if t.Language == Japanese {
+		return strings.Count(t.Lower, word)
+	}
+
+	count := 0
+	for _, w := range t.Words {
+		if w == word {
+			count++
+		}
+	}
+	return count
+}
+
+// Length returns the length of the text in characters
+func (t *Text) Length() int {
+	return len([]rune(strings.TrimSpace(t.Lower)))
+}
@@ -0,0 +1,110 @@
+package language_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/tennashi/tabler/internal/language"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected language.Language
+	}{
+		{"prepare the slides", language.English},
+		{"資料を準備する", language.Japanese},
+		{"ミーティングの準備 for Q4", language.Japanese},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			// Act
+			result := language.Detect(tt.input)
+
+			// Assert
+			if result != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	t.Run("should accept supported settings", func(t *testing.T) {
+		for setting, expected := range map[string]language.Language{"": language.Auto, "auto": language.Auto, "EN": language.English, "ja": language.Japanese} {
+			// Act
+			result, err := language.Parse(setting)
+
+			// Assert
+			if err != nil || result != expected {
+				t.Errorf("Parse(%q): expected %s, got %s (%v)", setting, expected, result, err)
+			}
+		}
+	})
+
+	t.Run("should reject unsupported languages", func(t *testing.T) {
+		// Act
+		_, err := language.Parse("fr")
+
+		// Assert
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Fix the login-bug, today!", "fix|the|login|bug|today"},
+		{"資料を準備する", "資料|を|準備|する"},
+		{"ミーティングの資料", "ミーティング|の|資料"},
+		{"Q4の予算レビュー", "q4|の|予算|レビュー"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			// Act
+			result := strings.Join(language.Tokenize(tt.input), "|")
+
+			// Assert
+			if result != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	t.Run("should match whole words in English", func(t *testing.T) {
+		// Arrange
+		text := language.Analyze("whatever, buy milk", language.Auto)
+
+		// Act & Assert
+		if text.Has("what") {
+			t.Error("expected 'what' not to match inside 'whatever'")
+		}
+		if !text.Has("milk") {
+			t.Error("expected 'milk' to match")
+		}
+	})
+
+	t.Run("should match anywhere in Japanese", func(t *testing.T) {
+		// Arrange
+		text := language.Analyze("来週の会議を準備する", language.Auto)
+
+		// Act & Assert
+		if text.Language != language.Japanese {
+			t.Fatalf("expected Japanese, got %s", text.Language)
+		}
+		if !text.Has("準備") {
+			t.Error("expected '準備' to match")
+		}
+		if text.Length() != 10 {
+			t.Errorf("expected 10 characters, got %d", text.Length())
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package learning
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tennashi/tabler/internal/language"
+)
+
+// Pattern types
+const (
+	// TypeTag counts how often a tag is used (key: tag)
+	TypeTag = "tag"
+	// TypePriority links title keywords to priorities (key: keyword, value: priority)
+	TypePriority = "priority"
+	// TypeTimeTag links the time of day to tags (key: time of day, value: tag)
+	TypeTimeTag = "time_tag"
+	// TypeModeChoice counts explicitly chosen input modes per word (key: word, value: mode)
+	TypeModeChoice = "mode_choice"
+)
+
+const (
+	// minKeywordLength skips short words that carry no meaning on their own
+	minKeywordLength = 3
+	// minJapaneseKeywordLength skips single kanji, which are too ambiguous alone
+	minJapaneseKeywordLength = 2
+)
+
+// stopWords are common words never learned as priority keywords
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true,
+	"this": true, "that": true, "into": true, "about": true, "before": true,
+}
+
+// Pattern is a learned usage pattern
+type Pattern struct {
+	Type      string    `json:"type"`
+	Key       string    `json:"key"`
+	Value     string    `json:"value,omitempty"`
+	Count     int       `json:"count"`
+	FirstSeen time.Time `json:"first_seen"`
+	LastUsed  time.Time `json:"last_used"`
+}
+
+// Suggestion is a ranked completion or recommendation
+type Suggestion struct {
+	Value string
+	Count int
+}
+
+// Observe extracts the patterns a newly created task contributes.
+// Tags listed in excluded are never learned.
+func Observe(title string, tags []string, priority int, createdAt time.Time, excluded []string) []*Pattern {
+	skip := make(map[string]bool, len(excluded))
+	for _, tag := range excluded {
+		skip[strings.ToLower(strings.TrimPrefix(tag, "#"))] = true
+	}
+
+	var patterns []*Pattern
+
+	timeOfDay := TimeOfDay(createdAt)
+	for _, tag := range tags {
+		if skip[strings.ToLower(tag)] {
+			continue
+		}
+		patterns = append(patterns,
+			&Pattern{Type: TypeTag, Key: tag},
+			&Pattern{Type: TypeTimeTag, Key: timeOfDay, Value: tag},
+		)
+	}
+
+	if priority > 0 {
+		for _, keyword := range Keywords(title) {
+			patterns = append(patterns, &Pattern{Type: TypePriority, Key: keyword, Value: strconv.Itoa(priority)})
+		}
+	}
+
+	return patterns
+}
+
+// Keywords returns the distinct lowercased words of a title worth learning from.
+// Japanese particles and verb endings (hiragana-only words) are skipped.
+func Keywords(title string) []string {
+	seen := make(map[string]bool)
+	var keywords []string
+
+	for _, word := range language.Tokenize(title) {
+		if seen[word] || stopWords[word] || language.IsKana(word) {
+			continue
+		}
+		// Kanji and katakana words carry meaning in fewer characters
+		minLength := minKeywordLength
+		if language.Detect(word) == language.Japanese {
+			minLength = minJapaneseKeywordLength
+		}
+		if len([]rune(word)) < minLength {
+			continue
+		}
+		seen[word] = true
+		keywords = append(keywords, word)
+	}
+
+	return keywords
+}
+
+// TimeOfDay buckets a time into morning, afternoon, evening or night
+func TimeOfDay(t time.Time) string {
+	switch hour := t.Hour(); {
+	case hour >= 5 && hour < 12:
+		return "morning"
+	case hour >= 12 && hour < 17:
+		return "afternoon"
+	case hour >= 17 && hour < 22:
+		return "evening"
+	default:
+		return "night"
+	}
+}
+
+// CompleteTag ranks learned tags starting with partial, most used first
+func CompleteTag(patterns []*Pattern, partial string, limit int) []Suggestion {
+	prefix := strings.ToLower(strings.TrimPrefix(partial, "#"))
+
+	var matches []*Pattern
+	for _, p := range patterns {
+		if p.Type == TypeTag && strings.HasPrefix(strings.ToLower(p.Key), prefix) {
+			matches = append(matches, p)
+		}
+	}
+
+	return rank(matches, func(p *Pattern) string { return p.Key }, limit)
+}
+
+// SuggestTagsForTime ranks tags usually added at the same time of day as at
+func SuggestTagsForTime(patterns []*Pattern, at time.Time, limit int) []Suggestion {
+	timeOfDay := TimeOfDay(at)
+
+	var matches []*Pattern
+	for _, p := range patterns {
+		if p.Type == TypeTimeTag && p.Key == timeOfDay {
+			matches = append(matches, p)
+		}
+	}
+
+	return rank(matches, func(p *Pattern) string { return p.Value }, limit)
+}
+
+// rank orders patterns by frequency, then recency, then name
+func rank(patterns []*Pattern, value func(*Pattern) string, limit int) []Suggestion {
+	sort.SliceStable(patterns, func(i, j int) bool {
+		if patterns[i].Count != patterns[j].Count {
+			return patterns[i].Count > patterns[j].Count
+		}
+		if !patterns[i].LastUsed.Equal(patterns[j].LastUsed) {
+			return patterns[i].LastUsed.After(patterns[j].LastUsed)
+		}
+		return value(patterns[i]) < value(patterns[j])
+	})
+
+	if limit > 0 && len(patterns) > limit {
+		patterns = patterns[:limit]
+	}
+
+	suggestions := make([]Suggestion, 0, len(patterns))
+	for _, p := range patterns {
+		suggestions = append(suggestions, Suggestion{Value: value(p), Count: p.Count})
+	}
+	return suggestions
+}
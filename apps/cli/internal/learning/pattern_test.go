@@ -0,0 +1,111 @@
+package learning_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/learning"
+)
+
+func TestObserve(t *testing.T) {
+	t.Run("should record tags, time of day and priority keywords", func(t *testing.T) {
+		// Arrange
+		createdAt := time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)
+
+		// Act
+		patterns := learning.Observe("Fix the login bug", []string{"work"}, 3, createdAt, nil)
+
+		// Assert
+		expected := []learning.Pattern{
+			{Type: learning.TypeTag, Key: "work"},
+			{Type: learning.TypeTimeTag, Key: "morning", Value: "work"},
+			{Type: learning.TypePriority, Key: "fix", Value: "3"},
+			{Type: learning.TypePriority, Key: "login", Value: "3"},
+			{Type: learning.TypePriority, Key: "bug", Value: "3"},
+		}
+		if len(patterns) != len(expected) {
+			t.Fatalf("expected %d patterns, got %d", len(expected), len(patterns))
+		}
+		for i, want := range expected {
+			got := patterns[i]
+			if got.Type != want.Type || got.Key != want.Key || got.Value != want.Value {
+				t.Errorf("pattern %d: expected %+v, got %+v", i, want, *got)
+			}
+		}
+	})
+
+	t.Run("should skip excluded tags", func(t *testing.T) {
+		// Act
+		patterns := learning.Observe("Plan party", []string{"Secret"}, 0, time.Now(), []string{"#secret"})
+
+		// Assert
+		if len(patterns) != 0 {
+			t.Errorf("expected no patterns, got %d", len(patterns))
+		}
+	})
+}
+
+func TestKeywords(t *testing.T) {
+	t.Run("should split Japanese titles and drop particles", func(t *testing.T) {
+		// Act
+		keywords := learning.Keywords("来週のミーティング資料を準備する")
+
+		// Assert
+		expected := []string{"来週", "ミーティング", "資料", "準備"}
+		if len(keywords) != len(expected) {
+			t.Fatalf("expected %v, got %v", expected, keywords)
+		}
+		for i, word := range expected {
+			if keywords[i] != word {
+				t.Errorf("keyword %d: expected %q, got %q", i, word, keywords[i])
+			}
+		}
+	})
+}
+
+func TestCompleteTag(t *testing.T) {
+	t.Run("should rank matching tags by frequency then recency", func(t *testing.T) {
+		// Arrange
+		older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		newer := older.AddDate(0, 0, 1)
+		patterns := []*learning.Pattern{
+			{Type: learning.TypeTag, Key: "workshop", Count: 3, LastUsed: newer},
+			{Type: learning.TypeTag, Key: "work", Count: 45, LastUsed: older},
+			{Type: learning.TypeTag, Key: "workout", Count: 3, LastUsed: older},
+			{Type: learning.TypeTag, Key: "home", Count: 50, LastUsed: newer},
+			{Type: learning.TypeTimeTag, Key: "morning", Value: "work", Count: 99},
+		}
+
+		// Act
+		suggestions := learning.CompleteTag(patterns, "#Wo", 0)
+
+		// Assert
+		expected := []string{"work", "workshop", "workout"}
+		if len(suggestions) != len(expected) {
+			t.Fatalf("expected %d suggestions, got %d", len(expected), len(suggestions))
+		}
+		for i, value := range expected {
+			if suggestions[i].Value != value {
+				t.Errorf("suggestion %d: expected %q, got %q", i, value, suggestions[i].Value)
+			}
+		}
+	})
+}
+
+func TestSuggestTagsForTime(t *testing.T) {
+	t.Run("should suggest tags used at the same time of day", func(t *testing.T) {
+		// Arrange
+		patterns := []*learning.Pattern{
+			{Type: learning.TypeTimeTag, Key: "morning", Value: "exercise", Count: 5},
+			{Type: learning.TypeTimeTag, Key: "evening", Value: "reading", Count: 9},
+		}
+
+		// Act
+		suggestions := learning.SuggestTagsForTime(patterns, time.Date(2024, 1, 15, 7, 0, 0, 0, time.UTC), 5)
+
+		// Assert
+		if len(suggestions) != 1 || suggestions[0].Value != "exercise" {
+			t.Errorf("expected [exercise], got %+v", suggestions)
+		}
+	})
+}
@@ -27,3 +27,12 @@ func (c *Cache) Set(key string, value *ExtractedMetadata) {
 
 	c.items[key] = value
 }
+
+// GetForPrompt returns a cached result only if it was produced by the given prompt version
+func (c *Cache) GetForPrompt(key, promptID string) (*ExtractedMetadata, bool) {
+	item, found := c.Get(key)
+	if !found || item.PromptID != promptID {
+		return nil, false
+	}
+	return item, true
+}
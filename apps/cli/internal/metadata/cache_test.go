@@ -35,4 +35,21 @@ func TestMetadataCache(t *testing.T) {
 			t.Errorf("expected priority %q, got %q", expected.Priority, result.Priority)
 		}
 	})
+	t.Run("returns results only for the prompt version that made them", func(t *testing.T) {
+		// Arrange
+		cache := metadata.NewCache()
+		cache.Set("test input", &metadata.ExtractedMetadata{CleanedText: "test", PromptID: "metadata@v1"})
+
+		// Act
+		_, current := cache.GetForPrompt("test input", "metadata@v1")
+		_, stale := cache.GetForPrompt("test input", "metadata@v2")
+
+		// Assert
+		if !current {
+			t.Error("expected a result for the prompt version that made it")
+		}
+		if stale {
+			t.Error("expected no result for a newer prompt version")
+		}
+	})
 }
@@ -6,14 +6,64 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"regexp"
 	"strings"
 	"time"
+
+	"github.com/tennashi/tabler/internal/claude"
+	"github.com/tennashi/tabler/internal/prompts"
 )
 
-type ClaudeClient struct{}
+// metadataSchema declares the response the metadata prompt must produce
+var metadataSchema = &claude.Schema{
+	Fields: []claude.Field{
+		{Name: "cleaned_text", Type: claude.StringField, Required: true, MaxLength: 500},
+		{Name: "deadline", Type: claude.StringField, Format: "2006-01-02", AllowEmpty: true},
+		{
+			Name:      "tags",
+			Type:      claude.StringListField,
+			MaxItems:  10,
+			MaxLength: 32,
+			Pattern:   regexp.MustCompile(`^[^\s#,]+$`),
+		},
+		{Name: "priority", Type: claude.StringField, Enum: []string{"low", "medium", "high"}, AllowEmpty: true},
+		{Name: "confidence", Type: claude.NumberField, Min: 0, Max: 1},
+		{Name: "reasoning", Type: claude.StringField},
+	},
+}
+
+type ClaudeClient struct {
+	executor *claude.StructuredExecutor
+	prompts  *prompts.Library
+	now      func() time.Time
+}
 
 func NewClaudeClient() *ClaudeClient {
-	return &ClaudeClient{}
+	return NewClaudeClientWithProvider(NewSubprocessProvider())
+}
+
+// NewClaudeClientWithProvider creates a client that sends prompts to the given provider
+func NewClaudeClientWithProvider(provider claude.Provider) *ClaudeClient {
+	return &ClaudeClient{
+		executor: claude.NewStructuredExecutor(provider),
+		prompts:  prompts.Default(),
+		now:      time.Now,
+	}
+}
+
+// SetPrompts sets the prompt library used to build extraction prompts
+func (c *ClaudeClient) SetPrompts(library *prompts.Library) {
+	c.prompts = library
+}
+
+// PromptID returns the version of the metadata prompt the next extraction uses
+func (c *ClaudeClient) PromptID() (string, error) {
+	return c.prompts.ID(prompts.Metadata)
+}
+
+// SetClock sets the time source used for relative dates (for testing)
+func (c *ClaudeClient) SetClock(now func() time.Time) {
+	c.now = now
 }
 
 type promptRequest struct {
@@ -36,16 +86,24 @@ func (c *ClaudeClient) FormatPrompt(input string, currentTime time.Time, timezon
 }
 
 func (c *ClaudeClient) ExtractMetadata(ctx context.Context, input string) (*ExtractedMetadata, error) {
-	// Use current time and default timezone
-	currentTime := time.Now()
+	// Use current time and its timezone
+	currentTime := c.now()
 	timezone := "UTC"
-	if tz := time.Local.String(); tz != "" {
+	if tz := currentTime.Location().String(); tz != "" {
 		timezone = tz
 	}
 
 	return c.ExecuteClaudeSubprocess(ctx, input, currentTime, timezone)
 }
 
+// metadataPromptData is the data available to the metadata template
+type metadataPromptData struct {
+	Request         string
+	Input           string
+	CurrentDateTime string
+	Timezone        string
+}
+
 type claudeResponse struct {
 	CleanedText string   `json:"cleaned_text"`
 	Deadline    string   `json:"deadline"`
@@ -64,28 +122,44 @@ func (c *ClaudeClient) ExecuteClaudeSubprocess(
 	prompt := c.FormatPrompt(input, currentTime, timezone)
 
 	// Prepare the prompt for Claude
-	claudePrompt := fmt.Sprintf(`You are a task metadata extractor. Given this task input:
-%s
-
-Extract and return ONLY valid JSON (no markdown, no explanation) with this exact structure:
-{
-  "cleaned_text": "task title without metadata",
-  "deadline": "YYYY-MM-DD or empty string",
-  "tags": ["tag1", "tag2"],
-  "priority": "low/medium/high",
-  "confidence": 0.0-1.0,
-  "reasoning": "brief explanation"
+	rendered, err := c.prompts.Render(prompts.Metadata, metadataPromptData{
+		Request:         prompt,
+		Input:           input,
+		CurrentDateTime: currentTime.Format(time.RFC3339),
+		Timezone:        timezone,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the response, asking Claude to repair it when needed
+	ctx = claude.WithFeature(ctx, claude.FeatureMetadata)
+	var response claudeResponse
+	if err := c.executor.Execute(ctx, rendered.Text, metadataSchema, &response); err != nil {
+		return nil, err
+	}
+
+	return &ExtractedMetadata{
+		CleanedText: response.CleanedText,
+		Deadline:    response.Deadline,
+		Tags:        response.Tags,
+		Priority:    response.Priority,
+		PromptID:    rendered.ID,
+	}, nil
 }
 
-Rules:
-- cleaned_text: Remove all metadata (tags, dates, priority markers) from the task title
-- deadline: Extract dates and convert to YYYY-MM-DD format
-- tags: Extract meaningful categories/labels from the task content
-- priority: Determine from urgency keywords (urgent/ASAP = high, important = medium, default = low)
-- For Japanese input, extract metadata but keep cleaned_text in original language`, prompt)
+// SubprocessProvider runs prompts through the claude CLI in print mode
+type SubprocessProvider struct{}
+
+// NewSubprocessProvider creates a provider backed by the claude CLI
+func NewSubprocessProvider() *SubprocessProvider {
+	return &SubprocessProvider{}
+}
 
+// Execute implements claude.Provider
+func (p *SubprocessProvider) Execute(ctx context.Context, prompt string) (string, error) {
 	// #nosec G204 - We control all inputs to this command
-	cmd := exec.CommandContext(ctx, "claude", "-p", claudePrompt)
+	cmd := exec.CommandContext(ctx, "claude", "-p", prompt)
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -95,41 +169,10 @@ Rules:
 	err := cmd.Run()
 	if err != nil {
 		if ctx.Err() != nil {
-			return nil, ctx.Err()
+			return "", ctx.Err()
 		}
-		return nil, fmt.Errorf("claude execution failed: %w, stderr: %s", err, stderr.String())
+		return "", fmt.Errorf("claude execution failed: %w, stderr: %s", err, stderr.String())
 	}
 
-	// Parse response
-	var response claudeResponse
-	output := stdout.String()
-
-	if err := json.Unmarshal([]byte(output), &response); err != nil {
-		// Try to extract JSON from the output (Claude might include markdown)
-		// Look for JSON between ```json and ```
-		jsonStart := strings.Index(output, "```json")
-		jsonEnd := strings.LastIndex(output, "```")
-		if jsonStart != -1 && jsonEnd != -1 && jsonStart < jsonEnd {
-			jsonStart += 7 // Skip "```json"
-			jsonContent := strings.TrimSpace(output[jsonStart:jsonEnd])
-			if err := json.Unmarshal([]byte(jsonContent), &response); err == nil {
-				// Successfully parsed from markdown block
-				goto parsed
-			}
-		}
-
-		// Fallback to simple extraction if JSON parsing fails
-		return &ExtractedMetadata{
-			CleanedText: input,
-		}, nil
-	}
-
-parsed:
-
-	return &ExtractedMetadata{
-		CleanedText: response.CleanedText,
-		Deadline:    response.Deadline,
-		Tags:        response.Tags,
-		Priority:    response.Priority,
-	}, nil
+	return strings.TrimSpace(stdout.String()), nil
 }
@@ -2,10 +2,12 @@ package metadata_test
 
 import (
 	"context"
+	"errors"
 	"os/exec"
 	"testing"
 	"time"
 
+	"github.com/tennashi/tabler/internal/claude"
 	"github.com/tennashi/tabler/internal/metadata"
 )
 
@@ -53,4 +55,64 @@ func TestClaudeClient(t *testing.T) {
 			t.Errorf("expected context deadline error, got: %v", err)
 		}
 	})
+
+	t.Run("validates response against schema", func(t *testing.T) {
+		// Arrange
+		provider := &mockProvider{responses: []string{
+			`{"cleaned_text": "finish report", "deadline": "tomorrow", "priority": "urgent"}`,
+			`{"cleaned_text": "finish report", "deadline": "2024-01-16", "tags": ["work"], "priority": "high"}`,
+		}}
+		client := metadata.NewClaudeClientWithProvider(provider)
+		currentTime := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+		// Act
+		result, err := client.ExecuteClaudeSubprocess(context.Background(), "urgent: finish report by tomorrow #work",
+			currentTime, "UTC")
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if provider.calls != 2 {
+			t.Errorf("expected 2 calls after repair, got %d", provider.calls)
+		}
+		if result.Deadline != "2024-01-16" {
+			t.Errorf("expected deadline 2024-01-16, got %q", result.Deadline)
+		}
+		if result.Priority != "high" {
+			t.Errorf("expected priority high, got %q", result.Priority)
+		}
+	})
+
+	t.Run("surfaces typed error when response stays invalid", func(t *testing.T) {
+		// Arrange
+		provider := &mockProvider{responses: []string{"I could not understand the task."}}
+		client := metadata.NewClaudeClientWithProvider(provider)
+
+		// Act
+		result, err := client.ExtractMetadata(context.Background(), "finish report")
+
+		// Assert
+		var invalidErr *claude.InvalidResponseError
+		if !errors.As(err, &invalidErr) {
+			t.Fatalf("expected InvalidResponseError, got %v", err)
+		}
+		if result != nil {
+			t.Error("expected nil result")
+		}
+	})
+}
+
+// mockProvider returns canned responses in order, repeating the last one
+type mockProvider struct {
+	responses []string
+	calls     int
+}
+
+func (m *mockProvider) Execute(_ context.Context, _ string) (string, error) {
+	m.calls++
+	i := m.calls - 1
+	if i >= len(m.responses) {
+		i = len(m.responses) - 1
+	}
+	return m.responses[i], nil
 }
@@ -7,21 +7,30 @@ import (
 
 type Service struct {
 	claude Claude
+	cache  *Cache
 }
 
 type Claude interface {
 	ExtractMetadata(ctx context.Context, input string) (*ExtractedMetadata, error)
 }
 
+// PromptVersioner is implemented by Claude clients whose results can be cached:
+// a cached result is reused only while the prompt version it was made with is current
+type PromptVersioner interface {
+	PromptID() (string, error)
+}
+
 type ExtractedMetadata struct {
 	CleanedText string
 	Deadline    string
 	Tags        []string
 	Priority    string
+	// PromptID identifies the prompt template version that produced this result
+	PromptID string
 }
 
 func NewService(claude Claude) *Service {
-	return &Service{claude: claude}
+	return &Service{claude: claude, cache: NewCache()}
 }
 
 func (s *Service) Extract(ctx context.Context, input string) (*ExtractedMetadata, error) {
@@ -31,7 +40,7 @@ func (s *Service) Extract(ctx context.Context, input string) (*ExtractedMetadata
 
 	// If we have a claude client, use it for extraction
 	if s.claude != nil {
-		return s.claude.ExtractMetadata(ctx, input)
+		return s.extractWithClaude(ctx, input)
 	}
 
 	// Fallback to simple extraction
@@ -39,3 +48,26 @@ func (s *Service) Extract(ctx context.Context, input string) (*ExtractedMetadata
 		CleanedText: input,
 	}, nil
 }
+
+// extractWithClaude asks Claude, reusing results cached for the current prompt version
+func (s *Service) extractWithClaude(ctx context.Context, input string) (*ExtractedMetadata, error) {
+	versioner, ok := s.claude.(PromptVersioner)
+	if !ok {
+		return s.claude.ExtractMetadata(ctx, input)
+	}
+
+	promptID, err := versioner.PromptID()
+	if err != nil {
+		return nil, err
+	}
+	if cached, found := s.cache.GetForPrompt(input, promptID); found {
+		return cached, nil
+	}
+
+	result, err := s.claude.ExtractMetadata(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Set(input, result)
+	return result, nil
+}
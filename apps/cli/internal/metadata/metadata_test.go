@@ -2,9 +2,12 @@ package metadata_test
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/tennashi/tabler/internal/metadata"
+	"github.com/tennashi/tabler/internal/prompts"
 )
 
 func TestMetadataService(t *testing.T) {
@@ -156,6 +159,49 @@ func TestMetadataService(t *testing.T) {
 			}
 		})
 	})
+
+	t.Run("caching", func(t *testing.T) {
+		t.Run("reuses results until the prompt version changes", func(t *testing.T) {
+			// Arrange
+			dir := t.TempDir()
+			provider := &mockProvider{responses: []string{
+				`{"cleaned_text": "finish report", "deadline": "2024-01-16", "tags": ["work"], "priority": "high"}`,
+			}}
+			client := metadata.NewClaudeClientWithProvider(provider)
+			client.SetPrompts(prompts.NewLibrary(dir))
+			service := metadata.NewService(client)
+			ctx := context.Background()
+
+			// Act
+			first, err := service.Extract(ctx, "finish report by tomorrow")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, err := service.Extract(ctx, "finish report by tomorrow"); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			callsBeforeOverride := provider.calls
+			override := []byte("Extract task metadata as JSON from: {{.Input}}")
+			if err := os.WriteFile(filepath.Join(dir, prompts.Metadata+".tmpl"), override, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			second, err := service.Extract(ctx, "finish report by tomorrow")
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if callsBeforeOverride != 1 {
+				t.Errorf("expected the second extraction to be cached, got %d calls", callsBeforeOverride)
+			}
+			if provider.calls != 2 {
+				t.Errorf("expected a new prompt version to call Claude again, got %d calls", provider.calls)
+			}
+			if first.PromptID == second.PromptID {
+				t.Errorf("expected cached results to record their prompt version, got %q twice", first.PromptID)
+			}
+		})
+	})
 }
 
 type mockClaude struct {
@@ -20,15 +20,12 @@ func NewStorageAdapter(storage *storage.Storage) *StorageAdapter {
 
 // Create implements StorageWithDecomposition
 func (s *StorageAdapter) Create(t *task.Task) error {
-	return s.storage.CreateTask(t, []string{})
+	return s.storage.CreateTask(t, t.Tags)
 }
 
-// CreateWithParent implements StorageWithDecomposition
-func (s *StorageAdapter) CreateWithParent(t *task.Task, parentID string) error {
-	// For now, just create the task without parent relationship
-	// TODO: Implement parent-child relationship in storage when parentID is provided
-	_ = parentID // Will be used when parent-child relationship is implemented
-	return s.storage.CreateTask(t, []string{})
+// CreateTree implements StorageWithDecomposition
+func (s *StorageAdapter) CreateTree(root *task.Task) error {
+	return s.storage.CreateTaskTree(root)
 }
 
 // DecomposerAdapter adapts decomposition.TaskDecomposer to Decomposer interface
@@ -46,6 +43,11 @@ func (d *DecomposerAdapter) Decompose(ctx context.Context, task string) (*decomp
 	return d.decomposer.Decompose(ctx, task)
 }
 
+// Regenerate implements decomposition.Regenerator
+func (d *DecomposerAdapter) Regenerate(ctx context.Context, task string, subtasks []string, index int) (string, error) {
+	return d.decomposer.Regenerate(ctx, task, subtasks, index)
+}
+
 // PresenterAdapter adapts decomposition.InteractivePresenter to Presenter interface
 type PresenterAdapter struct {
 	presenter *decomposition.InteractivePresenter
@@ -61,6 +63,11 @@ func (p *PresenterAdapter) Present(result *decomposition.DecompositionResult) st
 	return p.presenter.Present(result)
 }
 
+// PresentSubtasks implements Presenter
+func (p *PresenterAdapter) PresentSubtasks(task string, subtasks []decomposition.Subtask) string {
+	return p.presenter.PresentSubtasks(task, subtasks)
+}
+
 // ParseSelection implements Presenter
 func (p *PresenterAdapter) ParseSelection(input string, total int) ([]int, error) {
 	return p.presenter.ParseSelection(input, total)
@@ -1,18 +1,27 @@
 package mode
 
 import (
+	"os"
+
 	"github.com/tennashi/tabler/internal/clarification"
 	"github.com/tennashi/tabler/internal/claude"
 	"github.com/tennashi/tabler/internal/decomposition"
-	"github.com/tennashi/tabler/internal/storage"
+	"github.com/tennashi/tabler/internal/language"
+	"github.com/tennashi/tabler/internal/prompts"
 )
 
 // ManagerBuilder helps build a ModeManager with optional features
 type ManagerBuilder struct {
 	useClarification bool
 	useDecomposition bool
-	claudeClient     *claude.Client
-	storage          *storage.Storage
+	provider         claude.Provider
+	prompts          *prompts.Library
+	storage          StorageWithDecomposition
+	history          ChoiceHistory
+	dialogues        DialogueStore
+	answers          map[string]string
+	language         language.Language
+	planningDepth    int
 }
 
 // NewManagerBuilder creates a new builder
@@ -20,69 +29,150 @@ func NewManagerBuilder() *ManagerBuilder {
 	return &ManagerBuilder{}
 }
 
+// WithProvider sets the AI provider used by clarification and decomposition
+func (b *ManagerBuilder) WithProvider(provider claude.Provider) *ManagerBuilder {
+	b.provider = provider
+	return b
+}
+
+// WithPrompts sets the prompt library used by clarification and decomposition
+func (b *ManagerBuilder) WithPrompts(library *prompts.Library) *ManagerBuilder {
+	b.prompts = library
+	return b
+}
+
+// WithHistory lets mode detection learn from explicitly chosen modes
+func (b *ManagerBuilder) WithHistory(history ChoiceHistory) *ManagerBuilder {
+	b.history = history
+	return b
+}
+
+// WithDialogueStore saves talk-mode dialogues so interrupted ones can be resumed
+func (b *ManagerBuilder) WithDialogueStore(store DialogueStore) *ManagerBuilder {
+	b.dialogues = store
+	return b
+}
+
+// WithAnswers makes talk-mode clarification non-interactive, answering questions
+// from answers and stopping at the first one left unanswered
+func (b *ManagerBuilder) WithAnswers(answers map[string]string) *ManagerBuilder {
+	b.answers = answers
+	return b
+}
+
+// WithLanguage fixes the input language used by mode, vagueness and complexity detection
+func (b *ManagerBuilder) WithLanguage(lang language.Language) *ManagerBuilder {
+	b.language = lang
+	return b
+}
+
 // WithClarification enables dialogue-based clarification for Talk mode
 func (b *ManagerBuilder) WithClarification() *ManagerBuilder {
 	b.useClarification = true
-	if b.claudeClient == nil {
-		b.claudeClient = claude.NewClient()
+	if b.provider == nil {
+		b.provider = claude.NewClient()
 	}
 	return b
 }
 
-// WithDecomposition enables task decomposition for Planning mode
-func (b *ManagerBuilder) WithDecomposition(storage *storage.Storage) *ManagerBuilder {
+// WithDecomposition enables task decomposition for Planning mode, storing plans in
+// storage. With nil storage, plans are returned unsaved for the caller to store.
+func (b *ManagerBuilder) WithDecomposition(storage StorageWithDecomposition) *ManagerBuilder {
 	b.useDecomposition = true
 	b.storage = storage
-	if b.claudeClient == nil {
-		b.claudeClient = claude.NewClient()
+	if b.provider == nil {
+		b.provider = claude.NewClient()
 	}
 	return b
 }
 
+// WithPlanningDepth limits how many levels of subtasks a decomposed plan may have
+func (b *ManagerBuilder) WithPlanningDepth(depth int) *ManagerBuilder {
+	b.planningDepth = depth
+	return b
+}
+
 // Build creates the ModeManager with configured features
 func (b *ManagerBuilder) Build() *ModeManager {
 	manager := &ModeManager{
 		handlers: make(map[Mode]ModeHandler),
 		detector: NewModeDetector(),
 	}
+	if b.history != nil {
+		manager.detector.SetHistory(b.history)
+	}
+	if b.language != "" {
+		manager.detector.SetLanguage(b.language)
+	}
 
 	// Always register Quick handler
 	manager.RegisterHandler(QuickMode, NewQuickHandler())
 
 	// Register Talk handler with or without clarification
-	if b.useClarification && b.claudeClient != nil {
+	if b.useClarification && b.provider != nil {
 		// Create clarification components
 		vaguenessDetector := clarification.NewVaguenessDetector()
-		questionGen := clarification.NewQuestionGenerator(b.claudeClient)
-		responseProcessor := clarification.NewResponseProcessor()
+		if b.language != "" {
+			vaguenessDetector.SetLanguage(b.language)
+		}
+		questionGen := clarification.NewQuestionGenerator(b.provider)
+		if b.prompts != nil {
+			questionGen.SetPrompts(b.prompts)
+		}
+		responseProcessor := clarification.NewAIResponseProcessor(b.provider)
+		if b.prompts != nil {
+			responseProcessor.SetPrompts(b.prompts)
+		}
 		dialogueManager := clarification.NewDialogueManager(vaguenessDetector, questionGen, responseProcessor)
 
 		// Use enhanced Talk handler
-		manager.RegisterHandler(TalkMode, NewTalkHandlerWithClarification(dialogueManager))
+		talkHandler := NewTalkHandlerWithClarification(dialogueManager)
+		if b.dialogues != nil {
+			talkHandler.SetDialogueStore(b.dialogues)
+		}
+		if b.answers != nil {
+			talkHandler.SetAnswers(b.answers)
+			// Keep stdout free for the machine-readable result
+			talkHandler.SetOutput(os.Stderr)
+		}
+		manager.RegisterHandler(TalkMode, talkHandler)
 	} else {
 		// Use basic Talk handler
 		manager.RegisterHandler(TalkMode, NewTalkHandler())
 	}
 
 	// Register Planning handler with or without decomposition
-	if b.useDecomposition && b.storage != nil && b.claudeClient != nil {
+	if b.useDecomposition && b.provider != nil {
 		// Create decomposition components
 		complexityDetector := decomposition.NewComplexityDetector()
-		decomposer := decomposition.NewTaskDecomposer(b.claudeClient)
+		if b.language != "" {
+			complexityDetector.SetLanguage(b.language)
+		}
+		decomposer := decomposition.NewTaskDecomposer(b.provider)
+		if b.prompts != nil {
+			decomposer.SetPrompts(b.prompts)
+		}
 		presenter := decomposition.NewInteractivePresenter()
 
 		// Create adapters
-		storageAdapter := NewStorageAdapter(b.storage)
 		decomposerAdapter := NewDecomposerAdapter(decomposer)
 		presenterAdapter := NewPresenterAdapter(presenter)
 
 		// Use enhanced Planning handler
-		manager.RegisterHandler(PlanningMode, NewPlanningHandlerWithDecomposition(
-			storageAdapter,
+		planningHandler := NewPlanningHandlerWithDecomposition(
+			b.storage,
 			complexityDetector,
 			decomposerAdapter,
 			presenterAdapter,
-		))
+		)
+		if b.planningDepth > 0 {
+			planningHandler.SetMaxDepth(b.planningDepth)
+		}
+		if b.storage != nil {
+			manager.RegisterHandler(PlanningMode, planningHandler)
+		} else {
+			manager.RegisterHandler(PlanningMode, unsavedPlanHandler{planner: planningHandler})
+		}
 	} else {
 		// Use basic Planning handler
 		manager.RegisterHandler(PlanningMode, NewPlanningHandler())
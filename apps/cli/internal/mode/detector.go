@@ -1,41 +1,212 @@
 package mode
 
 import (
+	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/tennashi/tabler/internal/language"
+	"github.com/tennashi/tabler/internal/learning"
+)
+
+const (
+	// quickBaseline makes Quick mode win when nothing else stands out
+	quickBaseline = 0.5
+	// shortInputWeight makes very short input decisively Quick
+	shortInputWeight = 2.0
+	// keywordWeight is added for each question word or planning keyword
+	keywordWeight = 1.0
+	// learnedWeight is the most a single learned word can add to a mode
+	learnedWeight = 1.5
+	// fullConfidenceChoices is how many explicit choices a word needs before its vote counts fully
+	fullConfidenceChoices = 3
 )
 
+// modeVocabulary holds the words that point at Talk or Planning mode in one language
+type modeVocabulary struct {
+	// questionWords suggest the user is unsure and wants Talk mode
+	questionWords []string
+	// planningKeywords suggest a larger piece of work for Planning mode
+	planningKeywords []string
+	// shortLength is the character count below which input is decisively Quick
+	shortLength int
+}
+
+// modeVocabularies are the supported languages
+var modeVocabularies = map[language.Language]*modeVocabulary{
+	language.English: {
+		questionWords: []string{"what", "how", "why", "when", "where", "should", "could", "would"},
+		planningKeywords: []string{
+			"plan", "plans", "planning", "organize", "organise", "organizing",
+			"prepare", "preparing", "strategy", "roadmap", "project", "projects",
+		},
+		shortLength: 10,
+	},
+	language.Japanese: {
+		questionWords:    []string{"何", "なに", "どう", "なぜ", "いつ", "どこ", "べき"},
+		planningKeywords: []string{"計画", "企画", "準備", "戦略", "ロードマップ", "プロジェクト", "整理"},
+		shortLength:      5,
+	},
+}
+
+// ChoiceHistory stores the modes a user explicitly chose, keyed by input words
+type ChoiceHistory interface {
+	// ModeVotes returns, for each word, how often each mode was chosen for inputs containing it
+	ModeVotes(words []string) (map[string]map[string]int, error)
+	// RecordModeChoice remembers that mode was chosen for an input with these words
+	RecordModeChoice(words []string, mode string) error
+}
+
+// Reason is one signal that contributed to a mode's score
+type Reason struct {
+	Mode   Mode
+	Weight float64
+	Text   string
+}
+
+// Explanation describes how a mode was chosen
+type Explanation struct {
+	Mode    Mode
+	Scores  map[Mode]float64
+	Reasons []Reason
+}
+
 // ModeDetector detects appropriate mode based on input characteristics
-type ModeDetector struct{}
+type ModeDetector struct {
+	history  ChoiceHistory
+	language language.Language
+}
 
 // NewModeDetector creates a new mode detector
 func NewModeDetector() *ModeDetector {
-	return &ModeDetector{}
+	return &ModeDetector{language: language.Auto}
+}
+
+// SetLanguage fixes the input language instead of detecting it per input
+func (d *ModeDetector) SetLanguage(lang language.Language) {
+	d.language = lang
+}
+
+// SetHistory sets where explicit mode choices are learned from
+func (d *ModeDetector) SetHistory(history ChoiceHistory) {
+	d.history = history
 }
 
 // DetectMode analyzes input and returns the recommended mode
 func (d *ModeDetector) DetectMode(input string) Mode {
-	// Very short input (<10 chars) → Quick mode
-	if len(input) < 10 {
-		return QuickMode
+	return d.Explain(input).Mode
+}
+
+// Explain scores every mode for input and reports the signals behind the scores
+func (d *ModeDetector) Explain(input string) *Explanation {
+	e := &Explanation{
+		Scores: map[Mode]float64{QuickMode: 0, TalkMode: 0, PlanningMode: 0},
 	}
+	add := func(mode Mode, weight float64, text string) {
+		e.Scores[mode] += weight
+		e.Reasons = append(e.Reasons, Reason{Mode: mode, Weight: weight, Text: text})
+	}
+
+	add(QuickMode, quickBaseline, "default")
+
+	text := language.Analyze(input, d.language)
+	vocabulary := modeVocabularies[text.Language]
 
-	// Contains question words → Talk mode
-	lowerInput := strings.ToLower(input)
-	questionWords := []string{"what", "how", "why", "when", "where", "should", "could", "would"}
-	for _, word := range questionWords {
-		if strings.Contains(lowerInput, word) {
-			return TalkMode
+	// Very short input → Quick mode
+	if text.Length() < vocabulary.shortLength {
+		add(QuickMode, shortInputWeight, "very short input")
+	}
+
+	// English matches whole words only, so "whatever" is not a question
+	for _, word := range vocabulary.questionWords {
+		if text.Has(word) {
+			add(TalkMode, keywordWeight, fmt.Sprintf("question word %q", word))
 		}
 	}
+	for _, word := range vocabulary.planningKeywords {
+		if text.Has(word) {
+			add(PlanningMode, keywordWeight, fmt.Sprintf("planning keyword %q", word))
+		}
+	}
+	trimmed := strings.TrimSpace(input)
+	if strings.HasSuffix(trimmed, "?") || strings.HasSuffix(trimmed, "？") {
+		add(TalkMode, keywordWeight, "ends with a question mark")
+	}
+
+	d.addLearned(input, add)
+
+	e.Mode = bestMode(e.Scores)
+	return e
+}
+
+// RecordChoice remembers an explicitly chosen mode so future detection leans towards it
+func (d *ModeDetector) RecordChoice(input string, mode Mode) error {
+	if d.history == nil {
+		return nil
+	}
+
+	keywords := learning.Keywords(input)
+	if len(keywords) == 0 {
+		return nil
+	}
+
+	return d.history.RecordModeChoice(keywords, string(mode))
+}
+
+// addLearned adds the user's past explicit choices for the words in input
+func (d *ModeDetector) addLearned(input string, add func(Mode, float64, string)) {
+	if d.history == nil {
+		return
+	}
 
-	// Contains planning keywords → Planning mode
-	planningKeywords := []string{"plan", "organize", "prepare", "strategy", "roadmap", "project"}
-	for _, keyword := range planningKeywords {
-		if strings.Contains(lowerInput, keyword) {
-			return PlanningMode
+	keywords := learning.Keywords(input)
+	if len(keywords) == 0 {
+		return
+	}
+
+	votes, err := d.history.ModeVotes(keywords)
+	if err != nil {
+		// Learning is optional; fall back to the built-in heuristics
+		return
+	}
+
+	for _, word := range keywords {
+		total := 0
+		for _, count := range votes[word] {
+			total += count
+		}
+		if total == 0 {
+			continue
+		}
+
+		confidence := float64(min(total, fullConfidenceChoices)) / fullConfidenceChoices
+		for _, mode := range []Mode{QuickMode, TalkMode, PlanningMode} {
+			count := votes[word][string(mode)]
+			if count == 0 {
+				continue
+			}
+			weight := learnedWeight * confidence * float64(count) / float64(total)
+			add(mode, weight, fmt.Sprintf("you chose %s for %q %d of %d times", mode, word, count, total))
+		}
+	}
+}
+
+// bestMode picks the highest score; ties favour Talk, then Planning, then Quick
+func bestMode(scores map[Mode]float64) Mode {
+	best := QuickMode
+	for _, mode := range []Mode{PlanningMode, TalkMode} {
+		if scores[mode] >= scores[best] && scores[mode] > 0 {
+			best = mode
 		}
 	}
+	return best
+}
 
-	// Default → Quick mode
-	return QuickMode
+// SortedReasons returns the reasons with the strongest first
+func (e *Explanation) SortedReasons() []Reason {
+	reasons := append([]Reason{}, e.Reasons...)
+	sort.SliceStable(reasons, func(i, j int) bool {
+		return reasons[i].Weight > reasons[j].Weight
+	})
+	return reasons
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/tennashi/tabler/internal/clarification"
 	"github.com/tennashi/tabler/internal/task"
 )
 
@@ -49,9 +50,15 @@ func (m *ModeManager) ProcessInput(input string) (*ProcessResult, error) {
 func (m *ModeManager) ProcessTask(ctx context.Context, input string) (*task.Task, error) {
 	mode, taskText, hasPrefix := ParseModePrefix(input)
 
-	// If no prefix, use auto-detection
-	if !hasPrefix && m.detector != nil {
-		mode = m.detector.DetectMode(input)
+	if m.detector != nil {
+		if !hasPrefix {
+			// If no prefix, use auto-detection
+			mode = m.detector.DetectMode(input)
+		} else if m.detector.DetectMode(taskText) != mode {
+			// Choices that override auto-detection teach the detector; failing to
+			// learn must not block the task
+			_ = m.detector.RecordChoice(taskText, mode)
+		}
 	}
 
 	handler, exists := m.handlers[mode]
@@ -61,3 +68,36 @@ func (m *ModeManager) ProcessTask(ctx context.Context, input string) (*task.Task
 
 	return handler.Process(ctx, taskText)
 }
+
+// DialogueResumer is implemented by handlers that can continue a saved dialogue
+type DialogueResumer interface {
+	Resume(ctx context.Context, session *clarification.DialogueSession) (*task.Task, error)
+}
+
+// Resume continues a saved talk-mode dialogue
+func (m *ModeManager) Resume(ctx context.Context, session *clarification.DialogueSession) (*task.Task, error) {
+	resumer, ok := m.handlers[TalkMode].(DialogueResumer)
+	if !ok {
+		return nil, fmt.Errorf("talk mode cannot resume dialogues without clarification")
+	}
+	return resumer.Resume(ctx, session)
+}
+
+// DetectMode returns the mode ProcessTask uses for input: the prefixed mode, or
+// the auto-detected one for input without a prefix
+func (m *ModeManager) DetectMode(input string) Mode {
+	mode, _, hasPrefix := ParseModePrefix(input)
+	if hasPrefix || m.detector == nil {
+		return mode
+	}
+	return m.detector.DetectMode(input)
+}
+
+// ExplainMode reports which mode auto-detection picks for the task text and why
+func (m *ModeManager) ExplainMode(input string) *Explanation {
+	_, taskText, _ := ParseModePrefix(input)
+	if m.detector == nil {
+		return &Explanation{Mode: QuickMode, Scores: map[Mode]float64{QuickMode: 0}}
+	}
+	return m.detector.Explain(taskText)
+}
@@ -219,6 +219,11 @@ func TestModeDetector(t *testing.T) {
 			{"planning keyword", "plan company retreat", PlanningMode},
 			{"organize keyword", "organize conference", PlanningMode},
 			{"simple task", "fix bug in login page", QuickMode},
+			{"question word inside another word", "whatever, buy milk", QuickMode},
+			{"question mark", "dentist appointment?", TalkMode},
+			{"Japanese short input", "牛乳を買う", QuickMode},
+			{"Japanese question word", "会議で何を話すべきか", TalkMode},
+			{"Japanese planning keyword", "社員旅行の計画を立てる", PlanningMode},
 		}
 
 		for _, tt := range tests {
@@ -237,3 +242,126 @@ func TestModeDetector(t *testing.T) {
 		}
 	})
 }
+
+func TestModeDetectorLearning(t *testing.T) {
+	t.Run("should lean towards modes the user chose explicitly", func(t *testing.T) {
+		// Arrange
+		history := newFakeHistory()
+		detector := NewModeDetector()
+		detector.SetHistory(history)
+		for i := 0; i < 3; i++ {
+			if err := detector.RecordChoice("plan groceries", QuickMode); err != nil {
+				t.Fatalf("failed to record choice: %v", err)
+			}
+		}
+
+		// Act
+		explanation := detector.Explain("plan groceries for the week")
+
+		// Assert
+		if explanation.Mode != QuickMode {
+			t.Errorf("expected learned quick mode, got %v (scores %v)", explanation.Mode, explanation.Scores)
+		}
+		found := false
+		for _, reason := range explanation.Reasons {
+			if reason.Text == `you chose quick for "groceries" 3 of 3 times` {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected learned reason, got %+v", explanation.Reasons)
+		}
+	})
+
+	t.Run("should keep built-in heuristics without history", func(t *testing.T) {
+		// Arrange
+		detector := NewModeDetector()
+
+		// Act
+		explanation := detector.Explain("plan groceries for the week")
+
+		// Assert
+		if explanation.Mode != PlanningMode {
+			t.Errorf("expected planning mode, got %v", explanation.Mode)
+		}
+	})
+}
+
+func TestModeManagerRecordsExplicitChoice(t *testing.T) {
+	t.Run("should record a prefixed mode that overrides detection", func(t *testing.T) {
+		// Arrange
+		history := newFakeHistory()
+		manager := NewManagerBuilder().WithHistory(history).Build()
+
+		// Act
+		_, err := manager.ProcessTask(context.Background(), "/q plan groceries")
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if history.votes["groceries"]["quick"] != 1 {
+			t.Errorf("expected quick choice to be recorded, got %v", history.votes)
+		}
+	})
+
+	t.Run("should not record a prefixed mode that detection agrees with", func(t *testing.T) {
+		// Arrange
+		history := newFakeHistory()
+		manager := NewManagerBuilder().WithHistory(history).Build()
+
+		// Act
+		_, err := manager.ProcessTask(context.Background(), "/q buy groceries today")
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(history.votes) != 0 {
+			t.Errorf("expected nothing recorded, got %v", history.votes)
+		}
+	})
+
+	t.Run("should not record auto-detected modes", func(t *testing.T) {
+		// Arrange
+		history := newFakeHistory()
+		manager := NewManagerBuilder().WithHistory(history).Build()
+
+		// Act
+		_, err := manager.ProcessTask(context.Background(), "buy groceries today")
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(history.votes) != 0 {
+			t.Errorf("expected nothing recorded, got %v", history.votes)
+		}
+	})
+}
+
+// fakeHistory keeps mode choices in memory
+type fakeHistory struct {
+	votes map[string]map[string]int
+}
+
+func newFakeHistory() *fakeHistory {
+	return &fakeHistory{votes: make(map[string]map[string]int)}
+}
+
+func (h *fakeHistory) ModeVotes(words []string) (map[string]map[string]int, error) {
+	result := make(map[string]map[string]int)
+	for _, word := range words {
+		if votes, ok := h.votes[word]; ok {
+			result[word] = votes
+		}
+	}
+	return result, nil
+}
+
+func (h *fakeHistory) RecordModeChoice(words []string, mode string) error {
+	for _, word := range words {
+		if h.votes[word] == nil {
+			h.votes[word] = make(map[string]int)
+		}
+		h.votes[word][mode]++
+	}
+	return nil
+}
@@ -3,6 +3,8 @@ package mode
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/tennashi/tabler/internal/decomposition"
@@ -20,11 +22,11 @@ func TestPlanningHandlerWithDecomposition(t *testing.T) {
 			decomposer := &mockDecomposer{
 				result: &decomposition.DecompositionResult{
 					OriginalTask: "organize conference",
-					Subtasks: []string{
+					Subtasks: decomposition.NewSubtasks([]string{
 						"Book venue",
 						"Invite speakers",
 						"Setup registration",
-					},
+					}),
 				},
 			}
 			presenter := &mockPresenter{
@@ -32,6 +34,7 @@ func TestPlanningHandlerWithDecomposition(t *testing.T) {
 			}
 
 			handler := NewPlanningHandlerWithDecomposition(storage, detector, decomposer, presenter)
+			handler.SetInput(strings.NewReader("1,3\n"))
 			input := "organize conference"
 
 			// Act
@@ -129,6 +132,157 @@ func TestPlanningHandlerWithDecomposition(t *testing.T) {
 			}
 		})
 
+		t.Run("should create subtasks as edited before selection", func(t *testing.T) {
+			// Arrange
+			storage := &mockStorageWithDecomposition{
+				tasks: make(map[string]*task.Task),
+			}
+			detector := decomposition.NewComplexityDetector()
+			decomposer := &mockDecomposer{
+				result: &decomposition.DecompositionResult{
+					OriginalTask: "organize conference",
+					Subtasks: decomposition.NewSubtasks([]string{
+						"Book venue",
+						"Invite speakers",
+					}),
+				},
+			}
+
+			handler := NewPlanningHandlerWithDecomposition(storage, detector, decomposer,
+				decomposition.NewInteractivePresenter())
+			handler.SetInput(strings.NewReader(
+				"rename 1 Book the venue\nset 1 #venue !!\nmerge 5 1\nadd Order badges\n1,3\n"))
+
+			// Act
+			_, err := handler.Process(context.Background(), "organize conference")
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(storage.createdTasks) != 3 {
+				t.Fatalf("expected parent and 2 subtasks, got %d tasks", len(storage.createdTasks))
+			}
+			venue, badges := storage.createdTasks[1], storage.createdTasks[2]
+			if venue.Title != "Book the venue" || venue.Priority != 2 || !slices.Equal(venue.Tags, []string{"venue"}) {
+				t.Errorf("unexpected first subtask: %+v", venue)
+			}
+			if badges.Title != "Order badges" {
+				t.Errorf("expected added subtask, got %q", badges.Title)
+			}
+		})
+
+		t.Run("should break down complex subtasks into a tree", func(t *testing.T) {
+			// Arrange
+			storage := &mockStorageWithDecomposition{
+				tasks: make(map[string]*task.Task),
+			}
+			decomposer := &mockTreeDecomposer{subtasks: map[string][]string{
+				"organize conference": {"Plan the venue", "Invite speakers"},
+				"Plan the venue":      {"Compare quotes", "Sign contract"},
+			}}
+
+			handler := NewPlanningHandlerWithDecomposition(storage, decomposition.NewComplexityDetector(),
+				decomposer, decomposition.NewInteractivePresenter())
+			handler.SetInput(strings.NewReader("all\ny\nall\n"))
+
+			// Act
+			result, err := handler.Process(context.Background(), "organize conference")
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(storage.createdTasks) != 5 {
+				t.Fatalf("expected 5 tasks in the tree, got %d", len(storage.createdTasks))
+			}
+			if len(result.Subtasks) != 2 || len(result.Subtasks[0].Subtasks) != 2 {
+				t.Fatalf("expected venue subtask to have 2 subtasks, got %+v", result.Subtasks)
+			}
+			if title := result.Subtasks[0].Subtasks[1].Title; title != "Sign contract" {
+				t.Errorf("expected nested subtask %q, got %q", "Sign contract", title)
+			}
+		})
+
+		t.Run("should not break down beyond the depth limit", func(t *testing.T) {
+			// Arrange
+			storage := &mockStorageWithDecomposition{
+				tasks: make(map[string]*task.Task),
+			}
+			decomposer := &mockTreeDecomposer{subtasks: map[string][]string{
+				"organize conference": {"Plan the venue", "Invite speakers"},
+				"Plan the venue":      {"Compare quotes", "Sign contract"},
+			}}
+
+			handler := NewPlanningHandlerWithDecomposition(storage, decomposition.NewComplexityDetector(),
+				decomposer, decomposition.NewInteractivePresenter())
+			handler.SetMaxDepth(1)
+			handler.SetInput(strings.NewReader("all\ny\nall\n"))
+
+			// Act
+			_, err := handler.Process(context.Background(), "organize conference")
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(storage.createdTasks) != 3 {
+				t.Errorf("expected a flat plan of 3 tasks, got %d", len(storage.createdTasks))
+			}
+			if !slices.Equal(decomposer.calls, []string{"organize conference"}) {
+				t.Errorf("expected only the top task to be decomposed, got %v", decomposer.calls)
+			}
+		})
+
+		t.Run("should plan the tree without storing it", func(t *testing.T) {
+			// Arrange
+			storage := &mockStorageWithDecomposition{
+				tasks: make(map[string]*task.Task),
+			}
+			decomposer := &mockTreeDecomposer{subtasks: map[string][]string{
+				"organize conference": {"Book venue", "Invite speakers"},
+			}}
+
+			handler := NewPlanningHandlerWithDecomposition(storage, decomposition.NewComplexityDetector(),
+				decomposer, decomposition.NewInteractivePresenter())
+			handler.SetInput(strings.NewReader("all\n"))
+
+			// Act
+			result, err := handler.Plan(context.Background(), "organize conference")
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.Subtasks) != 2 {
+				t.Errorf("expected 2 subtasks, got %d", len(result.Subtasks))
+			}
+			if len(storage.createdTasks) != 0 {
+				t.Errorf("expected nothing to be stored, got %d tasks", len(storage.createdTasks))
+			}
+		})
+
+		t.Run("should create a single task when input runs out before a selection", func(t *testing.T) {
+			// Arrange
+			storage := &mockStorageWithDecomposition{
+				tasks: make(map[string]*task.Task),
+			}
+			decomposer := &mockTreeDecomposer{subtasks: map[string][]string{
+				"organize conference": {"Book venue", "Invite speakers"},
+			}}
+
+			handler := NewPlanningHandlerWithDecomposition(storage, decomposition.NewComplexityDetector(),
+				decomposer, decomposition.NewInteractivePresenter())
+			handler.SetInput(strings.NewReader("help"))
+
+			// Act
+			result, err := handler.Process(context.Background(), "organize conference")
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(result.Subtasks) != 0 || len(storage.createdTasks) != 1 {
+				t.Errorf("expected a single stored task, got %d subtasks and %d stored tasks",
+					len(result.Subtasks), len(storage.createdTasks))
+			}
+		})
+
 		t.Run("should handle user selecting 'none'", func(t *testing.T) {
 			// Arrange
 			storage := &mockStorageWithDecomposition{
@@ -138,10 +292,10 @@ func TestPlanningHandlerWithDecomposition(t *testing.T) {
 			decomposer := &mockDecomposer{
 				result: &decomposition.DecompositionResult{
 					OriginalTask: "organize conference",
-					Subtasks: []string{
+					Subtasks: decomposition.NewSubtasks([]string{
 						"Book venue",
 						"Invite speakers",
-					},
+					}),
 				},
 			}
 			presenter := &mockPresenter{
@@ -149,6 +303,7 @@ func TestPlanningHandlerWithDecomposition(t *testing.T) {
 			}
 
 			handler := NewPlanningHandlerWithDecomposition(storage, detector, decomposer, presenter)
+			handler.SetInput(strings.NewReader("none\n"))
 			input := "organize conference"
 
 			// Act
@@ -181,9 +336,12 @@ func (m *mockStorageWithDecomposition) Create(t *task.Task) error {
 	return nil
 }
 
-func (m *mockStorageWithDecomposition) CreateWithParent(t *task.Task, _ string) error {
-	m.tasks[t.ID] = t
-	m.createdTasks = append(m.createdTasks, t)
+func (m *mockStorageWithDecomposition) CreateTree(root *task.Task) error {
+	m.tasks[root.ID] = root
+	m.createdTasks = append(m.createdTasks, root)
+	for _, subtask := range root.Subtasks {
+		_ = m.CreateTree(subtask)
+	}
 	return nil
 }
 
@@ -198,6 +356,21 @@ func (m *mockDecomposer) Decompose(_ context.Context, _ string) (*decomposition.
 	return m.result, m.err
 }
 
+// mockTreeDecomposer returns different subtasks for each task it is asked about
+type mockTreeDecomposer struct {
+	subtasks map[string][]string
+	calls    []string
+}
+
+func (m *mockTreeDecomposer) Decompose(_ context.Context, input string) (*decomposition.DecompositionResult, error) {
+	m.calls = append(m.calls, input)
+	subtasks, ok := m.subtasks[input]
+	if !ok {
+		return nil, fmt.Errorf("no subtasks for %q", input)
+	}
+	return &decomposition.DecompositionResult{OriginalTask: input, Subtasks: decomposition.NewSubtasks(subtasks)}, nil
+}
+
 type mockPresenter struct {
 	called          bool
 	selectedIndices []int
@@ -208,6 +381,10 @@ func (m *mockPresenter) Present(_ *decomposition.DecompositionResult) string {
 	return "mocked presentation"
 }
 
+func (m *mockPresenter) PresentSubtasks(_ string, _ []decomposition.Subtask) string {
+	return "mocked subtasks"
+}
+
 func (m *mockPresenter) ParseSelection(_ string, _ int) ([]int, error) {
 	return m.selectedIndices, nil
 }
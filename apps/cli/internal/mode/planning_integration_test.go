@@ -79,8 +79,8 @@ func (s *storageAdapter) Create(t *task.Task) error {
 	return s.CreateTask(t, nil)
 }
 
-func (s *storageAdapter) CreateWithParent(t *task.Task, parentID string) error {
-	return s.Storage.CreateWithParent(t, parentID)
+func (s *storageAdapter) CreateTree(root *task.Task) error {
+	return s.CreateTaskTree(root)
 }
 
 // mockClaudeForIntegration is a simple mock for integration testing
@@ -3,6 +3,7 @@ package mode
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -14,10 +15,14 @@ import (
 	"github.com/tennashi/tabler/internal/task"
 )
 
+// defaultMaxDepth is how many levels of subtasks a plan may have by default
+const defaultMaxDepth = 2
+
 // StorageWithDecomposition extends storage interface for parent-child relationships
 type StorageWithDecomposition interface {
 	Create(t *task.Task) error
-	CreateWithParent(t *task.Task, parentID string) error
+	// CreateTree stores a task and its Subtasks, recursively, in one transaction
+	CreateTree(root *task.Task) error
 }
 
 // Decomposer interface for task decomposition
@@ -28,6 +33,7 @@ type Decomposer interface {
 // Presenter interface for interactive presentation
 type Presenter interface {
 	Present(result *decomposition.DecompositionResult) string
+	PresentSubtasks(task string, subtasks []decomposition.Subtask) string
 	ParseSelection(input string, total int) ([]int, error)
 }
 
@@ -38,6 +44,7 @@ type PlanningHandlerWithDecomposition struct {
 	decomposer Decomposer
 	presenter  Presenter
 	input      io.Reader // For testing, defaults to os.Stdin
+	maxDepth   int
 }
 
 // NewPlanningHandlerWithDecomposition creates a new handler with decomposition support
@@ -53,6 +60,7 @@ func NewPlanningHandlerWithDecomposition(
 		decomposer: decomposer,
 		presenter:  presenter,
 		input:      os.Stdin,
+		maxDepth:   defaultMaxDepth,
 	}
 }
 
@@ -61,14 +69,37 @@ func (h *PlanningHandlerWithDecomposition) SetInput(input io.Reader) {
 	h.input = input
 }
 
-// Process creates a task with optional decomposition
+// SetMaxDepth limits how many levels of subtasks a plan may have; 1 keeps plans flat
+func (h *PlanningHandlerWithDecomposition) SetMaxDepth(depth int) {
+	h.maxDepth = max(depth, 1)
+}
+
+// Process creates a task with optional decomposition and stores it with its subtasks
 func (h *PlanningHandlerWithDecomposition) Process(ctx context.Context, input string) (*task.Task, error) {
+	t, err := h.Plan(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(t.Subtasks) == 0 {
+		err = h.storage.Create(t)
+	} else {
+		err = h.storage.CreateTree(t)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Plan creates a task with optional decomposition without storing it
+func (h *PlanningHandlerWithDecomposition) Plan(ctx context.Context, input string) (*task.Task, error) {
 	// Check if task is complex
 	isComplex, reason := h.detector.DetectComplexity(input)
 
 	if !isComplex {
 		// Simple task - create directly
-		return h.createSimpleTask(input)
+		return newPlannedTask(decomposition.Subtask{Title: input}), nil
 	}
 
 	// Complex task - offer decomposition
@@ -81,78 +112,176 @@ func (h *PlanningHandlerWithDecomposition) Process(ctx context.Context, input st
 		// Fall back to simple task creation
 		fmt.Printf("⚠️  Could not decompose task: %v\n", err)
 		fmt.Println("Creating single task instead...")
-		return h.createSimpleTask(input)
+		return newPlannedTask(decomposition.Subtask{Title: input}), nil
 	}
 
-	// Present decomposition options
-	presentation := h.presenter.Present(result)
-	fmt.Print(presentation)
-
-	// Get user selection
 	reader := bufio.NewReader(h.input)
-	selection, _ := reader.ReadString('\n')
-	selection = strings.TrimSpace(selection)
-
-	// Parse selection
-	selectedIndices, err := h.presenter.ParseSelection(selection, len(result.Subtasks))
+	subtasks, err := h.selectSubtasks(ctx, reader, result)
 	if err != nil {
 		fmt.Printf("⚠️  Invalid selection: %v\n", err)
 		fmt.Println("Creating single task instead...")
-		return h.createSimpleTask(input)
+		return newPlannedTask(decomposition.Subtask{Title: input}), nil
 	}
 
-	// Create parent task
-	parentTask, err := h.createSimpleTask(input)
-	if err != nil {
-		return nil, err
+	// Build the whole plan before storing any of it
+	parentTask := newPlannedTask(decomposition.Subtask{Title: input})
+	parentTask.Subtasks = h.planSubtasks(ctx, reader, subtasks, 1)
+
+	if count := countSubtasks(parentTask); count > 0 {
+		fmt.Printf("✅ Creating %d subtasks...\n", count)
+	}
+	return parentTask, nil
+}
+
+// selectSubtasks presents a decomposition and lets the user edit it until they
+// pick the subtasks to create. Running out of input before a selection is an error.
+func (h *PlanningHandlerWithDecomposition) selectSubtasks(
+	ctx context.Context,
+	reader *bufio.Reader,
+	result *decomposition.DecompositionResult,
+) ([]decomposition.Subtask, error) {
+	fmt.Print(h.presenter.Present(result))
+
+	editor := decomposition.NewEditor(result)
+	if regenerator, ok := h.decomposer.(decomposition.Regenerator); ok {
+		editor.SetRegenerator(regenerator)
+	}
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && line == "" {
+			return nil, fmt.Errorf("no subtasks selected: %w", readErr)
+		}
+		line = strings.TrimSpace(line)
+
+		selectedIndices, err := h.presenter.ParseSelection(line, len(editor.Subtasks()))
+		if err == nil {
+			return pickSubtasks(editor.Subtasks(), selectedIndices), nil
+		}
+
+		if line == "help" {
+			fmt.Print(decomposition.EditHelp)
+			fmt.Print(h.presenter.PresentSubtasks(editor.Task(), editor.Subtasks()))
+			continue
+		}
+
+		editErr := editor.Apply(ctx, line)
+		if errors.Is(editErr, decomposition.ErrUnknownCommand) {
+			return nil, err
+		}
+		if editErr != nil {
+			fmt.Printf("⚠️  %v\n", editErr)
+		}
+		fmt.Print(h.presenter.PresentSubtasks(editor.Task(), editor.Subtasks()))
+	}
+}
+
+// planSubtasks turns selected subtasks at depth into tasks, offering to break
+// down the ones that still look complex while the depth limit allows
+func (h *PlanningHandlerWithDecomposition) planSubtasks(
+	ctx context.Context,
+	reader *bufio.Reader,
+	subtasks []decomposition.Subtask,
+	depth int,
+) []*task.Task {
+	planned := make([]*task.Task, 0, len(subtasks))
+	taskIDs := make(map[int]string, len(subtasks))
+	for i, subtask := range subtasks {
+		t := newPlannedTask(subtask)
+		t.Order = i + 1
+		planned = append(planned, t)
+		taskIDs[subtask.ID] = t.ID
 	}
 
-	// Create selected subtasks
-	if len(selectedIndices) > 0 {
-		fmt.Printf("✅ Creating %d subtasks...\n", len(selectedIndices))
-		for _, idx := range selectedIndices {
-			if idx > 0 && idx <= len(result.Subtasks) {
-				subtaskTitle := result.Subtasks[idx-1]
-				if err := h.createSubtask(subtaskTitle, parentTask.ID); err != nil {
-					fmt.Printf("⚠️  Failed to create subtask %q: %v\n", subtaskTitle, err)
-				}
+	// Dependencies on subtasks that were not selected are dropped
+	for i, subtask := range subtasks {
+		for _, dependency := range subtask.DependsOn {
+			if id, ok := taskIDs[dependency]; ok {
+				planned[i].DependsOn = append(planned[i].DependsOn, id)
 			}
 		}
 	}
 
-	return parentTask, nil
-}
+	for i, subtask := range subtasks {
+		t := planned[i]
 
-// createSimpleTask creates a single task without decomposition
-func (h *PlanningHandlerWithDecomposition) createSimpleTask(title string) (*task.Task, error) {
-	now := time.Now()
-	t := &task.Task{
-		ID:        uuid.New().String(),
-		Title:     title,
-		Priority:  0,
-		Completed: false,
-		CreatedAt: now,
-		UpdatedAt: now,
+		if depth >= h.maxDepth {
+			continue
+		}
+		isComplex, reason := h.detector.DetectComplexity(subtask.Title)
+		if !isComplex || !h.confirmBreakdown(reader, subtask.Title, reason) {
+			continue
+		}
+
+		result, err := h.decomposer.Decompose(ctx, subtask.Title)
+		if err != nil {
+			fmt.Printf("⚠️  Could not decompose %q: %v\n", subtask.Title, err)
+			continue
+		}
+		nested, err := h.selectSubtasks(ctx, reader, result)
+		if err != nil {
+			fmt.Printf("⚠️  Invalid selection: %v\n", err)
+			fmt.Printf("Keeping %q as a single subtask...\n", subtask.Title)
+			continue
+		}
+		t.Subtasks = h.planSubtasks(ctx, reader, nested, depth+1)
 	}
+	return planned
+}
 
-	if err := h.storage.Create(t); err != nil {
-		return nil, err
+// confirmBreakdown asks whether a complex subtask should be decomposed further
+func (h *PlanningHandlerWithDecomposition) confirmBreakdown(reader *bufio.Reader, title, reason string) bool {
+	fmt.Printf("📋 %q also looks complex (%s). Break it down further? [y/N]: ", title, reason)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// pickSubtasks returns the subtasks at the given one-based indices
+func pickSubtasks(subtasks []decomposition.Subtask, indices []int) []decomposition.Subtask {
+	picked := make([]decomposition.Subtask, 0, len(indices))
+	for _, idx := range indices {
+		if idx > 0 && idx <= len(subtasks) {
+			picked = append(picked, subtasks[idx-1])
+		}
 	}
+	return picked
+}
 
-	return t, nil
+// countSubtasks counts the subtasks of t at every level
+func countSubtasks(t *task.Task) int {
+	count := len(t.Subtasks)
+	for _, subtask := range t.Subtasks {
+		count += countSubtasks(subtask)
+	}
+	return count
 }
 
-// createSubtask creates a subtask with parent relationship
-func (h *PlanningHandlerWithDecomposition) createSubtask(title, parentID string) error {
+// newPlannedTask creates a task for a subtask of a plan
+func newPlannedTask(subtask decomposition.Subtask) *task.Task {
 	now := time.Now()
 	t := &task.Task{
 		ID:        uuid.New().String(),
-		Title:     title,
-		Priority:  0,
+		Title:     subtask.Title,
+		Priority:  subtask.Priority,
+		Tags:      subtask.Tags,
+		Effort:    subtask.Effort,
 		Completed: false,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+	if subtask.Deadline != nil {
+		t.Deadline = *subtask.Deadline
+	}
+	return t
+}
+
+// unsavedPlanHandler plans tasks for a caller that stores them itself
+type unsavedPlanHandler struct {
+	planner *PlanningHandlerWithDecomposition
+}
 
-	return h.storage.CreateWithParent(t, parentID)
+// Process implements ModeHandler
+func (h unsavedPlanHandler) Process(ctx context.Context, input string) (*task.Task, error) {
+	return h.planner.Plan(ctx, input)
 }
@@ -0,0 +1,76 @@
+package mode
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/clarification"
+	"github.com/tennashi/tabler/internal/claude"
+	"github.com/tennashi/tabler/internal/decomposition"
+	"github.com/tennashi/tabler/internal/task"
+)
+
+func TestReplayedAIFlows(t *testing.T) {
+	t.Run("talk mode clarifies vague task", func(t *testing.T) {
+		// Arrange
+		provider := claude.NewFixtureProvider(filepath.Join("testdata", "ai"), claude.NewClient())
+		dialogueManager := clarification.NewDialogueManager(
+			clarification.NewVaguenessDetector(),
+			clarification.NewQuestionGenerator(provider),
+			clarification.NewResponseProcessor(),
+		)
+		handler := NewTalkHandlerWithClarification(dialogueManager)
+		handler.SetInput(strings.NewReader("slides\nby Friday\n"))
+		handler.SetOutput(io.Discard)
+
+		// Act
+		result, err := handler.Process(context.Background(), "prepare the thing")
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Title != "Prepare slides by Friday" {
+			t.Errorf("expected title %q, got %q", "Prepare slides by Friday", result.Title)
+		}
+	})
+
+	t.Run("planning mode decomposes complex task", func(t *testing.T) {
+		// Arrange
+		provider := claude.NewFixtureProvider(filepath.Join("testdata", "ai"), claude.NewClient())
+		storage := &mockStorageWithDecomposition{tasks: make(map[string]*task.Task)}
+		handler := NewPlanningHandlerWithDecomposition(
+			storage,
+			decomposition.NewComplexityDetector(),
+			NewDecomposerAdapter(decomposition.NewTaskDecomposer(provider)),
+			NewPresenterAdapter(decomposition.NewInteractivePresenter()),
+		)
+		handler.SetInput(strings.NewReader("1,3\n"))
+
+		// Act
+		result, err := handler.Process(context.Background(), "organize team offsite")
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Title != "organize team offsite" {
+			t.Errorf("expected parent title %q, got %q", "organize team offsite", result.Title)
+		}
+		if len(storage.createdTasks) != 3 {
+			t.Fatalf("expected parent and 2 subtasks, got %d tasks", len(storage.createdTasks))
+		}
+		if storage.createdTasks[1].Title != "Pick a date and venue" {
+			t.Errorf("expected first subtask %q, got %q", "Pick a date and venue", storage.createdTasks[1].Title)
+		}
+		agenda := storage.createdTasks[2]
+		if len(agenda.DependsOn) != 1 || agenda.DependsOn[0] != storage.createdTasks[1].ID {
+			t.Errorf("expected agenda to depend on the venue subtask, got %v", agenda.DependsOn)
+		}
+		if agenda.Effort != 2*time.Hour || agenda.Order != 2 {
+			t.Errorf("expected effort 2h and order 2, got %v and %d", agenda.Effort, agenda.Order)
+		}
+	})
+}
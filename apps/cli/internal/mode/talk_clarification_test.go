@@ -2,6 +2,7 @@ package mode
 
 import (
 	"context"
+	"errors"
 	"io"
 	"strings"
 	"testing"
@@ -112,6 +113,261 @@ func TestTalkHandlerWithClarification(t *testing.T) {
 			t.Errorf("expected original title when skipped, got %q", task.Title)
 		}
 	})
+
+	t.Run("should carry shortcuts and clarified details into the task", func(t *testing.T) {
+		// Arrange
+		claude := &mockClaudeForClarification{
+			responses: []string{
+				"What specific thing do you need to work on?",
+				"Who is it for?",
+				"When do you need to complete it?",
+			},
+		}
+
+		detector := clarification.NewVaguenessDetector()
+		questionGen := clarification.NewQuestionGenerator(claude)
+		processor := clarification.NewResponseProcessor()
+		dialogueManager := clarification.NewDialogueManager(detector, questionGen, processor)
+
+		handler := NewTalkHandlerWithClarification(dialogueManager)
+		handler.SetInput(strings.NewReader("slides\nthe team\n2030-05-17\n"))
+		handler.SetOutput(io.Discard)
+
+		// Act
+		task, err := handler.Process(context.Background(), "work on the thing #work !!")
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(task.Tags) != 1 || task.Tags[0] != "work" {
+			t.Errorf("expected tags [work], got %v", task.Tags)
+		}
+		if task.Priority != 2 {
+			t.Errorf("expected priority 2, got %d", task.Priority)
+		}
+		if task.Deadline.Format("2006-01-02") != "2030-05-17" {
+			t.Errorf("expected deadline 2030-05-17, got %v", task.Deadline)
+		}
+		if !strings.HasPrefix(task.Notes, "Audience: the team\nTranscript:\n") {
+			t.Errorf("expected audience and transcript in notes, got %q", task.Notes)
+		}
+		if !strings.Contains(task.Notes, "Q: Who is it for?\nA: the team") {
+			t.Errorf("expected answered questions in transcript, got %q", task.Notes)
+		}
+		if strings.Contains(task.Title, "#work") {
+			t.Errorf("expected shortcuts removed from title, got %q", task.Title)
+		}
+	})
+
+	t.Run("should store the structured task from the AI response processor", func(t *testing.T) {
+		// Arrange
+		claude := &mockClaudeForClarification{
+			responses: []string{
+				"What specific thing do you need to work on?",
+				"COMPLETE",
+				`{"title": "Draft launch slides", "deadline": "2030-05-17", "tags": ["launch"], "priority": "medium", "notes": "Audience: sales"}`,
+			},
+		}
+
+		detector := clarification.NewVaguenessDetector()
+		questionGen := clarification.NewQuestionGenerator(claude)
+		processor := clarification.NewAIResponseProcessor(claude)
+		dialogueManager := clarification.NewDialogueManager(detector, questionGen, processor)
+
+		handler := NewTalkHandlerWithClarification(dialogueManager)
+		handler.SetInput(strings.NewReader("launch slides\n"))
+		handler.SetOutput(io.Discard)
+
+		// Act
+		task, err := handler.Process(context.Background(), "work on the thing #work")
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if task.Title != "Draft launch slides" {
+			t.Errorf("expected title %q, got %q", "Draft launch slides", task.Title)
+		}
+		if strings.Join(task.Tags, ",") != "work,launch" {
+			t.Errorf("expected tags [work launch], got %v", task.Tags)
+		}
+		if task.Priority != 2 {
+			t.Errorf("expected priority 2, got %d", task.Priority)
+		}
+		if task.Deadline.Format("2006-01-02") != "2030-05-17" {
+			t.Errorf("expected deadline 2030-05-17, got %v", task.Deadline)
+		}
+		if !strings.HasPrefix(task.Notes, "Audience: sales\n") {
+			t.Errorf("expected model notes first, got %q", task.Notes)
+		}
+	})
+
+	t.Run("should save an interrupted dialogue and resume it", func(t *testing.T) {
+		// Arrange
+		claude := &mockClaudeForClarification{
+			responses: []string{
+				"What specific thing do you need to work on?",
+				"When do you need to complete it?",
+				"COMPLETE",
+			},
+		}
+		dialogueManager := clarification.NewDialogueManager(
+			clarification.NewVaguenessDetector(),
+			clarification.NewQuestionGenerator(claude),
+			clarification.NewResponseProcessor(),
+		)
+		store := &fakeDialogueStore{saved: make(map[string]*clarification.DialogueSession)}
+
+		interrupted := NewTalkHandlerWithClarification(dialogueManager)
+		interrupted.SetDialogueStore(store)
+		interrupted.SetOutput(io.Discard)
+		// The first answer arrives, then input stops as if the process were killed
+		interrupted.SetInput(&interruptingReader{data: "presentation\n"})
+
+		resumed := NewTalkHandlerWithClarification(dialogueManager)
+		resumed.SetDialogueStore(store)
+		resumed.SetOutput(io.Discard)
+		resumed.SetInput(strings.NewReader("2030-05-17\n"))
+
+		// Act
+		_, interruptErr := interrupted.Process(context.Background(), "work on the thing #work")
+		if interruptErr == nil {
+			t.Fatal("expected the interrupted dialogue to return an error")
+		}
+		if len(store.saved) != 1 {
+			t.Fatalf("expected 1 saved dialogue, got %d", len(store.saved))
+		}
+		var saved *clarification.DialogueSession
+		for _, session := range store.saved {
+			saved = session
+		}
+		task, err := resumed.Resume(context.Background(), saved)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(saved.Answered()) != 2 {
+			t.Errorf("expected both answers in the resumed session, got %d", len(saved.Answered()))
+		}
+		if task.Deadline.Format("2006-01-02") != "2030-05-17" {
+			t.Errorf("expected deadline from the resumed answer, got %v", task.Deadline)
+		}
+		if len(task.Tags) != 1 || task.Tags[0] != "work" {
+			t.Errorf("expected shortcut tags from the original input, got %v", task.Tags)
+		}
+		if !strings.Contains(task.Notes, "A: presentation") || !strings.Contains(task.Notes, "A: 2030-05-17") {
+			t.Errorf("expected full transcript in notes, got %q", task.Notes)
+		}
+		if len(store.saved) != 0 {
+			t.Errorf("expected finished dialogue to be deleted, %d left", len(store.saved))
+		}
+	})
+
+	t.Run("should answer non-interactively by position and topic", func(t *testing.T) {
+		// Arrange
+		claude := &mockClaudeForClarification{
+			responses: []string{
+				"What specific thing do you need to work on?",
+				"When do you need to complete it?",
+				"COMPLETE",
+			},
+		}
+		dialogueManager := clarification.NewDialogueManager(
+			clarification.NewVaguenessDetector(),
+			clarification.NewQuestionGenerator(claude),
+			clarification.NewResponseProcessor(),
+		)
+		handler := NewTalkHandlerWithClarification(dialogueManager)
+		handler.SetOutput(io.Discard)
+		handler.SetInput(&interruptingReader{done: true}) // stdin must not be read
+		handler.SetAnswers(map[string]string{"q1": "presentation", "deadline": "2030-05-17"})
+
+		// Act
+		task, err := handler.Process(context.Background(), "work on the thing")
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(task.Title, "presentation") {
+			t.Errorf("expected positional answer in title, got %q", task.Title)
+		}
+		if task.Deadline.Format("2006-01-02") != "2030-05-17" {
+			t.Errorf("expected deadline from topic answer, got %v", task.Deadline)
+		}
+	})
+
+	t.Run("should hand back the first unanswered question", func(t *testing.T) {
+		// Arrange
+		claude := &mockClaudeForClarification{
+			responses: []string{
+				"What specific thing do you need to work on?",
+				"When do you need to complete it?",
+			},
+		}
+		dialogueManager := clarification.NewDialogueManager(
+			clarification.NewVaguenessDetector(),
+			clarification.NewQuestionGenerator(claude),
+			clarification.NewResponseProcessor(),
+		)
+		store := &fakeDialogueStore{saved: make(map[string]*clarification.DialogueSession)}
+		handler := NewTalkHandlerWithClarification(dialogueManager)
+		handler.SetDialogueStore(store)
+		handler.SetOutput(io.Discard)
+		handler.SetAnswers(map[string]string{"what": "presentation"})
+
+		// Act
+		_, err := handler.Process(context.Background(), "work on the thing")
+
+		// Assert
+		var pending *PendingQuestionError
+		if !errors.As(err, &pending) {
+			t.Fatalf("expected PendingQuestionError, got %v", err)
+		}
+		if pending.Key != "q2" || pending.Topic != "deadline" {
+			t.Errorf("expected q2/deadline, got %s/%s", pending.Key, pending.Topic)
+		}
+		if pending.Question != "When do you need to complete it?" {
+			t.Errorf("unexpected question %q", pending.Question)
+		}
+		if len(pending.Answered) != 1 || pending.Answered[0].Answer != "presentation" {
+			t.Errorf("expected the answered question, got %+v", pending.Answered)
+		}
+		if _, ok := store.saved[pending.SessionID]; !ok {
+			t.Error("expected the pending dialogue to be saved for resuming")
+		}
+	})
+}
+
+// fakeDialogueStore keeps saved dialogues in memory
+type fakeDialogueStore struct {
+	saved map[string]*clarification.DialogueSession
+}
+
+func (s *fakeDialogueStore) SaveDialogue(session *clarification.DialogueSession) error {
+	// Copy so later changes to the live session are not visible, as with real storage
+	copied := *session
+	copied.History = append([]clarification.Exchange{}, session.History...)
+	s.saved[session.ID] = &copied
+	return nil
+}
+
+func (s *fakeDialogueStore) DeleteDialogue(id string) error {
+	delete(s.saved, id)
+	return nil
+}
+
+// interruptingReader returns its data and then fails like a killed terminal
+type interruptingReader struct {
+	data string
+	done bool
+}
+
+func (r *interruptingReader) Read(p []byte) (int, error) {
+	if r.done {
+		return 0, errors.New("interrupted")
+	}
+	r.done = true
+	return copy(p, r.data), nil
 }
 
 // mockClaudeForClarification provides canned responses for testing
@@ -3,20 +3,46 @@ package mode
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/tennashi/tabler/internal/clarification"
+	"github.com/tennashi/tabler/internal/parser"
 	"github.com/tennashi/tabler/internal/task"
 )
 
+// DialogueStore keeps unfinished dialogues so they can be resumed later
+type DialogueStore interface {
+	SaveDialogue(session *clarification.DialogueSession) error
+	DeleteDialogue(id string) error
+}
+
+// PendingQuestionError stops a non-interactive dialogue at a question no answer was given for
+type PendingQuestionError struct {
+	SessionID string // empty when dialogues are not saved
+	Input     string
+	Key       string // positional answer key, e.g. "q2"
+	Topic     string // topic answer key, see clarification.QuestionKey
+	Question  string
+	Answered  []clarification.Exchange
+}
+
+func (e *PendingQuestionError) Error() string {
+	return fmt.Sprintf("clarification needs an answer for %s: %s", e.Key, e.Question)
+}
+
 // TalkHandlerWithClarification implements talk mode with dialogue-based clarification
 type TalkHandlerWithClarification struct {
 	dialogueManager *clarification.DialogueManager
+	store           DialogueStore
+	answers         map[string]string
 	input           io.Reader
 	output          io.Writer
 }
@@ -30,6 +56,19 @@ func NewTalkHandlerWithClarification(dialogueManager *clarification.DialogueMana
 	}
 }
 
+// SetDialogueStore saves each dialogue step so an interrupted dialogue can be resumed
+func (h *TalkHandlerWithClarification) SetDialogueStore(store DialogueStore) {
+	h.store = store
+}
+
+// SetAnswers makes the dialogue non-interactive. Questions are answered from
+// answers, keyed by position ("q1", "q2", ...) or by topic (see
+// clarification.QuestionKey), and the first question without an answer stops
+// the dialogue with a *PendingQuestionError. A nil map restores reading input.
+func (h *TalkHandlerWithClarification) SetAnswers(answers map[string]string) {
+	h.answers = answers
+}
+
 // SetInput sets the input reader (for testing)
 func (h *TalkHandlerWithClarification) SetInput(input io.Reader) {
 	h.input = input
@@ -42,34 +81,87 @@ func (h *TalkHandlerWithClarification) SetOutput(output io.Writer) {
 
 // Process creates a task through conversational clarification
 func (h *TalkHandlerWithClarification) Process(ctx context.Context, input string) (*task.Task, error) {
+	parsed := parseShortcuts(input)
+
 	// Start dialogue if needed
-	session, err := h.dialogueManager.StartDialogue(ctx, input)
+	session, err := h.dialogueManager.StartDialogue(ctx, parsed.Title)
 	if err != nil {
 		return nil, fmt.Errorf("failed to start dialogue: %w", err)
 	}
 
 	// If no dialogue needed (clear input), create task directly
 	if session == nil {
-		return h.createTask(input), nil
+		return h.createTask(parsed.Title, parsed, nil, nil), nil
 	}
+	session.Input = input
 
 	// Show initial greeting
 	_, _ = fmt.Fprintln(h.output, "🤔 I'd like to help clarify this task.")
+	if h.store != nil {
+		_, _ = fmt.Fprintln(h.output, "(If you stop now, continue later with 'tabler talk resume'.)")
+	}
+
+	return h.converse(ctx, session, parsed)
+}
+
+// Resume continues a saved dialogue from its unanswered question
+func (h *TalkHandlerWithClarification) Resume(ctx context.Context, session *clarification.DialogueSession) (*task.Task, error) {
+	_, _ = fmt.Fprintf(h.output, "🔁 Resuming: %s\n", session.Input)
+	for _, exchange := range session.Answered() {
+		_, _ = fmt.Fprintf(h.output, "%s\n> %s\n\n", exchange.Question, exchange.Answer)
+	}
+
+	return h.converse(ctx, session, parseShortcuts(session.Input))
+}
+
+// parseShortcuts keeps shortcuts out of the dialogue so they can be applied to the final task
+func parseShortcuts(input string) *parser.ParseResult {
+	parsed := parser.Parse(input)
+	if strings.TrimSpace(parsed.Title) == "" {
+		parsed.Title = input
+	}
+	return parsed
+}
+
+// converse asks the session's questions until it completes and builds the task
+func (h *TalkHandlerWithClarification) converse(ctx context.Context, session *clarification.DialogueSession, parsed *parser.ParseResult) (*task.Task, error) {
+	title := parsed.Title
 
 	// Conduct dialogue
 	reader := bufio.NewReader(h.input)
+	used := make(map[string]bool)
 
 	for !session.IsComplete {
+		// Save before asking so an interrupted dialogue resumes at this question
+		h.save(session)
+
 		// Show current question
 		_, _ = fmt.Fprintln(h.output, session.CurrentQuestion)
 		_, _ = fmt.Fprint(h.output, "> ")
 
-		// Get user response
-		response, err := reader.ReadString('\n')
-		if err != nil {
-			// If error reading, skip dialogue
-			session.SkipRequested = true
-			break
+		var response string
+		if h.answers != nil {
+			// Non-interactive: answer from the given answers or hand the question back
+			answer, ok := h.answerFor(session, used)
+			if !ok {
+				_, _ = fmt.Fprintln(h.output)
+				return nil, h.pendingQuestion(session)
+			}
+			_, _ = fmt.Fprintln(h.output, answer)
+			response = answer
+		} else {
+			// Get user response
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				// A saved dialogue that was cut off mid-question stays resumable
+				if h.store != nil && session.ID != "" && !errors.Is(err, io.EOF) {
+					return nil, fmt.Errorf("dialogue %s interrupted: %w", session.ID, err)
+				}
+				// Otherwise (e.g. end of input) skip dialogue
+				session.SkipRequested = true
+				break
+			}
+			response = line
 		}
 		response = strings.TrimSpace(response)
 
@@ -84,17 +176,30 @@ func (h *TalkHandlerWithClarification) Process(ctx context.Context, input string
 		}
 	}
 
+	// The dialogue is over either way, so there is nothing left to resume
+	h.forget(session)
+
+	// Skipped dialogues keep the original input and nothing extracted
+	if session.SkipRequested {
+		return h.createTask(title, parsed, nil, session.Answered()), nil
+	}
+
 	// Get final task
 	finalTaskTitle := h.dialogueManager.GetFinalTask(session)
+	created := h.createTask(finalTaskTitle, parsed, session.ExtractedInfo, session.Answered())
 
 	// Show result
-	if !session.SkipRequested && finalTaskTitle != input {
+	if finalTaskTitle != title {
 		_, _ = fmt.Fprintf(h.output, "\n✅ Got it! Creating task: \"%s\"\n", finalTaskTitle)
 
 		// Show extracted details if any
 		if len(session.ExtractedInfo) > 0 {
 			if deadline, ok := session.ExtractedInfo["deadline"]; ok {
-				_, _ = fmt.Fprintf(h.output, "📅 Deadline: %s\n", deadline)
+				if created.Deadline.IsZero() {
+					_, _ = fmt.Fprintf(h.output, "📅 Deadline: %s\n", deadline)
+				} else {
+					_, _ = fmt.Fprintf(h.output, "📅 Deadline: %s (%s)\n", deadline, created.Deadline.Format("2006-01-02"))
+				}
 			}
 			if audience, ok := session.ExtractedInfo["audience"]; ok {
 				_, _ = fmt.Fprintf(h.output, "👥 For: %s\n", audience)
@@ -102,21 +207,156 @@ func (h *TalkHandlerWithClarification) Process(ctx context.Context, input string
 			if materials, ok := session.ExtractedInfo["materials"]; ok {
 				_, _ = fmt.Fprintf(h.output, "📋 Materials: %s\n", materials)
 			}
+			if notes, ok := session.ExtractedInfo["notes"]; ok {
+				_, _ = fmt.Fprintf(h.output, "📝 Notes: %s\n", notes)
+			}
+		}
+	}
+
+	return created, nil
+}
+
+// answerFor looks up the answer to the current question by position, then by topic.
+// Each answer is used once so a repeated topic is not answered twice with the same text.
+func (h *TalkHandlerWithClarification) answerFor(session *clarification.DialogueSession, used map[string]bool) (string, bool) {
+	keys := []string{positionKey(session)}
+	if topic := clarification.QuestionKey(session.CurrentQuestion); topic != "" {
+		keys = append(keys, topic)
+	}
+
+	for _, key := range keys {
+		if answer, ok := h.answers[key]; ok && !used[key] {
+			used[key] = true
+			return answer, true
 		}
 	}
+	return "", false
+}
+
+// pendingQuestion describes the unanswered current question
+func (h *TalkHandlerWithClarification) pendingQuestion(session *clarification.DialogueSession) *PendingQuestionError {
+	return &PendingQuestionError{
+		SessionID: session.ID,
+		Input:     session.Input,
+		Key:       positionKey(session),
+		Topic:     clarification.QuestionKey(session.CurrentQuestion),
+		Question:  session.CurrentQuestion,
+		Answered:  session.Answered(),
+	}
+}
+
+// positionKey is the answer key of the current question: "q1" for the first question
+func positionKey(session *clarification.DialogueSession) string {
+	return fmt.Sprintf("q%d", len(session.History))
+}
+
+// save persists the session, assigning it an ID on first save
+func (h *TalkHandlerWithClarification) save(session *clarification.DialogueSession) {
+	if h.store == nil {
+		return
+	}
+
+	now := time.Now()
+	if session.ID == "" {
+		session.ID = uuid.New().String()
+		session.CreatedAt = now
+	}
+	session.UpdatedAt = now
 
-	return h.createTask(finalTaskTitle), nil
+	// Failing to save only costs the ability to resume
+	if err := h.store.SaveDialogue(session); err != nil {
+		_, _ = fmt.Fprintf(h.output, "⚠️  Could not save this conversation: %v\n", err)
+	}
 }
 
-// createTask creates a new task with the given title
-func (h *TalkHandlerWithClarification) createTask(title string) *task.Task {
+// forget removes a finished session from the store
+func (h *TalkHandlerWithClarification) forget(session *clarification.DialogueSession) {
+	if h.store == nil || session.ID == "" {
+		return
+	}
+	_ = h.store.DeleteDialogue(session.ID)
+}
+
+// noteFields lists the clarified details kept as task notes, in display order
+var noteFields = []struct {
+	key   string
+	label string
+}{
+	{"audience", "Audience"},
+	{"materials", "Materials"},
+	{"project", "Project"},
+}
+
+// createTask creates a new task with the given title, the shortcuts parsed from
+// the original input, any information extracted during clarification and the
+// transcript of answered questions
+func (h *TalkHandlerWithClarification) createTask(
+	title string,
+	parsed *parser.ParseResult,
+	info map[string]string,
+	transcript []clarification.Exchange,
+) *task.Task {
 	now := time.Now()
-	return &task.Task{
+	created := &task.Task{
 		ID:        uuid.New().String(),
 		Title:     title,
-		Priority:  0,
+		Priority:  parsed.Priority,
+		Tags:      mergeTags(parsed.Tags, info["tags"]),
 		Completed: false,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
+
+	// Explicit !priority shortcuts win over the clarified priority
+	if created.Priority == 0 {
+		if priority, err := strconv.Atoi(info["priority"]); err == nil {
+			created.Priority = priority
+		}
+	}
+
+	var notes []string
+
+	// An explicit @deadline wins over one mentioned in the dialogue
+	if parsed.Deadline != nil {
+		created.Deadline = *parsed.Deadline
+	} else if answer, ok := info["deadline"]; ok {
+		if deadline, ok := parser.ParseDeadline(answer); ok {
+			created.Deadline = *deadline
+		} else {
+			// Keep deadlines we cannot place on the calendar so they are not lost
+			notes = append(notes, "Deadline: "+answer)
+		}
+	}
+
+	for _, field := range noteFields {
+		if value, ok := info[field.key]; ok && value != "" {
+			notes = append(notes, field.label+": "+value)
+		}
+	}
+	if value, ok := info["notes"]; ok && value != "" {
+		notes = append(notes, value)
+	}
+
+	// Keep the whole conversation for later reference
+	if len(transcript) > 0 {
+		notes = append(notes, "Transcript:")
+		for _, exchange := range transcript {
+			notes = append(notes, "Q: "+exchange.Question, "A: "+exchange.Answer)
+		}
+	}
+	created.Notes = strings.Join(notes, "\n")
+
+	return created
+}
+
+// mergeTags adds comma-separated clarified tags to the shortcut tags, skipping duplicates
+func mergeTags(tags []string, clarified string) []string {
+	merged := append([]string{}, tags...)
+	for _, tag := range strings.Split(clarified, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(merged, tag) {
+			merged = append(merged, tag)
+		}
+	}
+	return merged
 }
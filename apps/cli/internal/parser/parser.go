@@ -79,6 +79,19 @@ func extractDeadline(part string) (*time.Time, bool) {
 	return parseDeadlineString(dateStr)
 }
 
+// ParseDeadline converts a free-form deadline answer such as "Friday",
+// "next friday" or "2024-03-01" into a date. Words around the date, like
+// "by" or "this", are ignored.
+func ParseDeadline(text string) (*time.Time, bool) {
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		word = strings.Trim(word, ".,!?")
+		if deadline, ok := parseDeadlineString(word); ok {
+			return deadline, true
+		}
+	}
+	return nil, false
+}
+
 var weekdayMap = map[string]time.Weekday{
 	"mon":       time.Monday,
 	"monday":    time.Monday,
@@ -191,6 +191,54 @@ func TestParser(t *testing.T) {
 	}
 }
 
+func TestParseDeadline(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected func() time.Time
+	}{
+		{
+			name:     "parse a bare weekday",
+			input:    "Friday",
+			expected: func() time.Time { return getNextWeekday(time.Friday) },
+		},
+		{
+			name:     "parse a weekday inside a phrase",
+			input:    "by next friday.",
+			expected: func() time.Time { return getNextWeekday(time.Friday) },
+		},
+		{
+			name:     "parse a specific date",
+			input:    "before 2024-03-01",
+			expected: func() time.Time { return time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC) },
+		},
+		{
+			name:     "reject text without a date",
+			input:    "when I have time",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			deadline, ok := ParseDeadline(tt.input)
+
+			// Assert
+			if tt.expected == nil {
+				if ok {
+					t.Errorf("expected no deadline, got %v", deadline)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("expected deadline for %q", tt.input)
+			}
+			assertDateEquals(t, tt.expected(), *deadline, tt.input)
+		})
+	}
+}
+
 // Helper functions for tests
 func getNextWeekday(weekday time.Weekday) time.Time {
 	now := time.Now()
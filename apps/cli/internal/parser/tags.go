@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// TagSeparator splits a hierarchical tag such as "work/clienta" into levels
+const TagSeparator = "/"
+
+// TagRules controls how tags are normalized before they are stored or matched
+type TagRules struct {
+	// Lowercase folds tags to lower case so #Work and #work are the same tag
+	Lowercase bool
+	// StripPunctuation trims punctuation around each level, e.g. "#work," becomes "work"
+	StripPunctuation bool
+	// Aliases map a tag, or the top of a hierarchy, to the tag used instead
+	Aliases map[string]string
+}
+
+// DefaultTagRules returns the rules used when none are configured
+func DefaultTagRules() TagRules {
+	return TagRules{
+		Lowercase:        true,
+		StripPunctuation: true,
+	}
+}
+
+// NormalizeTag returns the stored form of a tag, or "" if nothing is left of it.
+// Empty levels are dropped, so "work//clientA/" becomes "work/clienta".
+func NormalizeTag(tag string, rules TagRules) string {
+	normalized := normalizeLevels(tag, rules)
+	if normalized == "" || len(rules.Aliases) == 0 {
+		return normalized
+	}
+
+	// The longest aliased ancestor wins: with work -> job, "work/a" becomes "job/a".
+	// Aliases that normalize alike are tried in key order, so the first one wins.
+	aliases := slices.Sorted(maps.Keys(rules.Aliases))
+	for prefix := normalized; prefix != ""; prefix = TagParent(prefix) {
+		for _, alias := range aliases {
+			if normalizeLevels(alias, rules) != prefix {
+				continue
+			}
+			target := normalizeLevels(rules.Aliases[alias], rules)
+			if target == "" {
+				return normalized
+			}
+			return target + strings.TrimPrefix(normalized, prefix)
+		}
+	}
+	return normalized
+}
+
+// NormalizeTags normalizes tags, dropping empty ones and duplicates
+func NormalizeTags(tags []string, rules TagRules) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag, rules)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// TagParent returns the tag one level up, or "" for a top-level tag
+func TagParent(tag string) string {
+	i := strings.LastIndex(tag, TagSeparator)
+	if i < 0 {
+		return ""
+	}
+	return tag[:i]
+}
+
+// TagIncludes reports whether tag is parent itself or one of its descendants
+func TagIncludes(parent, tag string) bool {
+	return tag == parent || strings.HasPrefix(tag, parent+TagSeparator)
+}
+
+// normalizeLevels applies the case and punctuation rules to each level of a tag
+func normalizeLevels(tag string, rules TagRules) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), tagPrefix)
+	if rules.Lowercase {
+		tag = strings.ToLower(tag)
+	}
+
+	var levels []string
+	for _, level := range strings.Split(tag, TagSeparator) {
+		level = strings.TrimSpace(level)
+		if rules.StripPunctuation {
+			level = strings.TrimFunc(level, unicode.IsPunct)
+		}
+		if level != "" {
+			levels = append(levels, level)
+		}
+	}
+	return strings.Join(levels, TagSeparator)
+}
@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      string
+		rules    TagRules
+		expected string
+	}{
+		{name: "lower-case the tag", tag: "Work", rules: DefaultTagRules(), expected: "work"},
+		{name: "strip trailing punctuation", tag: "work,", rules: DefaultTagRules(), expected: "work"},
+		{name: "strip repeated hash signs", tag: "##work", rules: DefaultTagRules(), expected: "work"},
+		{name: "keep punctuation inside the tag", tag: "client.a", rules: DefaultTagRules(), expected: "client.a"},
+		{name: "keep symbols", tag: "c++", rules: DefaultTagRules(), expected: "c++"},
+		{name: "normalize each level", tag: "Work/ClientA.", rules: DefaultTagRules(), expected: "work/clienta"},
+		{name: "drop empty levels", tag: "/work//clientA/", rules: DefaultTagRules(), expected: "work/clienta"},
+		{name: "keep non-latin tags", tag: "仕事、", rules: DefaultTagRules(), expected: "仕事"},
+		{name: "return empty for punctuation only", tag: ",.", rules: DefaultTagRules(), expected: ""},
+		{name: "keep case when disabled", tag: "Work,", rules: TagRules{StripPunctuation: true}, expected: "Work"},
+		{name: "keep punctuation when disabled", tag: "Work,", rules: TagRules{Lowercase: true}, expected: "work,"},
+		{
+			name:     "replace an alias",
+			tag:      "#Job",
+			rules:    TagRules{Lowercase: true, Aliases: map[string]string{"job": "work"}},
+			expected: "work",
+		},
+		{
+			name:     "replace an aliased ancestor",
+			tag:      "job/clientA",
+			rules:    TagRules{Lowercase: true, Aliases: map[string]string{"Job": "Work/Office"}},
+			expected: "work/office/clienta",
+		},
+		{
+			name:     "use the first of aliases that normalize alike",
+			tag:      "work",
+			rules:    TagRules{Lowercase: true, Aliases: map[string]string{"work": "career", "Work": "job", "WORK": "office"}},
+			expected: "office",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			got := NormalizeTag(tt.tag, tt.rules)
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	t.Run("should drop empty tags and duplicates", func(t *testing.T) {
+		// Act
+		got := NormalizeTags([]string{"Work", "work,", ",", "home"}, DefaultTagRules())
+
+		// Assert
+		expected := []string{"work", "home"}
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+}
+
+func TestTagIncludes(t *testing.T) {
+	tests := []struct {
+		parent, tag string
+		expected    bool
+	}{
+		{"work", "work", true},
+		{"work", "work/clienta", true},
+		{"work", "work/clienta/design", true},
+		{"work/clienta", "work", false},
+		{"work", "workshop", false},
+		{"work", "home", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.parent+" includes "+tt.tag, func(t *testing.T) {
+			// Act
+			got := TagIncludes(tt.parent, tt.tag)
+
+			// Assert
+			if got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package prompts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Prompt names
+const (
+	Metadata              = "metadata"
+	ClarificationQuestion = "clarification_question"
+	ClarificationResult   = "clarification_result"
+	Decomposition         = "decomposition"
+	SubtaskRegeneration   = "subtask_regeneration"
+	Enrichment            = "enrichment"
+)
+
+// templateExt is the file extension for prompt templates
+const templateExt = ".tmpl"
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// defaultVersions tracks the version of each embedded template.
+// Bump a version whenever its template text changes.
+var defaultVersions = map[string]string{
+	Metadata:              "v1",
+	ClarificationQuestion: "v1",
+	ClarificationResult:   "v1",
+	Decomposition:         "v2",
+	SubtaskRegeneration:   "v1",
+	Enrichment:            "v1",
+}
+
+// templateFuncs are available to every prompt template
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// Rendered is a prompt ready to send, tagged with the template version that produced it
+type Rendered struct {
+	ID   string
+	Text string
+}
+
+// Info describes where a prompt template comes from
+type Info struct {
+	Name string
+	ID   string
+	Path string // empty for embedded defaults
+}
+
+// Redactor masks sensitive text before it is rendered into a prompt
+type Redactor interface {
+	Redact(text string) string
+}
+
+// Library renders prompt templates, preferring user overrides over embedded defaults
+type Library struct {
+	overrideDir string
+	redactor    Redactor
+}
+
+// NewLibrary creates a library that looks for overrides in overrideDir
+func NewLibrary(overrideDir string) *Library {
+	return &Library{overrideDir: overrideDir}
+}
+
+// Default returns a library that only uses the embedded templates
+func Default() *Library {
+	return &Library{}
+}
+
+// SetRedactor masks every string in the data of rendered prompts; the template
+// text itself is sent as written
+func (l *Library) SetRedactor(redactor Redactor) {
+	l.redactor = redactor
+}
+
+// Render executes the named template with data
+func (l *Library) Render(name string, data interface{}) (*Rendered, error) {
+	id, text, err := l.load(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template %s: %w", id, err)
+	}
+
+	if l.redactor != nil && data != nil {
+		data = redactStrings(reflect.ValueOf(data), l.redactor).Interface()
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("failed to render prompt %s: %w", id, err)
+	}
+
+	return &Rendered{ID: id, Text: buf.String()}, nil
+}
+
+// ID returns the versioned ID Render would tag the named prompt with
+func (l *Library) ID(name string) (string, error) {
+	id, _, err := l.load(name)
+	return id, err
+}
+
+// List describes every known prompt and whether it is overridden
+func (l *Library) List() ([]Info, error) {
+	names := make([]string, 0, len(defaultVersions))
+	for name := range defaultVersions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	infos := make([]Info, 0, len(names))
+	for _, name := range names {
+		id, _, err := l.load(name)
+		if err != nil {
+			return nil, err
+		}
+
+		info := Info{Name: name, ID: id}
+		if path, ok := l.overridePath(name); ok {
+			info.Path = path
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// DefaultText returns the embedded template text for a prompt
+func DefaultText(name string) (string, error) {
+	if _, ok := defaultVersions[name]; !ok {
+		return "", fmt.Errorf("unknown prompt: %s", name)
+	}
+
+	data, err := defaultTemplates.ReadFile("templates/" + name + templateExt)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// load returns the versioned ID and template text for a prompt
+func (l *Library) load(name string) (string, string, error) {
+	version, ok := defaultVersions[name]
+	if !ok {
+		return "", "", fmt.Errorf("unknown prompt: %s", name)
+	}
+
+	if path, ok := l.overridePath(name); ok {
+		data, err := os.ReadFile(path) // #nosec G304 - override dir is user-controlled
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read prompt override: %w", err)
+		}
+
+		// Overrides are versioned by content so cached results can tell them apart
+		sum := sha256.Sum256(data)
+		return name + "@user-" + hex.EncodeToString(sum[:4]), string(data), nil
+	}
+
+	text, err := DefaultText(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	return name + "@" + version, text, nil
+}
+
+// overridePath returns the user override for a prompt if one exists
+func (l *Library) overridePath(name string) (string, bool) {
+	if l.overrideDir == "" {
+		return "", false
+	}
+
+	path := filepath.Join(l.overrideDir, name+templateExt)
+	if _, err := os.Stat(path); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			// Surface unreadable overrides when loading instead of silently ignoring them
+			return path, true
+		}
+		return "", false
+	}
+
+	return path, true
+}
+
+// redactStrings returns a copy of v with every string masked by redactor
+func redactStrings(v reflect.Value, redactor Redactor) reflect.Value {
+	switch v.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(redactor.Redact(v.String())).Convert(v.Type())
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		redacted := reflect.New(v.Type().Elem())
+		redacted.Elem().Set(redactStrings(v.Elem(), redactor))
+		return redacted
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		redacted := reflect.New(v.Type()).Elem()
+		redacted.Set(redactStrings(v.Elem(), redactor))
+		return redacted
+	case reflect.Struct:
+		redacted := reflect.New(v.Type()).Elem()
+		redacted.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			// Unexported fields cannot reach the template, so they are kept as they are
+			if field := redacted.Field(i); field.CanSet() {
+				field.Set(redactStrings(v.Field(i), redactor))
+			}
+		}
+		return redacted
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		redacted := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			redacted.Index(i).Set(redactStrings(v.Index(i), redactor))
+		}
+		return redacted
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		redacted := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for _, key := range v.MapKeys() {
+			redacted.SetMapIndex(key, redactStrings(v.MapIndex(key), redactor))
+		}
+		return redacted
+	default:
+		return v
+	}
+}
@@ -0,0 +1,137 @@
+package prompts_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tennashi/tabler/internal/prompts"
+)
+
+func TestLibrary(t *testing.T) {
+	t.Run("renders embedded default", func(t *testing.T) {
+		// Arrange
+		library := prompts.NewLibrary(t.TempDir())
+
+		// Act
+		rendered, err := library.Render(prompts.Decomposition, struct{ Task string }{Task: "organize conference"})
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rendered.ID != "decomposition@v2" {
+			t.Errorf("expected ID %q, got %q", "decomposition@v2", rendered.ID)
+		}
+		if !strings.Contains(rendered.Text, `Task: "organize conference"`) {
+			t.Errorf("expected task in prompt, got %q", rendered.Text)
+		}
+	})
+
+	t.Run("prefers user override", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		override := "Split {{.Task}} into steps using our team's sprint vocabulary."
+		if err := os.WriteFile(filepath.Join(dir, "decomposition.tmpl"), []byte(override), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		library := prompts.NewLibrary(dir)
+
+		// Act
+		rendered, err := library.Render(prompts.Decomposition, struct{ Task string }{Task: "release"})
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rendered.Text != "Split release into steps using our team's sprint vocabulary." {
+			t.Errorf("unexpected prompt: %q", rendered.Text)
+		}
+		if !strings.HasPrefix(rendered.ID, "decomposition@user-") {
+			t.Errorf("expected user version ID, got %q", rendered.ID)
+		}
+		if id, err := library.ID(prompts.Decomposition); err != nil || id != rendered.ID {
+			t.Errorf("expected ID %q to match the rendered prompt, got %q, %v", rendered.ID, id, err)
+		}
+	})
+
+	t.Run("redacts data but not the template", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		override := "Keep work short: {{.Task}} then {{range .Steps}}{{.}};{{end}}"
+		if err := os.WriteFile(filepath.Join(dir, "decomposition.tmpl"), []byte(override), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		library := prompts.NewLibrary(dir)
+		library.SetRedactor(replaceRedactor{old: "work", new: "[PRIVATE_1]"})
+		data := struct {
+			Task  string
+			Steps []string
+		}{Task: "work offsite", Steps: []string{"book work trip"}}
+
+		// Act
+		rendered, err := library.Render(prompts.Decomposition, data)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := "Keep work short: [PRIVATE_1] offsite then book [PRIVATE_1] trip;"
+		if rendered.Text != expected {
+			t.Errorf("expected %q, got %q", expected, rendered.Text)
+		}
+		if data.Task != "work offsite" || data.Steps[0] != "book work trip" {
+			t.Errorf("expected the data to be left alone, got %+v", data)
+		}
+	})
+
+	t.Run("reports invalid override", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "metadata.tmpl"), []byte("{{.Missing"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		library := prompts.NewLibrary(dir)
+
+		// Act
+		_, err := library.Render(prompts.Metadata, struct{ Request string }{Request: "{}"})
+
+		// Assert
+		if err == nil {
+			t.Error("expected error for invalid template")
+		}
+	})
+
+	t.Run("lists prompts with sources", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "metadata.tmpl"), []byte("{{.Request}}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		library := prompts.NewLibrary(dir)
+
+		// Act
+		infos, err := library.List()
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(infos) != 6 {
+			t.Fatalf("expected 6 prompts, got %d", len(infos))
+		}
+		for _, info := range infos {
+			overridden := info.Path != ""
+			if overridden != (info.Name == prompts.Metadata) {
+				t.Errorf("unexpected source for %s: %q", info.Name, info.Path)
+			}
+		}
+	})
+}
+
+// replaceRedactor masks every occurrence of one word
+type replaceRedactor struct {
+	old string
+	new string
+}
+
+func (r replaceRedactor) Redact(text string) string {
+	return strings.ReplaceAll(text, r.old, r.new)
+}
@@ -0,0 +1,308 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tennashi/tabler/internal/parser"
+)
+
+// SyntaxError reports where an expression could not be parsed
+type SyntaxError struct {
+	// Pos is the byte offset of the problem in the expression
+	Pos int
+	Msg string
+}
+
+// Error implements the error interface
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid query at position %d: %s", e.Pos+1, e.Msg)
+}
+
+// fields are the names conditions can test
+var fields = map[Field]bool{
+	FieldTag:      true,
+	FieldTitle:    true,
+	FieldPriority: true,
+	FieldDue:      true,
+	FieldStatus:   true,
+}
+
+// statusWords are bare words that stand for a status condition
+var statusWords = map[string]bool{
+	StatusDone:    true,
+	StatusPending: true,
+	StatusBlocked: true,
+}
+
+// Parse reads a filter expression. Conditions are written field:value or with
+// a comparison such as priority>=2, and combined with and, or, not and
+// parentheses; conditions next to each other must all match. The bare words
+// done, pending and blocked test the status. An empty expression returns nil.
+func Parse(input string) (Expr, error) {
+	p := &parserState{input: input}
+	p.skipSpace()
+	if p.done() {
+		return nil, nil
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, p.errorf("unexpected %q", p.input[p.pos:])
+	}
+	return expr, nil
+}
+
+// parserState walks an expression one character at a time
+type parserState struct {
+	input string
+	pos   int
+}
+
+func (p *parserState) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parserState) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if !p.keyword("and") && (p.done() || p.peek() == ')' || p.peekKeyword("or")) {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+}
+
+func (p *parserState) parseUnary() (Expr, error) {
+	if p.keyword("not") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &Not{Expr: expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parserState) parsePrimary() (Expr, error) {
+	if p.done() {
+		return nil, p.errorf("expected a condition")
+	}
+
+	if p.peek() == '(' {
+		p.pos++
+		p.skipSpace()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.done() || p.peek() != ')' {
+			return nil, p.errorf("missing ')'")
+		}
+		p.pos++
+		p.skipSpace()
+		return expr, nil
+	}
+
+	return p.parseCondition()
+}
+
+func (p *parserState) parseCondition() (Expr, error) {
+	start := p.pos
+	name := strings.ToLower(p.readWhile(isFieldChar))
+	if name == "" {
+		return nil, p.errorf("expected a condition")
+	}
+
+	op, ok := p.readOp()
+	if !ok {
+		p.skipSpace()
+		if statusWords[name] {
+			return &Condition{Field: FieldStatus, Op: OpEqual, Value: name}, nil
+		}
+		return nil, &SyntaxError{Pos: start, Msg: fmt.Sprintf("%q is not a condition; use field:value or done, pending, blocked", name)}
+	}
+	if !fields[Field(name)] {
+		return nil, &SyntaxError{Pos: start, Msg: fmt.Sprintf("unknown field %q (available: tag, title, priority, due, status)", name)}
+	}
+
+	valuePos := p.pos
+	value, err := p.readValue()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+
+	condition := &Condition{Field: Field(name), Op: op, Value: value}
+	if err := condition.resolve(); err != nil {
+		return nil, &SyntaxError{Pos: valuePos, Msg: err.Error()}
+	}
+	return condition, nil
+}
+
+// resolve checks the operator and value for the field and fills in typed values
+func (c *Condition) resolve() error {
+	equality := c.Op == OpEqual || c.Op == OpNotEqual
+	switch c.Field {
+	case FieldTag, FieldStatus:
+		if !equality {
+			return fmt.Errorf("%s only supports : and !=", c.Field)
+		}
+		if c.Field == FieldTag {
+			// Tags are normalized with the configured rules when the query runs
+			return nil
+		}
+		c.Value = strings.ToLower(c.Value)
+		if !statusWords[c.Value] {
+			return fmt.Errorf("status must be done, pending or blocked, got %q", c.Value)
+		}
+	case FieldTitle:
+		if !equality {
+			return fmt.Errorf("title only supports : and !=")
+		}
+	case FieldPriority:
+		n, err := strconv.Atoi(strings.Trim(c.Value, "!"))
+		if strings.Trim(c.Value, "!") == "" {
+			// Priority can be written the way tasks are: !, !! or !!!
+			n, err = len(c.Value), nil
+		}
+		if err != nil || n < 0 || n > 3 {
+			return fmt.Errorf("priority must be between 0 and 3, got %q", c.Value)
+		}
+		c.Number = n
+	case FieldDue:
+		c.Value = strings.ToLower(c.Value)
+		if c.Value == DueNone {
+			if !equality {
+				return fmt.Errorf("due:none only supports : and !=")
+			}
+			return nil
+		}
+		deadline, ok := parser.ParseDeadline(c.Value)
+		if !ok {
+			return fmt.Errorf("unknown date %q; use today, tomorrow, a weekday, YYYY-MM-DD or none", c.Value)
+		}
+		c.Date = *deadline
+	}
+	return nil
+}
+
+// readOp reads a comparison operator; ':' is the same as '='
+func (p *parserState) readOp() (Op, bool) {
+	for _, op := range []string{"!=", "<=", ">=", "<", ">", "=", ":"} {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			p.pos += len(op)
+			if op == ":" {
+				return OpEqual, true
+			}
+			return Op(op), true
+		}
+	}
+	return "", false
+}
+
+// readValue reads a quoted string or a bare word
+func (p *parserState) readValue() (string, error) {
+	if p.done() || p.peek() != '"' {
+		value := p.readWhile(func(r rune) bool { return !isSpace(r) && r != '(' && r != ')' })
+		if value == "" {
+			return "", p.errorf("missing value")
+		}
+		return value, nil
+	}
+
+	start := p.pos
+	end := start + 1
+	for end < len(p.input) && p.input[end] != '"' {
+		if p.input[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	if end >= len(p.input) {
+		return "", &SyntaxError{Pos: start, Msg: "unterminated string"}
+	}
+	value, err := strconv.Unquote(p.input[start : end+1])
+	if err != nil {
+		return "", &SyntaxError{Pos: start, Msg: "invalid string"}
+	}
+	p.pos = end + 1
+	return value, nil
+}
+
+// keyword consumes a case-insensitive keyword followed by a space, '(' or the end
+func (p *parserState) keyword(word string) bool {
+	if !p.peekKeyword(word) {
+		return false
+	}
+	p.pos += len(word)
+	p.skipSpace()
+	return true
+}
+
+func (p *parserState) peekKeyword(word string) bool {
+	rest := p.input[p.pos:]
+	if len(rest) < len(word) || !strings.EqualFold(rest[:len(word)], word) {
+		return false
+	}
+	if len(rest) == len(word) {
+		return true
+	}
+	next := rune(rest[len(word)])
+	return isSpace(next) || next == '('
+}
+
+func (p *parserState) readWhile(accept func(rune) bool) string {
+	start := p.pos
+	for p.pos < len(p.input) && accept(rune(p.input[p.pos])) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *parserState) skipSpace() {
+	p.readWhile(isSpace)
+}
+
+func (p *parserState) peek() byte {
+	return p.input[p.pos]
+}
+
+func (p *parserState) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parserState) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func isFieldChar(r rune) bool {
+	return r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-')
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n'
+}
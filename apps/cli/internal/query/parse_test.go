@@ -0,0 +1,103 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("should build a syntax tree", func(t *testing.T) {
+		tests := []struct {
+			input    string
+			expected string
+		}{
+			{"tag:work", "tag:work"},
+			{"TAG:Work", "tag:Work"},
+			{"priority>=2", "priority>=2"},
+			{"priority:!!!", "priority:!!!"},
+			{"done", "status:done"},
+			{`title:"weekly report"`, `title:"weekly report"`},
+			{"tag:work not done", "(tag:work and not status:done)"},
+			{"tag:work or tag:home and blocked", "(tag:work or (tag:home and status:blocked))"},
+			{"tag:work and (priority>=2 or due<2030-05-17) and not done",
+				"((tag:work and (priority>=2 or due<2030-05-17)) and not status:done)"},
+			{"not (tag:work or tag:home)", "not (tag:work or tag:home)"},
+			{"due:none", "due:none"},
+			{"   ", ""},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				// Act
+				expr, err := Parse(tt.input)
+
+				// Assert
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				got := ""
+				if expr != nil {
+					got = expr.String()
+				}
+				if got != tt.expected {
+					t.Errorf("expected %s, got %s", tt.expected, got)
+				}
+			})
+		}
+	})
+
+	t.Run("should resolve typed values", func(t *testing.T) {
+		// Act
+		expr, err := Parse("priority:!! and due<=2030-05-17")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		and := expr.(*And)
+		if priority := and.Left.(*Condition); priority.Number != 2 {
+			t.Errorf("expected priority 2, got %d", priority.Number)
+		}
+		expected := time.Date(2030, 5, 17, 0, 0, 0, 0, time.UTC)
+		if due := and.Right.(*Condition); !due.Date.Equal(expected) || due.Op != OpLessEqual {
+			t.Errorf("expected due<=%v, got %s%v", expected, due.Op, due.Date)
+		}
+	})
+
+	t.Run("should report syntax errors", func(t *testing.T) {
+		tests := []struct {
+			input string
+			pos   int
+		}{
+			{"size:large", 0},
+			{"urgent", 0},
+			{"tag:work and", 12},
+			{"(tag:work", 9},
+			{"tag:work)", 8},
+			{"priority:5", 9},
+			{"tag>work", 4},
+			{"due<someday", 4},
+			{"due>none", 4},
+			{`title:"unfinished`, 6},
+			{"status:maybe", 7},
+			{"tag:", 4},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.input, func(t *testing.T) {
+				// Act
+				_, err := Parse(tt.input)
+
+				// Assert
+				var syntaxErr *SyntaxError
+				if !errors.As(err, &syntaxErr) {
+					t.Fatalf("expected SyntaxError, got %v", err)
+				}
+				if syntaxErr.Pos != tt.pos {
+					t.Errorf("expected error at %d, got %d (%v)", tt.pos, syntaxErr.Pos, err)
+				}
+			})
+		}
+	})
+}
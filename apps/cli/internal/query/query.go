@@ -0,0 +1,111 @@
+// Package query parses task filter expressions such as
+// "tag:work and (priority>=2 or due<friday) and not done" into a syntax tree.
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Field is a task property a condition tests
+type Field string
+
+const (
+	// FieldTag matches tasks carrying a tag
+	FieldTag Field = "tag"
+	// FieldTitle matches tasks whose title contains a text
+	FieldTitle Field = "title"
+	// FieldPriority compares the priority level (0-3)
+	FieldPriority Field = "priority"
+	// FieldDue compares the deadline by day; "none" matches tasks without one
+	FieldDue Field = "due"
+	// FieldStatus matches done, pending or blocked tasks
+	FieldStatus Field = "status"
+)
+
+// Status values for FieldStatus
+const (
+	StatusDone    = "done"
+	StatusPending = "pending"
+	StatusBlocked = "blocked"
+)
+
+// DueNone is the FieldDue value for tasks without a deadline
+const DueNone = "none"
+
+// Op is a comparison operator
+type Op string
+
+const (
+	OpEqual        Op = "="
+	OpNotEqual     Op = "!="
+	OpLess         Op = "<"
+	OpLessEqual    Op = "<="
+	OpGreater      Op = ">"
+	OpGreaterEqual Op = ">="
+)
+
+// Expr is a node of a parsed filter expression
+type Expr interface {
+	String() string
+}
+
+// And matches tasks matching both sides
+type And struct {
+	Left, Right Expr
+}
+
+// Or matches tasks matching either side
+type Or struct {
+	Left, Right Expr
+}
+
+// Not matches tasks not matching Expr
+type Not struct {
+	Expr Expr
+}
+
+// Condition compares one field of a task with a value
+type Condition struct {
+	Field Field
+	Op    Op
+	// Value is the value as written, lower-cased for everything but titles and tags
+	Value string
+	// Number holds the value of FieldPriority
+	Number int
+	// Date holds the day of FieldDue unless Value is DueNone
+	Date time.Time
+}
+
+func (e *And) String() string {
+	return fmt.Sprintf("(%s and %s)", e.Left, e.Right)
+}
+
+func (e *Or) String() string {
+	return fmt.Sprintf("(%s or %s)", e.Left, e.Right)
+}
+
+func (e *Not) String() string {
+	return fmt.Sprintf("not %s", e.Expr)
+}
+
+func (c *Condition) String() string {
+	if c.Op == OpEqual {
+		return fmt.Sprintf("%s:%s", c.Field, quote(c.Value))
+	}
+	return fmt.Sprintf("%s%s%s", c.Field, c.Op, quote(c.Value))
+}
+
+// quote wraps values that would not survive parsing as a bare word
+func quote(value string) string {
+	for _, r := range value {
+		if isSpace(r) || r == '(' || r == ')' || r == '"' {
+			return strconv.Quote(value)
+		}
+	}
+	if value == "" {
+		return `""`
+	}
+	return value
+}
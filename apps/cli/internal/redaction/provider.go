@@ -0,0 +1,39 @@
+package redaction
+
+import (
+	"context"
+
+	"github.com/tennashi/tabler/internal/claude"
+)
+
+// Provider restores, in the responses of another provider, the text a Session
+// masked while prompts were rendered
+type Provider struct {
+	provider claude.Provider
+	session  *Session
+}
+
+// NewProvider creates a provider that restores the placeholders of session
+func NewProvider(provider claude.Provider, session *Session) *Provider {
+	return &Provider{provider: provider, session: session}
+}
+
+// Execute implements claude.Provider
+func (p *Provider) Execute(ctx context.Context, prompt string) (string, error) {
+	response, err := p.provider.Execute(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	return p.session.Restore(response), nil
+}
+
+// ExecuteMasked implements claude.MaskingProvider
+func (p *Provider) ExecuteMasked(ctx context.Context, prompt string) (string, error) {
+	return p.provider.Execute(ctx, prompt)
+}
+
+// Restore implements claude.MaskingProvider
+func (p *Provider) Restore(text string) string {
+	return p.session.Restore(text)
+}
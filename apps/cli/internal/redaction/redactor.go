@@ -0,0 +1,170 @@
+package redaction
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/tennashi/tabler/internal/config"
+)
+
+// Built-in patterns for personal data
+var (
+	urlPattern   = regexp.MustCompile(`(?:https?://|www\.)[^\s<>"']*[^\s<>"'.,;:!?)]`)
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// Requires separated digit groups so dates like 2024-01-15 are left alone
+	phonePattern = regexp.MustCompile(`\+\d{7,15}\b|(?:\+\d{1,3}[\s.-]?)?(?:\(\d{2,4}\)[\s.-]?|\b\d{2,4}[\s.-])\d{2,4}[\s.-]\d{3,4}\b`)
+)
+
+// matcher masks one kind of sensitive text
+type matcher struct {
+	kind    string
+	pattern *regexp.Regexp
+	// wholeTag only masks matches that stand as a whole tag, see isWholeTag
+	wholeTag bool
+}
+
+// Redactor replaces sensitive text with placeholders such as [EMAIL_1]
+type Redactor struct {
+	matchers []matcher
+}
+
+// NewRedactor builds a redactor from the privacy settings
+func NewRedactor(privacy config.Privacy) (*Redactor, error) {
+	r := &Redactor{}
+
+	// URLs go first because they may contain email-like text
+	if privacy.RedactURLs {
+		r.matchers = append(r.matchers, matcher{kind: "URL", pattern: urlPattern})
+	}
+	if privacy.RedactEmails {
+		r.matchers = append(r.matchers, matcher{kind: "EMAIL", pattern: emailPattern})
+	}
+	if privacy.RedactPhones {
+		r.matchers = append(r.matchers, matcher{kind: "PHONE", pattern: phonePattern})
+	}
+
+	for _, tag := range privacy.PrivateTags {
+		tag = strings.TrimPrefix(tag, "#")
+		if tag == "" {
+			continue
+		}
+		// Only the name is masked so "#tag" keeps its marker and the model still sees a tag.
+		// Descendants such as "tag/client" are masked whole.
+		pattern := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(tag) + `(?:/[^\s,;:!?()]*)?`)
+		r.matchers = append(r.matchers, matcher{kind: "PRIVATE", pattern: pattern, wholeTag: true})
+	}
+
+	for _, expr := range privacy.Patterns {
+		pattern, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", expr, err)
+		}
+		r.matchers = append(r.matchers, matcher{kind: "REDACTED", pattern: pattern})
+	}
+
+	return r, nil
+}
+
+// Mapping remembers which placeholder stands for which original text
+type Mapping struct {
+	originals    map[string]string // placeholder -> original
+	placeholders map[string]string // original -> placeholder
+	counts       map[string]int
+}
+
+func newMapping() *Mapping {
+	return &Mapping{
+		originals:    make(map[string]string),
+		placeholders: make(map[string]string),
+		counts:       make(map[string]int),
+	}
+}
+
+// placeholder returns a stable placeholder for original, reusing it for repeated values
+func (m *Mapping) placeholder(kind, original string) string {
+	if existing, ok := m.placeholders[original]; ok {
+		return existing
+	}
+
+	m.counts[kind]++
+	placeholder := fmt.Sprintf("[%s_%d]", kind, m.counts[kind])
+	m.originals[placeholder] = original
+	m.placeholders[original] = placeholder
+	return placeholder
+}
+
+// Len returns how many distinct values were masked
+func (m *Mapping) Len() int {
+	return len(m.originals)
+}
+
+// Restore puts the original text back in place of every placeholder
+func (m *Mapping) Restore(text string) string {
+	if len(m.originals) == 0 {
+		return text
+	}
+
+	pairs := make([]string, 0, len(m.originals)*2)
+	for placeholder, original := range m.originals {
+		pairs = append(pairs, placeholder, original)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// Redact masks sensitive text and returns the mapping needed to restore it
+func (r *Redactor) Redact(text string) (string, *Mapping) {
+	mapping := newMapping()
+	return r.redact(text, mapping), mapping
+}
+
+// redact masks sensitive text, adding placeholders to mapping
+func (r *Redactor) redact(text string, mapping *Mapping) string {
+	for _, m := range r.matchers {
+		text = m.replace(text, mapping)
+	}
+	return text
+}
+
+// replace masks every match of m in text
+func (m matcher) replace(text string, mapping *Mapping) string {
+	if !m.wholeTag {
+		return m.pattern.ReplaceAllStringFunc(text, func(match string) string {
+			return mapping.placeholder(m.kind, match)
+		})
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range m.pattern.FindAllStringIndex(text, -1) {
+		if !isWholeTag(text, loc[0], loc[1]) {
+			continue
+		}
+		b.WriteString(text[last:loc[0]])
+		b.WriteString(mapping.placeholder(m.kind, text[loc[0]:loc[1]]))
+		last = loc[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// isWholeTag reports whether text[start:end] is a tag on its own: it follows '#',
+// whitespace or a list comma, and is followed by whitespace, punctuation or the
+// end of the text. Unlike \b this works for tags in any script, such as 仕事.
+func isWholeTag(text string, start, end int) bool {
+	if start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		if before != '#' && before != ',' && !unicode.IsSpace(before) {
+			return false
+		}
+	}
+	if end < len(text) {
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !unicode.IsSpace(after) && !unicode.IsPunct(after) {
+			return false
+		}
+	}
+	return true
+}
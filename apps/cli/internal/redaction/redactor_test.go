@@ -0,0 +1,208 @@
+package redaction_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/tennashi/tabler/internal/config"
+	"github.com/tennashi/tabler/internal/redaction"
+)
+
+func TestRedactor(t *testing.T) {
+	t.Run("Redact", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			privacy  config.Privacy
+			input    string
+			expected string
+		}{
+			{
+				name:     "email",
+				privacy:  config.Default().Privacy,
+				input:    "Reply to alice@example.com today",
+				expected: "Reply to [EMAIL_1] today",
+			},
+			{
+				name:     "url with trailing punctuation",
+				privacy:  config.Default().Privacy,
+				input:    "Review https://example.com/pr/42.",
+				expected: "Review [URL_1].",
+			},
+			{
+				name:     "phone numbers",
+				privacy:  config.Default().Privacy,
+				input:    "Call 090-1234-5678 or (555) 123-4567",
+				expected: "Call [PHONE_1] or [PHONE_2]",
+			},
+			{
+				name:     "dates are not phone numbers",
+				privacy:  config.Default().Privacy,
+				input:    "Today is 2024-01-15",
+				expected: "Today is 2024-01-15",
+			},
+			{
+				name:     "repeated values share a placeholder",
+				privacy:  config.Default().Privacy,
+				input:    "Mail bob@example.com, cc bob@example.com",
+				expected: "Mail [EMAIL_1], cc [EMAIL_1]",
+			},
+			{
+				name:     "disabled rule",
+				privacy:  config.Privacy{RedactEmails: false},
+				input:    "Reply to alice@example.com",
+				expected: "Reply to alice@example.com",
+			},
+			{
+				name:     "private tag keeps its marker",
+				privacy:  config.Privacy{PrivateTags: []string{"#salary"}},
+				input:    "Negotiate raise #salary",
+				expected: "Negotiate raise #[PRIVATE_1]",
+			},
+			{
+				name:     "private tag in any script",
+				privacy:  config.Privacy{PrivateTags: []string{"仕事"}},
+				input:    "会議 #仕事 を準備",
+				expected: "会議 #[PRIVATE_1] を準備",
+			},
+			{
+				name:     "private tag masks its descendants",
+				privacy:  config.Privacy{PrivateTags: []string{"work"}},
+				input:    "Call #work/clientA, then #Work",
+				expected: "Call #[PRIVATE_1], then #[PRIVATE_2]",
+			},
+			{
+				name:     "private tag leaves longer tags alone",
+				privacy:  config.Privacy{PrivateTags: []string{"work"}},
+				input:    "Plan #workshop and #homework",
+				expected: "Plan #workshop and #homework",
+			},
+			{
+				name:     "private tag in a tag list",
+				privacy:  config.Privacy{PrivateTags: []string{"salary"}},
+				input:    "tags: home, salary",
+				expected: "tags: home, [PRIVATE_1]",
+			},
+			{
+				name:     "custom pattern",
+				privacy:  config.Privacy{Patterns: []string{`PRJ-\d+`}},
+				input:    "Fix PRJ-1234 crash",
+				expected: "Fix [REDACTED_1] crash",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				redactor, err := redaction.NewRedactor(tt.privacy)
+				if err != nil {
+					t.Fatalf("failed to create redactor: %v", err)
+				}
+
+				// Act
+				result, mapping := redactor.Redact(tt.input)
+
+				// Assert
+				if result != tt.expected {
+					t.Errorf("expected %q, got %q", tt.expected, result)
+				}
+				if restored := mapping.Restore(result); restored != tt.input {
+					t.Errorf("expected restore to give %q, got %q", tt.input, restored)
+				}
+			})
+		}
+	})
+
+	t.Run("NewRedactor", func(t *testing.T) {
+		t.Run("should reject invalid custom patterns", func(t *testing.T) {
+			// Act
+			_, err := redaction.NewRedactor(config.Privacy{Patterns: []string{"("}})
+
+			// Assert
+			if err == nil {
+				t.Error("expected error")
+			}
+		})
+	})
+}
+
+func TestSession(t *testing.T) {
+	t.Run("should share placeholders across redacted fields", func(t *testing.T) {
+		// Arrange
+		redactor, err := redaction.NewRedactor(config.Privacy{RedactEmails: true, PrivateTags: []string{"work"}})
+		if err != nil {
+			t.Fatalf("failed to create redactor: %v", err)
+		}
+		session := redaction.NewSession(redactor)
+
+		// Act
+		first := session.Redact("Mail alice@example.com #work")
+		second := session.Redact("cc alice@example.com")
+
+		// Assert
+		if first != "Mail [EMAIL_1] #[PRIVATE_1]" || second != "cc [EMAIL_1]" {
+			t.Errorf("expected shared placeholders, got %q and %q", first, second)
+		}
+		if restored := session.Restore("[EMAIL_1] about [PRIVATE_1]"); restored != "alice@example.com about work" {
+			t.Errorf("expected restored text, got %q", restored)
+		}
+	})
+}
+
+func TestProvider(t *testing.T) {
+	newProvider := func(t *testing.T, response string) (*redaction.Provider, *echoProvider, string) {
+		t.Helper()
+		redactor, err := redaction.NewRedactor(config.Default().Privacy)
+		if err != nil {
+			t.Fatalf("failed to create redactor: %v", err)
+		}
+		session := redaction.NewSession(redactor)
+		prompt := "Task: " + session.Redact("email alice@example.com")
+		inner := &echoProvider{response: response}
+		return redaction.NewProvider(inner, session), inner, prompt
+	}
+
+	t.Run("should send the prompt as rendered and restore placeholders in the response", func(t *testing.T) {
+		// Arrange
+		provider, inner, prompt := newProvider(t, `Email [EMAIL_1]`)
+
+		// Act
+		response, err := provider.Execute(context.Background(), prompt)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if inner.prompt != "Task: email [EMAIL_1]" {
+			t.Errorf("expected the masked prompt, got %q", inner.prompt)
+		}
+		if response != "Email alice@example.com" {
+			t.Errorf("expected email to be restored, got %q", response)
+		}
+	})
+
+	t.Run("should leave placeholders in masked responses", func(t *testing.T) {
+		// Arrange
+		provider, _, prompt := newProvider(t, `{"cleaned_text": "Email [EMAIL_1]"}`)
+
+		// Act
+		response, err := provider.ExecuteMasked(context.Background(), prompt)
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if strings.Contains(response, "alice@example.com") {
+			t.Errorf("expected placeholders to stay, got %q", response)
+		}
+	})
+}
+
+// echoProvider records the prompt and returns a fixed response
+type echoProvider struct {
+	prompt   string
+	response string
+}
+
+func (p *echoProvider) Execute(_ context.Context, prompt string) (string, error) {
+	p.prompt = prompt
+	return p.response, nil
+}
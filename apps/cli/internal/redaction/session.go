@@ -0,0 +1,23 @@
+package redaction
+
+// Session masks the user-supplied fields of every prompt in one command,
+// sharing placeholders so a value keeps its placeholder across prompts
+type Session struct {
+	redactor *Redactor
+	mapping  *Mapping
+}
+
+// NewSession creates a session that masks text with redactor
+func NewSession(redactor *Redactor) *Session {
+	return &Session{redactor: redactor, mapping: newMapping()}
+}
+
+// Redact masks sensitive text; it implements prompts.Redactor
+func (s *Session) Redact(text string) string {
+	return s.redactor.redact(text, s.mapping)
+}
+
+// Restore puts the original text back in place of the placeholders of this session
+func (s *Session) Restore(text string) string {
+	return s.mapping.Restore(text)
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeleteTasks deletes tasks in one transaction
+func (s *TaskService) DeleteTasks(ids []string) error {
+	return s.storage.DeleteTasks(ids)
+}
+
+// TagTasks adds and removes tags on tasks in one transaction.
+// Tags may be written with or without a leading '#'.
+func (s *TaskService) TagTasks(ids []string, add, remove []string) error {
+	return s.storage.TagTasks(ids, trimTagPrefixes(add), trimTagPrefixes(remove))
+}
+
+// SetTasksPriority sets the priority of tasks in one transaction
+func (s *TaskService) SetTasksPriority(ids []string, priority int) error {
+	if priority < 0 || priority > 3 {
+		return fmt.Errorf("priority must be between 0 and 3, got %d", priority)
+	}
+	return s.storage.SetTasksPriority(ids, priority)
+}
+
+func trimTagPrefixes(tags []string) []string {
+	trimmed := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.TrimPrefix(tag, "#"); tag != "" {
+			trimmed = append(trimmed, tag)
+		}
+	}
+	return trimmed
+}
@@ -0,0 +1,62 @@
+package service
+
+import "github.com/tennashi/tabler/internal/task"
+
+// AddDependency makes taskID wait for dependsOnID to be completed.
+// It returns a *storage.CycleError if the tasks would wait for each other.
+func (s *TaskService) AddDependency(taskID, dependsOnID string) error {
+	return s.storage.AddDependency(taskID, dependsOnID)
+}
+
+// RemoveDependency stops taskID from waiting for dependsOnID
+func (s *TaskService) RemoveDependency(taskID, dependsOnID string) error {
+	return s.storage.RemoveDependency(taskID, dependsOnID)
+}
+
+// Blockers returns the unfinished tasks taskID is waiting for
+func (s *TaskService) Blockers(taskID string) ([]*task.Task, error) {
+	return s.storage.Blockers(taskID)
+}
+
+// CompleteTasksAndUnblock completes tasks in one transaction and returns the
+// tasks waiting for them that can now be worked on
+func (s *TaskService) CompleteTasksAndUnblock(ids ...string) ([]*task.Task, error) {
+	completed := make(map[string]bool, len(ids))
+	var dependents []*task.Task
+	for _, id := range ids {
+		completed[id] = true
+		waiting, err := s.storage.Dependents(id)
+		if err != nil {
+			return nil, err
+		}
+		dependents = append(dependents, waiting...)
+	}
+
+	if err := s.storage.CompleteTasks(ids); err != nil {
+		return nil, err
+	}
+
+	if len(dependents) == 0 {
+		return nil, nil
+	}
+
+	actionable, err := s.storage.ActionableTasks()
+	if err != nil {
+		return nil, err
+	}
+	actionableIDs := make(map[string]bool, len(actionable))
+	for _, t := range actionable {
+		actionableIDs[t.ID] = true
+	}
+
+	// A task waiting for several of the completed tasks is reported once
+	var unblocked []*task.Task
+	reported := make(map[string]bool)
+	for _, dependent := range dependents {
+		if actionableIDs[dependent.ID] && !completed[dependent.ID] && !reported[dependent.ID] {
+			reported[dependent.ID] = true
+			unblocked = append(unblocked, dependent)
+		}
+	}
+	return unblocked, nil
+}
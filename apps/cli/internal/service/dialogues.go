@@ -0,0 +1,41 @@
+package service
+
+import (
+	"errors"
+
+	"github.com/tennashi/tabler/internal/clarification"
+)
+
+// ErrNoDialogues is returned when there is no unfinished dialogue to resume
+var ErrNoDialogues = errors.New("no unfinished talk sessions")
+
+// SaveDialogue keeps an unfinished clarification session so it can be resumed
+func (s *TaskService) SaveDialogue(session *clarification.DialogueSession) error {
+	return s.storage.SaveDialogue(session)
+}
+
+// DeleteDialogue forgets a clarification session once its task is created
+func (s *TaskService) DeleteDialogue(id string) error {
+	return s.storage.DeleteDialogue(id)
+}
+
+// GetDialogue returns the saved session with id, or the most recent one when id is empty
+func (s *TaskService) GetDialogue(id string) (*clarification.DialogueSession, error) {
+	if id != "" {
+		return s.storage.GetDialogue(id)
+	}
+
+	sessions, err := s.storage.ListDialogues()
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, ErrNoDialogues
+	}
+	return sessions[0], nil
+}
+
+// ListDialogues returns unfinished clarification sessions, most recent first
+func (s *TaskService) ListDialogues() ([]*clarification.DialogueSession, error) {
+	return s.storage.ListDialogues()
+}
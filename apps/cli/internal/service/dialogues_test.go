@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/clarification"
+)
+
+func TestTaskServiceDialogues(t *testing.T) {
+	t.Run("GetDialogue", func(t *testing.T) {
+		t.Run("should report when nothing is left to resume", func(t *testing.T) {
+			// Arrange
+			service, err := NewTaskService(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			defer func() {
+				_ = service.Close()
+			}()
+
+			// Act
+			_, err = service.GetDialogue("")
+
+			// Assert
+			if !errors.Is(err, ErrNoDialogues) {
+				t.Errorf("expected ErrNoDialogues, got %v", err)
+			}
+		})
+
+		t.Run("should pick the most recent session without an ID", func(t *testing.T) {
+			// Arrange
+			service, err := NewTaskService(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			defer func() {
+				_ = service.Close()
+			}()
+
+			started := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+			for i, id := range []string{"older", "newer"} {
+				at := started.Add(time.Duration(i) * time.Hour)
+				session := &clarification.DialogueSession{
+					ID:            id,
+					Input:         "plan the thing",
+					OriginalInput: "plan the thing",
+					ExtractedInfo: map[string]string{},
+					CreatedAt:     at,
+					UpdatedAt:     at,
+				}
+				if err := service.SaveDialogue(session); err != nil {
+					t.Fatalf("failed to save dialogue: %v", err)
+				}
+			}
+
+			// Act
+			session, err := service.GetDialogue("")
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if session.ID != "newer" {
+				t.Errorf("expected the newer session, got %q", session.ID)
+			}
+		})
+	})
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"github.com/tennashi/tabler/internal/enrichment"
+)
+
+// EnrichmentCandidates returns the pending tasks matching filter for AI enrichment.
+// Tasks opted out of AI or carrying an excluded tag are skipped.
+func (s *TaskService) EnrichmentCandidates(filter *FilterOptions) ([]*enrichment.Candidate, error) {
+	items, err := s.ListTasks(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*enrichment.Candidate, 0, len(items))
+	for _, item := range items {
+		if item.Task.Completed || item.Task.NoAI {
+			continue
+		}
+		if _, excluded := s.ExcludedTag(item.Tags); excluded {
+			continue
+		}
+		candidates = append(candidates, &enrichment.Candidate{
+			ID:       item.Task.ID,
+			Title:    item.Task.Title,
+			Tags:     item.Tags,
+			Priority: item.Task.Priority,
+			Deadline: item.Task.Deadline,
+		})
+	}
+
+	return candidates, nil
+}
+
+// ApplyEnrichment stores the metadata from an accepted proposal
+func (s *TaskService) ApplyEnrichment(proposal *enrichment.Proposal) error {
+	t, _, err := s.storage.GetTask(proposal.Candidate.ID)
+	if err != nil {
+		return err
+	}
+
+	t.Priority = proposal.Priority
+	t.Deadline = proposal.Deadline
+
+	return s.storage.UpdateTaskFull(t, proposal.Tags)
+}
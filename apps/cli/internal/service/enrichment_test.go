@@ -0,0 +1,112 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/enrichment"
+)
+
+func TestTaskServiceEnrichment(t *testing.T) {
+	t.Run("EnrichmentCandidates", func(t *testing.T) {
+		t.Run("should return only pending untagged tasks", func(t *testing.T) {
+			// Arrange
+			service, err := NewTaskService(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			defer func() {
+				_ = service.Close()
+			}()
+
+			untaggedID, _ := service.CreateTaskFromInput("Call plumber")
+			_, _ = service.CreateTaskFromInput("Write report #work")
+			doneID, _ := service.CreateTaskFromInput("Buy milk")
+			if err := service.CompleteTask(doneID); err != nil {
+				t.Fatalf("failed to complete task: %v", err)
+			}
+
+			// Act
+			candidates, err := service.EnrichmentCandidates(&FilterOptions{Untagged: true})
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(candidates) != 1 {
+				t.Fatalf("expected 1 candidate, got %d", len(candidates))
+			}
+			if candidates[0].ID != untaggedID {
+				t.Errorf("expected candidate %s, got %s", untaggedID, candidates[0].ID)
+			}
+		})
+
+		t.Run("should skip private tasks and tasks with excluded tags", func(t *testing.T) {
+			// Arrange
+			service, err := NewTaskService(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			defer func() {
+				_ = service.Close()
+			}()
+			service.SetExcludedTags([]string{"secret"})
+
+			publicID, _ := service.CreateTaskFromInput("Write report #work")
+			_, _ = service.CreatePrivateTaskFromInput("Call lawyer")
+			_, _ = service.CreateTaskFromInput("Plan surprise #secret")
+
+			// Act
+			candidates, err := service.EnrichmentCandidates(&FilterOptions{})
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(candidates) != 1 || candidates[0].ID != publicID {
+				t.Errorf("expected only %s, got %d candidates", publicID, len(candidates))
+			}
+		})
+	})
+
+	t.Run("ApplyEnrichment", func(t *testing.T) {
+		t.Run("should store proposed tags, priority and deadline", func(t *testing.T) {
+			// Arrange
+			service, err := NewTaskService(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			defer func() {
+				_ = service.Close()
+			}()
+
+			id, _ := service.CreateTaskFromInput("Call plumber")
+			candidates, _ := service.EnrichmentCandidates(&FilterOptions{})
+			deadline := time.Date(2024, 1, 19, 0, 0, 0, 0, time.UTC)
+			proposal := &enrichment.Proposal{
+				Candidate: candidates[0],
+				Tags:      []string{"home"},
+				Priority:  3,
+				Deadline:  deadline,
+			}
+
+			// Act
+			err = service.ApplyEnrichment(proposal)
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			task, tags, err := service.GetTask(id)
+			if err != nil {
+				t.Fatalf("failed to get task: %v", err)
+			}
+			if task.Priority != 3 {
+				t.Errorf("expected priority 3, got %d", task.Priority)
+			}
+			if !task.Deadline.Equal(deadline) {
+				t.Errorf("expected deadline %v, got %v", deadline, task.Deadline)
+			}
+			if len(tags) != 1 || tags[0] != "home" {
+				t.Errorf("expected tags [home], got %v", tags)
+			}
+		})
+	})
+}
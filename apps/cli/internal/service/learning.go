@@ -0,0 +1,125 @@
+package service
+
+import (
+	"time"
+
+	"github.com/tennashi/tabler/internal/config"
+	"github.com/tennashi/tabler/internal/learning"
+	"github.com/tennashi/tabler/internal/task"
+)
+
+// LearningStatus summarizes the local pattern store
+type LearningStatus struct {
+	Enabled       bool
+	Patterns      int
+	RetentionDays int
+}
+
+// SetLearning sets the pattern tracking settings
+func (s *TaskService) SetLearning(settings config.Learning) {
+	s.learning = settings
+}
+
+// learnFromTask records the patterns of a newly created task.
+// Learning is best effort and never fails task creation.
+func (s *TaskService) learnFromTask(t *task.Task, tags []string) {
+	if !s.learning.Enabled {
+		return
+	}
+
+	patterns := learning.Observe(t.Title, tags, t.Priority, t.CreatedAt, s.learning.ExcludedTags)
+	if len(patterns) == 0 {
+		return
+	}
+
+	if err := s.storage.RecordPatterns(patterns, t.CreatedAt); err != nil {
+		return
+	}
+
+	if s.learning.RetentionDays > 0 {
+		cutoff := time.Now().AddDate(0, 0, -s.learning.RetentionDays)
+		_, _ = s.storage.DeletePatternsUnusedSince(cutoff)
+	}
+}
+
+// ModeVotes returns how often each mode was explicitly chosen for inputs containing each word
+func (s *TaskService) ModeVotes(words []string) (map[string]map[string]int, error) {
+	patterns, err := s.storage.ListPatterns(learning.TypeModeChoice)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[string]bool, len(words))
+	for _, word := range words {
+		wanted[word] = true
+	}
+
+	votes := make(map[string]map[string]int)
+	for _, p := range patterns {
+		if !wanted[p.Key] {
+			continue
+		}
+		if votes[p.Key] == nil {
+			votes[p.Key] = make(map[string]int)
+		}
+		votes[p.Key][p.Value] = p.Count
+	}
+
+	return votes, nil
+}
+
+// RecordModeChoice remembers that mode was explicitly chosen for an input with these words
+func (s *TaskService) RecordModeChoice(words []string, mode string) error {
+	if !s.learning.Enabled {
+		return nil
+	}
+
+	patterns := make([]*learning.Pattern, 0, len(words))
+	for _, word := range words {
+		patterns = append(patterns, &learning.Pattern{Type: learning.TypeModeChoice, Key: word, Value: mode})
+	}
+
+	return s.storage.RecordPatterns(patterns, time.Now())
+}
+
+// SuggestTags completes a partial tag from learned usage
+func (s *TaskService) SuggestTags(partial string, limit int) ([]learning.Suggestion, error) {
+	patterns, err := s.storage.ListPatterns(learning.TypeTag)
+	if err != nil {
+		return nil, err
+	}
+	return learning.CompleteTag(patterns, partial, limit), nil
+}
+
+// SuggestTagsForTime returns tags usually added at the same time of day as at
+func (s *TaskService) SuggestTagsForTime(at time.Time, limit int) ([]learning.Suggestion, error) {
+	patterns, err := s.storage.ListPatterns(learning.TypeTimeTag)
+	if err != nil {
+		return nil, err
+	}
+	return learning.SuggestTagsForTime(patterns, at, limit), nil
+}
+
+// LearningStatus reports whether learning is enabled and how much is stored
+func (s *TaskService) LearningStatus() (*LearningStatus, error) {
+	patterns, err := s.storage.ListPatterns("")
+	if err != nil {
+		return nil, err
+	}
+
+	return &LearningStatus{
+		Enabled:       s.learning.Enabled,
+		Patterns:      len(patterns),
+		RetentionDays: s.learning.RetentionDays,
+	}, nil
+}
+
+// ExportLearning returns every learned pattern
+func (s *TaskService) ExportLearning() ([]*learning.Pattern, error) {
+	return s.storage.ListPatterns("")
+}
+
+// ClearLearning deletes every learned pattern and returns how many were removed
+func (s *TaskService) ClearLearning() (int64, error) {
+	return s.storage.DeletePatterns()
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/config"
+	"github.com/tennashi/tabler/internal/task"
+)
+
+func TestTaskServiceLearning(t *testing.T) {
+	newService := func(t *testing.T) *TaskService {
+		t.Helper()
+		service, err := NewTaskService(t.TempDir())
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		t.Cleanup(func() {
+			_ = service.Close()
+		})
+		return service
+	}
+
+	t.Run("SuggestTags", func(t *testing.T) {
+		t.Run("should complete tags learned from created tasks", func(t *testing.T) {
+			// Arrange
+			service := newService(t)
+			for _, input := range []string{"Write report #work", "Fix bug #work", "Prepare slides #workshop"} {
+				if _, err := service.CreateTaskFromInput(input); err != nil {
+					t.Fatalf("failed to create task: %v", err)
+				}
+			}
+
+			// Act
+			suggestions, err := service.SuggestTags("wo", 5)
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(suggestions) != 2 {
+				t.Fatalf("expected 2 suggestions, got %d", len(suggestions))
+			}
+			if suggestions[0].Value != "work" || suggestions[0].Count != 2 {
+				t.Errorf("expected work used 2 times first, got %+v", suggestions[0])
+			}
+		})
+
+		t.Run("should learn tags as they are stored", func(t *testing.T) {
+			// Arrange
+			service := newService(t)
+			if _, err := service.CreateTaskFromInput("Write report #Work,"); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			plan := &task.Task{ID: "plan", Title: "Plan offsite", Tags: []string{"WORK"}, CreatedAt: time.Now()}
+			if _, err := service.StoreTask(plan); err != nil {
+				t.Fatalf("failed to store task: %v", err)
+			}
+
+			// Act
+			suggestions, err := service.SuggestTags("", 5)
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(suggestions) != 1 || suggestions[0].Value != "work" || suggestions[0].Count != 2 {
+				t.Errorf("expected work used 2 times, got %+v", suggestions)
+			}
+		})
+
+		t.Run("should not learn when disabled", func(t *testing.T) {
+			// Arrange
+			service := newService(t)
+			service.SetLearning(config.Learning{Enabled: false})
+			if _, err := service.CreateTaskFromInput("Write report #work"); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+
+			// Act
+			suggestions, err := service.SuggestTags("", 5)
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(suggestions) != 0 {
+				t.Errorf("expected no suggestions, got %+v", suggestions)
+			}
+		})
+	})
+
+	t.Run("ClearLearning", func(t *testing.T) {
+		t.Run("should remove every learned pattern", func(t *testing.T) {
+			// Arrange
+			service := newService(t)
+			if _, err := service.CreateTaskFromInput("Write report #work !!"); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+
+			// Act
+			removed, err := service.ClearLearning()
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if removed == 0 {
+				t.Error("expected patterns to be removed")
+			}
+			status, err := service.LearningStatus()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if status.Patterns != 0 {
+				t.Errorf("expected no patterns left, got %d", status.Patterns)
+			}
+		})
+	})
+
+	t.Run("ModeVotes", func(t *testing.T) {
+		t.Run("should count explicit mode choices per word", func(t *testing.T) {
+			// Arrange
+			service := newService(t)
+			if err := service.RecordModeChoice([]string{"groceries", "plan"}, "quick"); err != nil {
+				t.Fatalf("failed to record choice: %v", err)
+			}
+			if err := service.RecordModeChoice([]string{"groceries"}, "quick"); err != nil {
+				t.Fatalf("failed to record choice: %v", err)
+			}
+
+			// Act
+			votes, err := service.ModeVotes([]string{"groceries", "milk"})
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if votes["groceries"]["quick"] != 2 {
+				t.Errorf("expected 2 quick votes for groceries, got %v", votes)
+			}
+			if _, ok := votes["plan"]; ok {
+				t.Errorf("expected only requested words, got %v", votes)
+			}
+		})
+	})
+}
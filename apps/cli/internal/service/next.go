@@ -0,0 +1,155 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tennashi/tabler/internal/config"
+	"github.com/tennashi/tabler/internal/parser"
+	"github.com/tennashi/tabler/internal/task"
+)
+
+// ErrNothingActionable is returned when no pending task can be recommended
+var ErrNothingActionable = errors.New("no actionable tasks")
+
+// maxAgeWeeks caps how much waiting around can add to a task's urgency
+const maxAgeWeeks = 4
+
+// ScoreComponent is one part of a task's urgency score
+type ScoreComponent struct {
+	// Factor names what contributed, such as "priority" or "deadline"
+	Factor string
+	// Detail explains the value the factor was computed from
+	Detail string
+	Points float64
+}
+
+// Recommendation is a pending task ranked by urgency
+type Recommendation struct {
+	*TaskItem
+	// Waiting is true while a dependency or subtask is unfinished
+	Waiting   bool
+	Score     float64
+	Breakdown []ScoreComponent
+}
+
+// SetUrgency sets the weights used to rank tasks
+func (s *TaskService) SetUrgency(weights config.Urgency) {
+	s.urgency = weights
+}
+
+// NextTask recommends the most urgent task
+func (s *TaskService) NextTask() (*TaskItem, error) {
+	recommendations, err := s.RecommendTasks(1, false)
+	if err != nil {
+		return nil, err
+	}
+	return recommendations[0].TaskItem, nil
+}
+
+// RecommendTasks returns up to n actionable tasks, most urgent first. With
+// includeWaiting, tasks waiting on dependencies or subtasks follow every
+// actionable task.
+func (s *TaskService) RecommendTasks(n int, includeWaiting bool) ([]*Recommendation, error) {
+	tasks, err := s.storage.PendingTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	waiting, err := s.storage.WaitingTaskIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	recommendations := make([]*Recommendation, 0, len(tasks))
+	for _, t := range tasks {
+		if waiting[t.ID] && !includeWaiting {
+			continue
+		}
+		_, tags, err := s.storage.GetTask(t.ID)
+		if err != nil {
+			return nil, err
+		}
+		recommendation := &Recommendation{
+			TaskItem: &TaskItem{Task: t, Tags: tags},
+			Waiting:  waiting[t.ID],
+		}
+		recommendation.Breakdown = scoreUrgency(t, tags, waiting[t.ID], s.urgency, now)
+		for _, component := range recommendation.Breakdown {
+			recommendation.Score += component.Points
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+
+	if len(recommendations) == 0 {
+		return nil, ErrNothingActionable
+	}
+
+	// Tasks come oldest first, so equally urgent plans keep their order
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Waiting != recommendations[j].Waiting {
+			return !recommendations[i].Waiting
+		}
+		return recommendations[i].Score > recommendations[j].Score
+	})
+
+	if n > 0 && n < len(recommendations) {
+		recommendations = recommendations[:n]
+	}
+	return recommendations, nil
+}
+
+// scoreUrgency breaks down how urgent a task is; factors that add nothing are left out
+func scoreUrgency(t *task.Task, tags []string, waiting bool, weights config.Urgency, now time.Time) []ScoreComponent {
+	var breakdown []ScoreComponent
+	add := func(factor, detail string, points float64) {
+		if points != 0 {
+			breakdown = append(breakdown, ScoreComponent{Factor: factor, Detail: detail, Points: points})
+		}
+	}
+
+	add("priority", strings.Repeat("!", t.Priority), float64(t.Priority)*weights.Priority)
+
+	if !t.Deadline.IsZero() {
+		horizon := float64(max(weights.DeadlineHorizonDays, 1))
+		days := t.Deadline.Sub(now).Hours() / 24
+		add("deadline", describeDeadline(days), weights.Deadline*math.Max(0, math.Min(1, 1-days/horizon)))
+	}
+
+	if days := now.Sub(t.CreatedAt).Hours() / 24; days >= 1 {
+		weeks := math.Min(days/7, maxAgeWeeks)
+		add("age", fmt.Sprintf("%.0f days old", math.Floor(days)), weeks*weights.Age)
+	}
+
+	// A weight on a tag also counts for its descendants, once per task
+	for _, weighted := range slices.Sorted(maps.Keys(weights.Tags)) {
+		if slices.ContainsFunc(tags, func(tag string) bool { return parser.TagIncludes(weighted, tag) }) {
+			add("tag", "#"+weighted, weights.Tags[weighted])
+		}
+	}
+
+	if waiting {
+		add("blocked", "waiting on dependencies or subtasks", -weights.Blocked)
+	}
+
+	return breakdown
+}
+
+// describeDeadline says when a deadline is relative to now
+func describeDeadline(days float64) string {
+	switch {
+	case days < 0:
+		return fmt.Sprintf("overdue by %.0f days", math.Ceil(-days))
+	case days < 1:
+		return "due today"
+	default:
+		return fmt.Sprintf("due in %.0f days", math.Floor(days))
+	}
+}
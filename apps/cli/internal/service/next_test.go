@@ -0,0 +1,287 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/config"
+	"github.com/tennashi/tabler/internal/task"
+)
+
+func TestTaskServiceNextTask(t *testing.T) {
+	t.Run("should report when nothing is actionable", func(t *testing.T) {
+		// Arrange
+		service, err := NewTaskService(t.TempDir())
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		defer func() {
+			_ = service.Close()
+		}()
+
+		// Act
+		_, err = service.NextTask()
+
+		// Assert
+		if !errors.Is(err, ErrNothingActionable) {
+			t.Errorf("expected ErrNothingActionable, got %v", err)
+		}
+	})
+
+	t.Run("should recommend the first unblocked subtask of a plan", func(t *testing.T) {
+		// Arrange
+		service, err := NewTaskService(t.TempDir())
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		defer func() {
+			_ = service.Close()
+		}()
+
+		now := time.Now()
+		venue := &task.Task{ID: "venue", Title: "Book venue", Order: 2, Tags: []string{"offsite"}, CreatedAt: now, UpdatedAt: now}
+		date := &task.Task{ID: "date", Title: "Pick a date", Order: 1, CreatedAt: now, UpdatedAt: now}
+		venue.DependsOn = []string{date.ID}
+		plan := &task.Task{ID: "plan", Title: "Organize offsite", CreatedAt: now, UpdatedAt: now,
+			Subtasks: []*task.Task{date, venue}}
+		if _, err := service.StoreTask(plan); err != nil {
+			t.Fatalf("failed to store plan: %v", err)
+		}
+		if err := service.CompleteTask(date.ID); err != nil {
+			t.Fatalf("failed to complete task: %v", err)
+		}
+
+		// Act
+		next, err := service.NextTask()
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if next.Task.ID != venue.ID {
+			t.Errorf("expected %q, got %q", venue.ID, next.Task.ID)
+		}
+		if len(next.Tags) != 1 || next.Tags[0] != "offsite" {
+			t.Errorf("expected tags of the subtask, got %v", next.Tags)
+		}
+	})
+}
+
+func TestTaskServiceRecommendTasks(t *testing.T) {
+	newService := func(t *testing.T) *TaskService {
+		t.Helper()
+		service, err := NewTaskService(t.TempDir())
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		t.Cleanup(func() {
+			_ = service.Close()
+		})
+		return service
+	}
+
+	t.Run("should rank tasks by urgency and leave blocked tasks last", func(t *testing.T) {
+		// Arrange
+		service, err := NewTaskService(t.TempDir())
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		defer func() {
+			_ = service.Close()
+		}()
+		service.SetUrgency(config.Urgency{Priority: 3, Deadline: 10, DeadlineHorizonDays: 14, Blocked: 20,
+			Tags: map[string]float64{"work": 4}})
+
+		now := time.Now()
+		tasks := []*task.Task{
+			{ID: "someday", Title: "Learn Rust", CreatedAt: now, UpdatedAt: now},
+			{ID: "report", Title: "Quarterly report", Priority: 1, Deadline: now.Add(24 * time.Hour),
+				CreatedAt: now, UpdatedAt: now},
+			{ID: "deploy", Title: "Deploy", Priority: 3, CreatedAt: now, UpdatedAt: now},
+			{ID: "review", Title: "Code review", Tags: []string{"work"}, CreatedAt: now, UpdatedAt: now},
+		}
+		for _, pending := range tasks {
+			if _, err := service.StoreTask(pending); err != nil {
+				t.Fatalf("failed to store task: %v", err)
+			}
+		}
+		if err := service.AddDependency("deploy", "review"); err != nil {
+			t.Fatalf("failed to add dependency: %v", err)
+		}
+
+		// Act
+		recommendations, err := service.RecommendTasks(3, false)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var ids []string
+		for _, recommendation := range recommendations {
+			ids = append(ids, recommendation.Task.ID)
+		}
+		expected := []string{"report", "review", "someday"}
+		if !slices.Equal(ids, expected) {
+			t.Errorf("expected %v, got %v", expected, ids)
+		}
+	})
+	t.Run("should never rank waiting tasks above actionable ones", func(t *testing.T) {
+		// Arrange
+		service := newService(t)
+		service.SetUrgency(config.Urgency{Priority: 3, DeadlineHorizonDays: 14, Blocked: 1})
+
+		now := time.Now()
+		date := &task.Task{ID: "date", Title: "Pick a date", Order: 1, CreatedAt: now, UpdatedAt: now}
+		venue := &task.Task{ID: "venue", Title: "Book venue", Order: 2, Priority: 3, DependsOn: []string{date.ID},
+			CreatedAt: now, UpdatedAt: now}
+		plan := &task.Task{ID: "plan", Title: "Organize offsite", Priority: 3, Subtasks: []*task.Task{date, venue},
+			CreatedAt: now, UpdatedAt: now}
+		if _, err := service.StoreTask(plan); err != nil {
+			t.Fatalf("failed to store plan: %v", err)
+		}
+
+		// Act
+		actionable, err := service.RecommendTasks(0, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		all, err := service.RecommendTasks(0, true)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(actionable) != 1 || actionable[0].Task.ID != date.ID {
+			t.Errorf("expected only the unblocked subtask, got %v", recommendationIDs(actionable))
+		}
+		if len(all) != 3 || all[0].Task.ID != date.ID || !all[1].Waiting || !all[2].Waiting {
+			t.Errorf("expected waiting tasks after the unblocked subtask, got %v", recommendationIDs(all))
+		}
+	})
+}
+
+// recommendationIDs returns the task IDs of recommendations in order
+func recommendationIDs(recommendations []*Recommendation) []string {
+	ids := make([]string, 0, len(recommendations))
+	for _, recommendation := range recommendations {
+		ids = append(ids, recommendation.Task.ID)
+	}
+	return ids
+}
+
+func TestScoreUrgency(t *testing.T) {
+	now := time.Date(2030, 5, 17, 12, 0, 0, 0, time.UTC)
+	weights := config.Urgency{Priority: 3, Deadline: 10, DeadlineHorizonDays: 10, Age: 1, Blocked: 20,
+		Tags: map[string]float64{"work": 2}}
+
+	tests := []struct {
+		name     string
+		task     *task.Task
+		tags     []string
+		waiting  bool
+		expected []ScoreComponent
+	}{
+		{
+			name:     "new task without metadata",
+			task:     &task.Task{CreatedAt: now},
+			expected: nil,
+		},
+		{
+			name: "priority, tags and blocked status",
+			task: &task.Task{Priority: 2, CreatedAt: now},
+			tags: []string{"work", "home"},
+			expected: []ScoreComponent{
+				{Factor: "priority", Detail: "!!", Points: 6},
+				{Factor: "tag", Detail: "#work", Points: 2},
+				{Factor: "blocked", Detail: "waiting on dependencies or subtasks", Points: -20},
+			},
+			waiting: true,
+		},
+		{
+			name: "tag weight counts once for descendants",
+			task: &task.Task{CreatedAt: now},
+			tags: []string{"work/clienta", "work"},
+			expected: []ScoreComponent{
+				{Factor: "tag", Detail: "#work", Points: 2},
+			},
+		},
+		{
+			name: "deadline within the horizon",
+			task: &task.Task{Deadline: now.Add(5 * 24 * time.Hour), CreatedAt: now},
+			expected: []ScoreComponent{
+				{Factor: "deadline", Detail: "due in 5 days", Points: 5},
+			},
+		},
+		{
+			name: "overdue and old",
+			task: &task.Task{Deadline: now.Add(-48 * time.Hour), CreatedAt: now.Add(-10 * 7 * 24 * time.Hour)},
+			expected: []ScoreComponent{
+				{Factor: "deadline", Detail: "overdue by 2 days", Points: 10},
+				{Factor: "age", Detail: "70 days old", Points: 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			breakdown := scoreUrgency(tt.task, tt.tags, tt.waiting, weights, now)
+
+			// Assert
+			if !slices.Equal(breakdown, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, breakdown)
+			}
+		})
+	}
+}
+
+func TestTaskServiceCompleteTasksAndUnblock(t *testing.T) {
+	t.Run("should report dependents that became actionable", func(t *testing.T) {
+		// Arrange
+		service, err := NewTaskService(t.TempDir())
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		defer func() {
+			_ = service.Close()
+		}()
+
+		now := time.Now()
+		tests := &task.Task{ID: "tests", Title: "Run tests", CreatedAt: now, UpdatedAt: now}
+		review := &task.Task{ID: "review", Title: "Code review", CreatedAt: now, UpdatedAt: now}
+		deploy := &task.Task{ID: "deploy", Title: "Deploy", CreatedAt: now, UpdatedAt: now}
+		notes := &task.Task{ID: "notes", Title: "Write release notes", CreatedAt: now, UpdatedAt: now}
+		for _, pending := range []*task.Task{tests, review, deploy, notes} {
+			if _, err := service.StoreTask(pending); err != nil {
+				t.Fatalf("failed to store task: %v", err)
+			}
+		}
+		for _, dependency := range [][2]string{{"deploy", "review"}, {"deploy", "tests"}, {"notes", "review"}} {
+			if err := service.AddDependency(dependency[0], dependency[1]); err != nil {
+				t.Fatalf("failed to add dependency: %v", err)
+			}
+		}
+
+		// Act
+		unblocked, err := service.CompleteTasksAndUnblock(review.ID)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(unblocked) != 1 || unblocked[0].ID != notes.ID {
+			t.Errorf("expected only the release notes to be unblocked, got %v", unblocked)
+		}
+		items, err := service.ListTasks(&FilterOptions{HideBlocked: true})
+		if err != nil {
+			t.Fatalf("failed to list tasks: %v", err)
+		}
+		for _, item := range items {
+			if item.Task.ID == deploy.ID {
+				t.Errorf("expected deploy to be hidden while tests are pending")
+			}
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tennashi/tabler/internal/learning"
+	"github.com/tennashi/tabler/internal/parser"
+)
+
+const (
+	// minSimilarity is the cosine similarity a past task needs to count as similar
+	minSimilarity = 0.3
+	// maxSimilarTasks caps how many neighbours vote on a suggestion
+	maxSimilarTasks = 10
+	// minSimilarTasks avoids suggesting from a single coincidental match
+	minSimilarTasks = 2
+	// minVoteShare is the weighted share of neighbours that must agree on a value
+	minVoteShare = 0.5
+)
+
+// SimilarSuggestion is metadata proposed from similar past tasks
+type SimilarSuggestion struct {
+	Matches        int
+	Tags           []string
+	Priority       int
+	DeadlineOffset int // days between creation and deadline
+	HasDeadline    bool
+}
+
+// IsEmpty reports whether there is nothing to suggest
+func (s *SimilarSuggestion) IsEmpty() bool {
+	return len(s.Tags) == 0 && s.Priority == 0 && !s.HasDeadline
+}
+
+// Shortcuts renders the suggestion as task shortcuts, placing the deadline relative to now
+func (s *SimilarSuggestion) Shortcuts(now time.Time) string {
+	var parts []string
+	for _, tag := range s.Tags {
+		parts = append(parts, "#"+tag)
+	}
+	if s.Priority > 0 {
+		parts = append(parts, strings.Repeat("!", s.Priority))
+	}
+	if s.HasDeadline {
+		parts = append(parts, "@"+now.AddDate(0, 0, s.DeadlineOffset).Format("2006-01-02"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// neighbour is a past task similar to the input
+type neighbour struct {
+	item       *TaskItem
+	similarity float64
+}
+
+// SuggestFromSimilar proposes tags, priority and a deadline offset from past tasks
+// whose titles resemble input. Only metadata missing from input is suggested.
+func (s *TaskService) SuggestFromSimilar(input string) (*SimilarSuggestion, error) {
+	parsed := parser.Parse(input)
+	suggestion := &SimilarSuggestion{}
+
+	items, err := s.ListTasks(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	neighbours := findSimilar(parsed.Title, items)
+	suggestion.Matches = len(neighbours)
+	if len(neighbours) < minSimilarTasks {
+		return suggestion, nil
+	}
+
+	var totalWeight float64
+	tagWeights := make(map[string]float64)
+	priorityWeights := make(map[int]float64)
+	var deadlineWeight float64
+	var offsets []int
+	for _, n := range neighbours {
+		totalWeight += n.similarity
+		for _, tag := range parser.NormalizeTags(n.item.Tags, s.tagRules) {
+			tagWeights[tag] += n.similarity
+		}
+		priorityWeights[n.item.Task.Priority] += n.similarity
+		if !n.item.Task.Deadline.IsZero() {
+			deadlineWeight += n.similarity
+			offsets = append(offsets, daysBetween(n.item.Task.CreatedAt, n.item.Task.Deadline))
+		}
+	}
+
+	// Tags most similar tasks share, except those already given in any spelling
+	existing := make(map[string]bool, len(parsed.Tags))
+	for _, tag := range parser.NormalizeTags(parsed.Tags, s.tagRules) {
+		existing[tag] = true
+	}
+	for tag, weight := range tagWeights {
+		if !existing[tag] && weight/totalWeight >= minVoteShare {
+			suggestion.Tags = append(suggestion.Tags, tag)
+		}
+	}
+	sort.Strings(suggestion.Tags)
+
+	if parsed.Priority == 0 {
+		// Check higher priorities first so an even split favours urgency
+		for priority := 3; priority >= 1; priority-- {
+			if priorityWeights[priority]/totalWeight >= minVoteShare {
+				suggestion.Priority = priority
+				break
+			}
+		}
+	}
+
+	if parsed.Deadline == nil && deadlineWeight/totalWeight >= minVoteShare {
+		sort.Ints(offsets)
+		suggestion.DeadlineOffset = offsets[len(offsets)/2]
+		suggestion.HasDeadline = true
+	}
+
+	return suggestion, nil
+}
+
+// findSimilar ranks past tasks by TF-IDF cosine similarity of their titles to title
+func findSimilar(title string, items []*TaskItem) []neighbour {
+	query := learning.Keywords(title)
+	if len(query) == 0 || len(items) == 0 {
+		return nil
+	}
+
+	documents := make([][]string, len(items))
+	documentFrequency := make(map[string]int)
+	for i, item := range items {
+		documents[i] = learning.Keywords(item.Task.Title)
+		for _, term := range documents[i] {
+			documentFrequency[term]++
+		}
+	}
+
+	// Smoothed inverse document frequency so terms seen everywhere still count a little
+	idf := func(term string) float64 {
+		return math.Log(float64(len(items)+1)/float64(documentFrequency[term]+1)) + 1
+	}
+
+	queryVector := termVector(query, idf)
+
+	var neighbours []neighbour
+	for i, document := range documents {
+		similarity := cosine(queryVector, termVector(document, idf))
+		if similarity >= minSimilarity {
+			neighbours = append(neighbours, neighbour{item: items[i], similarity: similarity})
+		}
+	}
+
+	sort.SliceStable(neighbours, func(i, j int) bool {
+		return neighbours[i].similarity > neighbours[j].similarity
+	})
+	if len(neighbours) > maxSimilarTasks {
+		neighbours = neighbours[:maxSimilarTasks]
+	}
+
+	return neighbours
+}
+
+// termVector weights each term by its inverse document frequency
+func termVector(terms []string, idf func(string) float64) map[string]float64 {
+	vector := make(map[string]float64, len(terms))
+	for _, term := range terms {
+		vector[term] += idf(term)
+	}
+	return vector
+}
+
+func cosine(a, b map[string]float64) float64 {
+	var dot, normA, normB float64
+	for term, weight := range a {
+		dot += weight * b[term]
+		normA += weight * weight
+	}
+	for _, weight := range b {
+		normB += weight * weight
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// daysBetween counts calendar days from the creation date to the deadline date
+func daysBetween(createdAt, deadline time.Time) int {
+	created := time.Date(createdAt.Year(), createdAt.Month(), createdAt.Day(), 0, 0, 0, 0, time.UTC)
+	due := time.Date(deadline.Year(), deadline.Month(), deadline.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(due.Sub(created).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/parser"
+)
+
+func TestTaskServiceSimilarity(t *testing.T) {
+	newService := func(t *testing.T, inputs ...string) *TaskService {
+		t.Helper()
+		service, err := NewTaskService(t.TempDir())
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		t.Cleanup(func() {
+			_ = service.Close()
+		})
+		for _, input := range inputs {
+			if _, err := service.CreateTaskFromInput(input); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+		}
+		return service
+	}
+
+	t.Run("SuggestFromSimilar", func(t *testing.T) {
+		t.Run("should propose metadata shared by similar past tasks", func(t *testing.T) {
+			// Arrange
+			service := newService(t,
+				"Write weekly status report #work #report !! @tomorrow",
+				"Send weekly status report #work #report !! @tomorrow",
+				"Weekly status report draft #work #report !!",
+				"Buy groceries #home",
+			)
+
+			// Act
+			suggestion, err := service.SuggestFromSimilar("Write weekly status report")
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if suggestion.Matches != 3 {
+				t.Errorf("expected 3 similar tasks, got %d", suggestion.Matches)
+			}
+			if strings.Join(suggestion.Tags, ",") != "report,work" {
+				t.Errorf("expected tags report,work, got %v", suggestion.Tags)
+			}
+			if suggestion.Priority != 2 {
+				t.Errorf("expected priority 2, got %d", suggestion.Priority)
+			}
+			if !suggestion.HasDeadline || suggestion.DeadlineOffset != 1 {
+				t.Errorf("expected deadline offset of 1 day, got %d (%v)", suggestion.DeadlineOffset, suggestion.HasDeadline)
+			}
+		})
+
+		t.Run("should only suggest metadata missing from the input", func(t *testing.T) {
+			// Arrange
+			service := newService(t,
+				"Weekly status report #work #report !!",
+				"Weekly status report #work #report !!",
+			)
+
+			// Act
+			suggestion, err := service.SuggestFromSimilar("Weekly status report #work !!!")
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(suggestion.Tags, ",") != "report" {
+				t.Errorf("expected only the report tag, got %v", suggestion.Tags)
+			}
+			if suggestion.Priority != 0 {
+				t.Errorf("expected no priority suggestion, got %d", suggestion.Priority)
+			}
+		})
+
+		t.Run("should treat given tags as their normalized form", func(t *testing.T) {
+			// Arrange
+			service := newService(t,
+				"Weekly status report #work #report",
+				"Weekly status report #work #report",
+			)
+			service.SetTagRules(parser.TagRules{
+				Lowercase:        true,
+				StripPunctuation: true,
+				Aliases:          map[string]string{"job": "work"},
+			})
+
+			// Act
+			suggestion, err := service.SuggestFromSimilar("Weekly status report #Job, #REPORT")
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(suggestion.Tags) != 0 {
+				t.Errorf("expected no tag suggestions, got %v", suggestion.Tags)
+			}
+		})
+
+		t.Run("should suggest nothing without enough similar tasks", func(t *testing.T) {
+			// Arrange
+			service := newService(t,
+				"Weekly status report #work",
+				"Buy groceries #home",
+			)
+
+			// Act
+			suggestion, err := service.SuggestFromSimilar("Write status report")
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !suggestion.IsEmpty() {
+				t.Errorf("expected empty suggestion, got %+v", suggestion)
+			}
+		})
+	})
+
+	t.Run("Shortcuts", func(t *testing.T) {
+		t.Run("should render suggestion as task shortcuts", func(t *testing.T) {
+			// Arrange
+			suggestion := &SimilarSuggestion{Tags: []string{"report", "work"}, Priority: 2, DeadlineOffset: 3, HasDeadline: true}
+
+			// Act
+			result := suggestion.Shortcuts(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+
+			// Assert
+			expected := "#report #work !! @2024-01-18"
+			if result != expected {
+				t.Errorf("expected %q, got %q", expected, result)
+			}
+		})
+	})
+}
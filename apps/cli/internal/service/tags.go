@@ -0,0 +1,74 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/tennashi/tabler/internal/parser"
+	"github.com/tennashi/tabler/internal/storage"
+)
+
+// ErrInvalidTag is returned for tag names that are empty or contain spaces
+var ErrInvalidTag = errors.New("tags must be a single word")
+
+// ListTags returns every tag in use with its usage, most used first
+func (s *TaskService) ListTags() ([]*storage.TagUsage, error) {
+	return s.storage.ListTagUsage()
+}
+
+// RenameTag renames a tag on every task and returns how many tasks changed
+func (s *TaskService) RenameTag(from, to string) (int, error) {
+	names, err := s.normalizeTagNames(from, to)
+	if err != nil {
+		return 0, err
+	}
+	if names[0] == names[1] {
+		return 0, fmt.Errorf("tag is already called %s", names[1])
+	}
+	return s.storage.RenameTag(names[0], names[1])
+}
+
+// MergeTags replaces the source tags with into on every task, moving their
+// descendants under into, and reports how many tags and tasks changed
+func (s *TaskService) MergeTags(sources []string, into string) (*storage.TagMerge, error) {
+	if len(sources) == 0 {
+		return nil, fmt.Errorf("merge needs at least one tag to merge")
+	}
+	names, err := s.normalizeTagNames(append(slices.Clone(sources), into)...)
+	if err != nil {
+		return nil, err
+	}
+	return s.storage.MergeTags(names[:len(names)-1], names[len(names)-1])
+}
+
+// DeleteTag removes a tag and its descendants from every task and returns how
+// many tasks changed
+func (s *TaskService) DeleteTag(tag string) (int, error) {
+	names, err := s.normalizeTagNames(tag)
+	if err != nil {
+		return 0, err
+	}
+	return s.storage.DeleteTag(names[0])
+}
+
+// NormalizeTags rewrites every stored tag with the current rules and returns
+// how many tags changed
+func (s *TaskService) NormalizeTags() (int, error) {
+	return s.storage.NormalizeTags()
+}
+
+// normalizeTagNames normalizes tag names with the tag rules and rejects names
+// that are not a single word
+func (s *TaskService) normalizeTagNames(tags ...string) ([]string, error) {
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		name := parser.NormalizeTag(tag, s.tagRules)
+		if name == "" || strings.ContainsAny(name, " \t\n") {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTag, tag)
+		}
+		names[i] = name
+	}
+	return names, nil
+}
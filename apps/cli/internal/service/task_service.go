@@ -8,8 +8,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/tennashi/tabler/internal/config"
 	"github.com/tennashi/tabler/internal/metadata"
 	"github.com/tennashi/tabler/internal/parser"
+	"github.com/tennashi/tabler/internal/query"
 	"github.com/tennashi/tabler/internal/storage"
 	"github.com/tennashi/tabler/internal/task"
 )
@@ -17,8 +19,12 @@ import (
 var ErrEmptyTitle = fmt.Errorf("task title cannot be empty")
 
 type TaskService struct {
-	storage  *storage.Storage
-	metadata *metadata.Service
+	storage      *storage.Storage
+	metadata     *metadata.Service
+	excludedTags []string
+	learning     config.Learning
+	urgency      config.Urgency
+	tagRules     parser.TagRules
 }
 
 func NewTaskService(dataDir string) (*TaskService, error) {
@@ -39,6 +45,9 @@ func NewTaskService(dataDir string) (*TaskService, error) {
 	return &TaskService{
 		storage:  store,
 		metadata: nil, // No metadata service by default
+		learning: config.Default().Learning,
+		urgency:  config.Default().Urgency,
+		tagRules: config.Default().Tags.Rules(),
 	}, nil
 }
 
@@ -60,6 +69,9 @@ func NewTaskServiceWithMetadata(dataDir string, metadataService *metadata.Servic
 	return &TaskService{
 		storage:  store,
 		metadata: metadataService,
+		learning: config.Default().Learning,
+		urgency:  config.Default().Urgency,
+		tagRules: config.Default().Tags.Rules(),
 	}, nil
 }
 
@@ -73,23 +85,67 @@ func (s *TaskService) StoreTask(t *task.Task) (string, error) {
 		return "", ErrEmptyTitle
 	}
 
-	// Store the task with empty tags for now
-	// TODO: Extract tags from task when tag support is added to mode handlers
-	if err := s.storage.CreateTask(t, []string{}); err != nil {
+	// Store the task with the tags and subtasks its mode handler assigned
+	if err := s.storage.CreateTaskTree(t); err != nil {
 		return "", err
 	}
+	// Learn tags as they were stored so #Work and #work count as one
+	s.learnFromTask(t, parser.NormalizeTags(t.Tags, s.tagRules))
 
 	return t.ID, nil
 }
 
+// SetExcludedTags sets the tags that keep a task from being sent to the AI provider
+func (s *TaskService) SetExcludedTags(tags []string) {
+	s.excludedTags = tags
+}
+
+// SetTagRules sets how tags are normalized when tasks are saved and filtered
+func (s *TaskService) SetTagRules(rules parser.TagRules) {
+	s.tagRules = rules
+	s.storage.SetTagRules(rules)
+}
+
+// ExcludedTag returns the first of tags that is excluded from AI processing.
+// Excluding a tag also excludes its descendants, ignoring case.
+func (s *TaskService) ExcludedTag(tags []string) (string, bool) {
+	for _, tag := range tags {
+		normalized := strings.ToLower(parser.NormalizeTag(tag, s.tagRules))
+		for _, excluded := range s.excludedTags {
+			excluded = strings.ToLower(parser.NormalizeTag(excluded, s.tagRules))
+			if excluded != "" && parser.TagIncludes(excluded, normalized) {
+				return tag, true
+			}
+		}
+	}
+	return "", false
+}
+
 func (s *TaskService) CreateTaskFromInput(input string) (string, error) {
+	return s.createTaskFromInput(input, false)
+}
+
+// CreatePrivateTaskFromInput creates a task that is never sent to the AI provider
+func (s *TaskService) CreatePrivateTaskFromInput(input string) (string, error) {
+	return s.createTaskFromInput(input, true)
+}
+
+func (s *TaskService) createTaskFromInput(input string, noAI bool) (string, error) {
 	// TODO: Integrate with mode system
 	// For now, keep existing implementation
 	var result *parser.ParseResult
 	var tags []string
 
+	// Tasks carrying an excluded tag stay local even when AI is enabled
+	useAI := s.metadata != nil && !noAI
+	if useAI {
+		if _, excluded := s.ExcludedTag(parser.Parse(input).Tags); excluded {
+			useAI = false
+		}
+	}
+
 	// If we have a metadata service, use it for extraction
-	if s.metadata != nil {
+	if useAI {
 		ctx := context.Background()
 		extracted, err := s.metadata.Extract(ctx, input)
 		if err == nil && extracted != nil {
@@ -152,6 +208,7 @@ func (s *TaskService) CreateTaskFromInput(input string) (string, error) {
 		Deadline:  deadline,
 		Priority:  result.Priority,
 		Completed: false,
+		NoAI:      noAI,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
@@ -160,6 +217,7 @@ func (s *TaskService) CreateTaskFromInput(input string) (string, error) {
 	if err := s.storage.CreateTask(task, tags); err != nil {
 		return "", err
 	}
+	s.learnFromTask(task, parser.NormalizeTags(tags, s.tagRules))
 
 	return taskID, nil
 }
@@ -172,16 +230,42 @@ func (s *TaskService) GetTask(id string) (*task.Task, []string, error) {
 type TaskItem struct {
 	Task *task.Task
 	Tags []string
+	// Blocked is true while a task it depends on is unfinished
+	Blocked bool
 }
 
 type FilterOptions struct {
-	Tag     string
-	Today   bool
-	Overdue bool
+	Tag      string
+	Untagged bool
+	Today    bool
+	Overdue  bool
+	// HideBlocked leaves out tasks waiting on unfinished dependencies
+	HideBlocked bool
+	// Sort is a spec such as "deadline,-priority"; empty lists the newest first
+	Sort string
+	// Query is a filter expression such as "tag:work and not done"
+	Query string
 }
 
 func (s *TaskService) ListTasks(filter *FilterOptions) ([]*TaskItem, error) {
-	tasks, err := s.storage.ListTasks(nil)
+	var where query.Expr
+	var keys []storage.SortKey
+	if filter != nil {
+		var err error
+		if where, err = query.Parse(filter.Query); err != nil {
+			return nil, err
+		}
+		if keys, err = storage.ParseSort(filter.Sort); err != nil {
+			return nil, err
+		}
+	}
+
+	tasks, err := s.storage.ListTasks(where, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked, err := s.storage.BlockedTaskIDs()
 	if err != nil {
 		return nil, err
 	}
@@ -196,11 +280,12 @@ func (s *TaskService) ListTasks(filter *FilterOptions) ([]*TaskItem, error) {
 
 		// Apply filters
 		if filter != nil {
-			// Tag filter
+			// Tag filter, which also matches descendants such as work/clienta for work
 			if filter.Tag != "" {
+				want := parser.NormalizeTag(filter.Tag, s.tagRules)
 				hasTag := false
 				for _, tag := range tags {
-					if tag == filter.Tag {
+					if parser.TagIncludes(want, tag) {
 						hasTag = true
 						break
 					}
@@ -209,11 +294,21 @@ func (s *TaskService) ListTasks(filter *FilterOptions) ([]*TaskItem, error) {
 					continue
 				}
 			}
+
+			// Untagged filter
+			if filter.Untagged && len(tags) > 0 {
+				continue
+			}
+
+			if filter.HideBlocked && blocked[t.ID] {
+				continue
+			}
 		}
 
 		taskItems = append(taskItems, &TaskItem{
-			Task: t,
-			Tags: tags,
+			Task:    t,
+			Tags:    tags,
+			Blocked: blocked[t.ID],
 		})
 	}
 
@@ -256,6 +351,8 @@ func (s *TaskService) UpdateTaskFromInput(id string, input string) error {
 		Deadline:  deadline,
 		Priority:  result.Priority,
 		Completed: existingTask.Completed, // Preserve completion status
+		NoAI:      existingTask.NoAI,      // Preserve AI opt-out
+		Notes:     existingTask.Notes,     // Preserve gathered details
 		CreatedAt: existingTask.CreatedAt, // Preserve creation time
 		UpdatedAt: time.Now(),
 	}
@@ -61,14 +61,78 @@ func TestTaskServiceWithLLM(t *testing.T) {
 			t.Errorf("expected priority 3 (high), got %d", task.Priority)
 		}
 	})
+
+	t.Run("skips LLM for private tasks", func(t *testing.T) {
+		// Arrange
+		claude := &mockClaude{response: &metadata.ExtractedMetadata{CleanedText: "leaked"}}
+		taskService, err := service.NewTaskServiceWithMetadata(t.TempDir(), metadata.NewService(claude))
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		defer func() {
+			_ = taskService.Close()
+		}()
+
+		// Act
+		taskID, err := taskService.CreatePrivateTaskFromInput("see doctor #health")
+		// Assert
+		if err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+		if claude.calls != 0 {
+			t.Errorf("expected no LLM calls, got %d", claude.calls)
+		}
+		task, tags, err := taskService.GetTask(taskID)
+		if err != nil {
+			t.Fatalf("failed to get task: %v", err)
+		}
+		if task.Title != "see doctor" || !task.NoAI {
+			t.Errorf("expected parsed private task, got %q (NoAI=%v)", task.Title, task.NoAI)
+		}
+		if len(tags) != 1 || tags[0] != "health" {
+			t.Errorf("expected tags [health], got %v", tags)
+		}
+	})
+
+	t.Run("skips LLM for tasks with excluded tags", func(t *testing.T) {
+		// Arrange
+		claude := &mockClaude{response: &metadata.ExtractedMetadata{CleanedText: "leaked"}}
+		taskService, err := service.NewTaskServiceWithMetadata(t.TempDir(), metadata.NewService(claude))
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		defer func() {
+			_ = taskService.Close()
+		}()
+		taskService.SetExcludedTags([]string{"#Health"})
+
+		// Act
+		taskID, err := taskService.CreateTaskFromInput("see doctor #health")
+		// Assert
+		if err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+		if claude.calls != 0 {
+			t.Errorf("expected no LLM calls, got %d", claude.calls)
+		}
+		task, _, err := taskService.GetTask(taskID)
+		if err != nil {
+			t.Fatalf("failed to get task: %v", err)
+		}
+		if task.Title != "see doctor" {
+			t.Errorf("expected title %q, got %q", "see doctor", task.Title)
+		}
+	})
 }
 
 type mockClaude struct {
 	response *metadata.ExtractedMetadata
 	err      error
+	calls    int
 }
 
 func (m *mockClaude) ExtractMetadata(_ context.Context, _ string) (*metadata.ExtractedMetadata, error) {
+	m.calls++
 	if m.err != nil {
 		return nil, m.err
 	}
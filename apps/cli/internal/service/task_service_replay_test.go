@@ -0,0 +1,55 @@
+package service_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/claude"
+	"github.com/tennashi/tabler/internal/metadata"
+	"github.com/tennashi/tabler/internal/service"
+)
+
+func TestTaskServiceWithReplayedLLM(t *testing.T) {
+	t.Run("creates task from replayed metadata extraction", func(t *testing.T) {
+		// Arrange
+		client := metadata.NewClaudeClientWithProvider(
+			claude.NewFixtureProvider(filepath.Join("testdata", "ai"), metadata.NewSubprocessProvider()))
+		client.SetClock(func() time.Time {
+			return time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+		})
+
+		taskService, err := service.NewTaskServiceWithMetadata(t.TempDir(), metadata.NewService(client))
+		if err != nil {
+			t.Fatalf("failed to create service: %v", err)
+		}
+		defer func() {
+			_ = taskService.Close()
+		}()
+
+		// Act
+		taskID, err := taskService.CreateTaskFromInput("urgent: finish quarterly report by tomorrow #work")
+		// Assert
+		if err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+
+		task, tags, err := taskService.GetTask(taskID)
+		if err != nil {
+			t.Fatalf("failed to get task: %v", err)
+		}
+		if task.Title != "finish quarterly report" {
+			t.Errorf("expected title %q, got %q", "finish quarterly report", task.Title)
+		}
+		expectedDeadline := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)
+		if !task.Deadline.Equal(expectedDeadline) {
+			t.Errorf("expected deadline %v, got %v", expectedDeadline, task.Deadline)
+		}
+		if task.Priority != 3 {
+			t.Errorf("expected priority 3 (high), got %d", task.Priority)
+		}
+		if len(tags) != 2 || tags[0] != "report" || tags[1] != "work" {
+			t.Errorf("expected tags [report work], got %v", tags)
+		}
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/tennashi/tabler/internal/task"
 )
 
 func TestTaskService(t *testing.T) {
@@ -109,6 +111,50 @@ func TestTaskService(t *testing.T) {
 		})
 	})
 
+	t.Run("StoreTask", func(t *testing.T) {
+		t.Run("should store tags and notes assigned by a mode handler", func(t *testing.T) {
+			// Arrange
+			service, err := NewTaskService(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			defer func() {
+				_ = service.Close()
+			}()
+
+			now := time.Now()
+			handled := &task.Task{
+				ID:        "talk-task",
+				Title:     "Prepare slides",
+				Priority:  2,
+				Notes:     "Audience: team",
+				Tags:      []string{"work"},
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+
+			// Act
+			taskID, err := service.StoreTask(handled)
+			// Assert
+			if err != nil {
+				t.Fatalf("StoreTask() returned error: %v", err)
+			}
+			stored, tags, err := service.GetTask(taskID)
+			if err != nil {
+				t.Fatalf("failed to get stored task: %v", err)
+			}
+			if len(tags) != 1 || tags[0] != "work" {
+				t.Errorf("expected tags [work], got %v", tags)
+			}
+			if stored.Priority != 2 {
+				t.Errorf("expected priority 2, got %d", stored.Priority)
+			}
+			if stored.Notes != "Audience: team" {
+				t.Errorf("expected notes %q, got %q", "Audience: team", stored.Notes)
+			}
+		})
+	})
+
 	t.Run("ListTasks", func(t *testing.T) {
 		t.Run("should list all tasks with their tags", func(t *testing.T) {
 			// Arrange
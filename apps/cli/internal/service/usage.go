@@ -0,0 +1,102 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tennashi/tabler/internal/claude"
+	"github.com/tennashi/tabler/internal/storage"
+)
+
+// UsageSummary aggregates AI calls for a single feature
+type UsageSummary struct {
+	Feature     string
+	Calls       int
+	Failures    int
+	FailureRate float64
+	P50Latency  time.Duration
+	P95Latency  time.Duration
+}
+
+// RecordUsage implements claude.UsageRecorder
+func (s *TaskService) RecordUsage(usage *claude.Usage) error {
+	return s.storage.RecordAIUsage(&storage.AIUsage{
+		Feature:       usage.Feature,
+		StartedAt:     usage.StartedAt,
+		Latency:       usage.Latency,
+		PromptBytes:   usage.PromptBytes,
+		ResponseBytes: usage.ResponseBytes,
+		Success:       usage.Success,
+		Error:         usage.Error,
+	})
+}
+
+// SummarizeAIUsage returns per-feature statistics for AI calls since the given time,
+// followed by a "total" row when any calls were recorded
+func (s *TaskService) SummarizeAIUsage(since time.Time) ([]*UsageSummary, error) {
+	usages, err := s.storage.ListAIUsage(since)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(usages) == 0 {
+		return []*UsageSummary{}, nil
+	}
+
+	byFeature := make(map[string][]*storage.AIUsage)
+	for _, u := range usages {
+		byFeature[u.Feature] = append(byFeature[u.Feature], u)
+	}
+
+	features := make([]string, 0, len(byFeature))
+	for feature := range byFeature {
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+
+	summaries := make([]*UsageSummary, 0, len(features)+1)
+	for _, feature := range features {
+		summaries = append(summaries, summarizeUsage(feature, byFeature[feature]))
+	}
+	summaries = append(summaries, summarizeUsage("total", usages))
+
+	return summaries, nil
+}
+
+// summarizeUsage computes counts, failure rate and latency percentiles
+func summarizeUsage(feature string, usages []*storage.AIUsage) *UsageSummary {
+	summary := &UsageSummary{
+		Feature: feature,
+		Calls:   len(usages),
+	}
+
+	latencies := make([]time.Duration, 0, len(usages))
+	for _, u := range usages {
+		if !u.Success {
+			summary.Failures++
+		}
+		latencies = append(latencies, u.Latency)
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	summary.FailureRate = float64(summary.Failures) / float64(summary.Calls)
+	summary.P50Latency = percentile(latencies, 50)
+	summary.P95Latency = percentile(latencies, 95)
+
+	return summary
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
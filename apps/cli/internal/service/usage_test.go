@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/claude"
+)
+
+func TestTaskServiceAIUsage(t *testing.T) {
+	t.Run("SummarizeAIUsage", func(t *testing.T) {
+		t.Run("should compute failure rate and latency percentiles per feature", func(t *testing.T) {
+			// Arrange
+			service, err := NewTaskService(t.TempDir())
+			if err != nil {
+				t.Fatalf("failed to create service: %v", err)
+			}
+			defer func() {
+				_ = service.Close()
+			}()
+
+			now := time.Now()
+			for i := 1; i <= 10; i++ {
+				usage := &claude.Usage{
+					Feature:   claude.FeatureMetadata,
+					StartedAt: now,
+					Latency:   time.Duration(i) * 100 * time.Millisecond,
+					Success:   i != 10,
+				}
+				if err := service.RecordUsage(usage); err != nil {
+					t.Fatalf("failed to record usage: %v", err)
+				}
+			}
+			old := &claude.Usage{Feature: claude.FeatureDecomposition, StartedAt: now.AddDate(0, 0, -30), Success: true}
+			if err := service.RecordUsage(old); err != nil {
+				t.Fatalf("failed to record usage: %v", err)
+			}
+
+			// Act
+			summaries, err := service.SummarizeAIUsage(now.AddDate(0, 0, -7))
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(summaries) != 2 {
+				t.Fatalf("expected metadata and total rows, got %d", len(summaries))
+			}
+
+			metadata := summaries[0]
+			if metadata.Feature != claude.FeatureMetadata {
+				t.Errorf("expected feature %q, got %q", claude.FeatureMetadata, metadata.Feature)
+			}
+			if metadata.Calls != 10 || metadata.Failures != 1 {
+				t.Errorf("expected 10 calls and 1 failure, got %d and %d", metadata.Calls, metadata.Failures)
+			}
+			if metadata.FailureRate != 0.1 {
+				t.Errorf("expected failure rate 0.1, got %v", metadata.FailureRate)
+			}
+			if metadata.P50Latency != 500*time.Millisecond {
+				t.Errorf("expected p50 500ms, got %v", metadata.P50Latency)
+			}
+			if metadata.P95Latency != time.Second {
+				t.Errorf("expected p95 1s, got %v", metadata.P95Latency)
+			}
+			if summaries[1].Feature != "total" || summaries[1].Calls != 10 {
+				t.Errorf("unexpected total row: %+v", summaries[1])
+			}
+		})
+	})
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tennashi/tabler/internal/query"
+	"github.com/tennashi/tabler/internal/storage"
+)
+
+// ErrInvalidViewName is returned for view names that are empty or contain spaces
+var ErrInvalidViewName = errors.New("view names must be a single word")
+
+// SaveView stores a named filter after checking its query and sort spec
+func (s *TaskService) SaveView(name, filter, sort, output string) error {
+	if name == "" || strings.ContainsAny(name, " \t\n") {
+		return ErrInvalidViewName
+	}
+	if _, err := query.Parse(filter); err != nil {
+		return err
+	}
+	if _, err := storage.ParseSort(sort); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return s.storage.SaveView(&storage.View{
+		Name:      name,
+		Query:     filter,
+		Sort:      sort,
+		Output:    output,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+}
+
+// GetView returns a saved view
+func (s *TaskService) GetView(name string) (*storage.View, error) {
+	view, err := s.storage.GetView(name)
+	if err != nil {
+		return nil, fmt.Errorf("view %s: %w", name, err)
+	}
+	return view, nil
+}
+
+// ListViews returns saved views sorted by name
+func (s *TaskService) ListViews() ([]*storage.View, error) {
+	return s.storage.ListViews()
+}
+
+// DeleteView removes a saved view
+func (s *TaskService) DeleteView(name string) error {
+	return s.storage.DeleteView(name)
+}
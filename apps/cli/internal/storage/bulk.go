@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/tennashi/tabler/internal/parser"
+)
+
+// CompleteTasks marks tasks completed in one transaction. Nothing changes if
+// any of them does not exist.
+func (s *Storage) CompleteTasks(ids []string) error {
+	now := time.Now().UTC().Unix()
+	return s.updateEach(ids, func(tx *sql.Tx, id string) (sql.Result, error) {
+		return tx.Exec(`UPDATE tasks SET completed = 1, updated_at = ? WHERE id = ?`, now, id)
+	})
+}
+
+// SetTasksPriority sets the priority of tasks in one transaction
+func (s *Storage) SetTasksPriority(ids []string, priority int) error {
+	now := time.Now().UTC().Unix()
+	return s.updateEach(ids, func(tx *sql.Tx, id string) (sql.Result, error) {
+		return tx.Exec(`UPDATE tasks SET priority = ?, updated_at = ? WHERE id = ?`, priority, now, id)
+	})
+}
+
+// TagTasks adds and removes tags on tasks in one transaction
+func (s *Storage) TagTasks(ids []string, add, remove []string) error {
+	add, remove = parser.NormalizeTags(add, s.tagRules), parser.NormalizeTags(remove, s.tagRules)
+	now := time.Now().UTC().Unix()
+	return s.updateEach(ids, func(tx *sql.Tx, id string) (sql.Result, error) {
+		for _, tag := range add {
+			if _, err := tx.Exec(`INSERT OR IGNORE INTO task_tags (task_id, tag) VALUES (?, ?)`, id, tag); err != nil {
+				return nil, err
+			}
+		}
+		for _, tag := range remove {
+			if _, err := tx.Exec(`DELETE FROM task_tags WHERE task_id = ? AND tag = ?`, id, tag); err != nil {
+				return nil, err
+			}
+		}
+		return tx.Exec(`UPDATE tasks SET updated_at = ? WHERE id = ?`, now, id)
+	})
+}
+
+// DeleteTasks removes tasks with their tags and dependencies in one transaction
+func (s *Storage) DeleteTasks(ids []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, id := range ids {
+		if err := deleteTask(tx, id); err != nil {
+			return fmt.Errorf("task %s: %w", id, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// updateEach runs update for every task in one transaction and fails with
+// sql.ErrNoRows if a task does not exist
+func (s *Storage) updateEach(ids []string, update func(tx *sql.Tx, id string) (sql.Result, error)) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, id := range ids {
+		result, err := update(tx, id)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("task %s: %w", id, sql.ErrNoRows)
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestStorageBulk(t *testing.T) {
+	newTasks := func(t *testing.T, s *Storage) []string {
+		var ids []string
+		for _, title := range []string{"Archive mail", "Clean desk"} {
+			created := createTestTask(title)
+			if err := s.CreateTask(created, []string{"old", "chores"}); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			ids = append(ids, created.ID)
+		}
+		return ids
+	}
+
+	t.Run("should update every task", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		ids := newTasks(t, s)
+
+		// Act
+		err := s.TagTasks(ids, []string{"cleanup", "old"}, []string{"chores"})
+		if err == nil {
+			err = s.SetTasksPriority(ids, 2)
+		}
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, id := range ids {
+			updated, tags, err := s.GetTask(id)
+			if err != nil {
+				t.Fatalf("failed to get task: %v", err)
+			}
+			slices.Sort(tags)
+			if !slices.Equal(tags, []string{"cleanup", "old"}) {
+				t.Errorf("expected tags [cleanup old], got %v", tags)
+			}
+			if updated.Priority != 2 {
+				t.Errorf("expected priority 2, got %d", updated.Priority)
+			}
+		}
+	})
+
+	t.Run("should change nothing when a task does not exist", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		ids := newTasks(t, s)
+
+		// Act
+		completeErr := s.CompleteTasks(append(ids, "missing"))
+		deleteErr := s.DeleteTasks(append(ids, "missing"))
+
+		// Assert
+		if !errors.Is(completeErr, sql.ErrNoRows) || !errors.Is(deleteErr, sql.ErrNoRows) {
+			t.Fatalf("expected sql.ErrNoRows, got %v and %v", completeErr, deleteErr)
+		}
+		for _, id := range ids {
+			unchanged, _, err := s.GetTask(id)
+			if err != nil {
+				t.Fatalf("expected task to survive the failed delete: %v", err)
+			}
+			if unchanged.Completed {
+				t.Errorf("expected task to stay pending after the failed completion")
+			}
+		}
+	})
+
+	t.Run("should delete every task with its tags", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		ids := newTasks(t, s)
+
+		// Act
+		err := s.DeleteTasks(ids)
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		tasks, err := s.ListTasks(nil, nil)
+		if err != nil {
+			t.Fatalf("failed to list tasks: %v", err)
+		}
+		if len(tasks) != 0 {
+			t.Errorf("expected no tasks, got %d", len(tasks))
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tennashi/tabler/internal/task"
+)
+
+// CycleError is returned when a new dependency would make tasks wait for each other
+type CycleError struct {
+	// Path lists task IDs from the task that would depend back to itself
+	Path []string
+}
+
+// Error implements the error interface
+func (e *CycleError) Error() string {
+	return "dependency would create a cycle: " + strings.Join(e.Path, " -> ")
+}
+
+// AddDependency records that taskID cannot start before dependsOnID is completed.
+// It returns a *CycleError if dependsOnID already waits for taskID.
+func (s *Storage) AddDependency(taskID, dependsOnID string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, id := range []string{taskID, dependsOnID} {
+		var exists int
+		if err := tx.QueryRow(`SELECT 1 FROM tasks WHERE id = ?`, id).Scan(&exists); err != nil {
+			return fmt.Errorf("task %s: %w", id, err)
+		}
+	}
+
+	if taskID == dependsOnID {
+		return &CycleError{Path: []string{taskID, taskID}}
+	}
+
+	// Dependencies never form a cycle, so walking them from dependsOnID terminates
+	query := `
+	WITH RECURSIVE reachable(id, path) AS (
+		SELECT depends_on_id, task_id || ',' || depends_on_id
+		FROM task_dependencies WHERE task_id = ?
+		UNION
+		SELECT d.depends_on_id, reachable.path || ',' || d.depends_on_id
+		FROM task_dependencies d JOIN reachable ON d.task_id = reachable.id
+	)
+	SELECT path FROM reachable WHERE id = ? LIMIT 1
+	`
+	var path string
+	err = tx.QueryRow(query, dependsOnID, taskID).Scan(&path)
+	switch {
+	case err == nil:
+		return &CycleError{Path: append([]string{taskID}, strings.Split(path, ",")...)}
+	case err != sql.ErrNoRows:
+		return err
+	}
+
+	insert := `INSERT OR IGNORE INTO task_dependencies (task_id, depends_on_id) VALUES (?, ?)`
+	if _, err := tx.Exec(insert, taskID, dependsOnID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RemoveDependency forgets that taskID waits for dependsOnID
+func (s *Storage) RemoveDependency(taskID, dependsOnID string) error {
+	query := `DELETE FROM task_dependencies WHERE task_id = ? AND depends_on_id = ?`
+	result, err := s.db.Exec(query, taskID, dependsOnID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Blockers returns the unfinished tasks taskID depends on
+func (s *Storage) Blockers(taskID string) ([]*task.Task, error) {
+	query := `
+	SELECT t.id, t.title, t.deadline, t.priority, t.completed, t.no_ai, t.notes,
+		t.effort_minutes, t.plan_order, t.created_at, t.updated_at
+	FROM task_dependencies d
+	JOIN tasks t ON t.id = d.depends_on_id
+	WHERE d.task_id = ? AND t.completed = 0
+	ORDER BY t.created_at ASC, t.plan_order ASC, t.id ASC
+	`
+	return s.queryTasks(query, taskID)
+}
+
+// Dependents returns the tasks that depend on taskID
+func (s *Storage) Dependents(taskID string) ([]*task.Task, error) {
+	query := `
+	SELECT t.id, t.title, t.deadline, t.priority, t.completed, t.no_ai, t.notes,
+		t.effort_minutes, t.plan_order, t.created_at, t.updated_at
+	FROM task_dependencies d
+	JOIN tasks t ON t.id = d.task_id
+	WHERE d.depends_on_id = ?
+	ORDER BY t.created_at ASC, t.plan_order ASC, t.id ASC
+	`
+	return s.queryTasks(query, taskID)
+}
+
+// BlockedTaskIDs returns the IDs of tasks with at least one unfinished dependency
+func (s *Storage) BlockedTaskIDs() (map[string]bool, error) {
+	query := `
+	SELECT DISTINCT d.task_id
+	FROM task_dependencies d
+	JOIN tasks dependency ON dependency.id = d.depends_on_id
+	WHERE dependency.completed = 0
+	`
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	blocked := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		blocked[id] = true
+	}
+	return blocked, rows.Err()
+}
+
+// WaitingTaskIDs returns the IDs of tasks that cannot be worked on yet because
+// a dependency or a subtask is unfinished
+func (s *Storage) WaitingTaskIDs() (map[string]bool, error) {
+	blocked, err := s.BlockedTaskIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT DISTINCT parent_task_id FROM tasks WHERE parent_task_id IS NOT NULL AND completed = 0`
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		blocked[id] = true
+	}
+	return blocked, rows.Err()
+}
+
+// ActionableTasks returns pending tasks that can be worked on now: every task they
+// depend on is completed and they have no pending subtasks. The most important
+// come first: higher priority, then earlier deadline (none last), then older plans,
+// then plan order.
+func (s *Storage) ActionableTasks() ([]*task.Task, error) {
+	query := `
+	SELECT t.id, t.title, t.deadline, t.priority, t.completed, t.no_ai, t.notes,
+		t.effort_minutes, t.plan_order, t.created_at, t.updated_at
+	FROM tasks t
+	WHERE t.completed = 0
+	AND NOT EXISTS (
+		SELECT 1 FROM task_dependencies d
+		JOIN tasks dependency ON dependency.id = d.depends_on_id
+		WHERE d.task_id = t.id AND dependency.completed = 0
+	)
+	AND NOT EXISTS (
+		SELECT 1 FROM tasks child
+		WHERE child.parent_task_id = t.id AND child.completed = 0
+	)
+	ORDER BY t.priority DESC, t.deadline = ? ASC, t.deadline ASC, t.created_at ASC, t.plan_order ASC, t.id ASC
+	`
+
+	return s.queryTasks(query, time.Time{}.Unix())
+}
+
+// queryTasks runs a query selecting the columns of ListTasks
+func (s *Storage) queryTasks(query string, args ...interface{}) ([]*task.Task, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	return scanTasks(rows)
+}
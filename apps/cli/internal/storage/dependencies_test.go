@@ -0,0 +1,245 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/task"
+)
+
+func TestStorageDependencies(t *testing.T) {
+	t.Run("ActionableTasks", func(t *testing.T) {
+		newPlan := func() (*task.Task, []*task.Task) {
+			venue := createTestTask("Book venue")
+			venue.Order = 1
+			venue.Effort = 2 * time.Hour
+			invites := createTestTask("Send invitations")
+			invites.Order = 2
+			invites.DependsOn = []string{venue.ID}
+			catering := createTestTask("Order catering")
+			catering.Order = 3
+			root := createTestTask("Organize offsite")
+			root.Subtasks = []*task.Task{venue, invites, catering}
+			return root, root.Subtasks
+		}
+		titles := func(tasks []*task.Task) []string {
+			var result []string
+			for _, t := range tasks {
+				result = append(result, t.Title)
+			}
+			return result
+		}
+
+		t.Run("should skip blocked tasks and parents with pending subtasks", func(t *testing.T) {
+			// Arrange
+			s := setupTestStorage(t)
+			root, _ := newPlan()
+			if err := s.CreateTaskTree(root); err != nil {
+				t.Fatalf("failed to create plan: %v", err)
+			}
+
+			// Act
+			tasks, err := s.ActionableTasks()
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := []string{"Book venue", "Order catering"}
+			if got := titles(tasks); !slices.Equal(got, expected) {
+				t.Errorf("expected %v, got %v", expected, got)
+			}
+			if tasks[0].Effort != 2*time.Hour || tasks[0].Order != 1 {
+				t.Errorf("expected effort and order to be stored, got %v and %d", tasks[0].Effort, tasks[0].Order)
+			}
+		})
+
+		t.Run("should unblock tasks whose dependencies are completed", func(t *testing.T) {
+			// Arrange
+			s := setupTestStorage(t)
+			root, subtasks := newPlan()
+			if err := s.CreateTaskTree(root); err != nil {
+				t.Fatalf("failed to create plan: %v", err)
+			}
+			if err := s.UpdateTaskCompleted(subtasks[0].ID, true); err != nil {
+				t.Fatalf("failed to complete task: %v", err)
+			}
+
+			// Act
+			tasks, err := s.ActionableTasks()
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := []string{"Send invitations", "Order catering"}
+			if got := titles(tasks); !slices.Equal(got, expected) {
+				t.Errorf("expected %v, got %v", expected, got)
+			}
+		})
+
+		t.Run("should put urgent tasks first", func(t *testing.T) {
+			// Arrange
+			s := setupTestStorage(t)
+			root, _ := newPlan()
+			if err := s.CreateTaskTree(root); err != nil {
+				t.Fatalf("failed to create plan: %v", err)
+			}
+			urgent := createTestTask("Renew passport")
+			urgent.Priority = 3
+			if err := s.CreateTask(urgent, nil); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+
+			// Act
+			tasks, err := s.ActionableTasks()
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(tasks) == 0 || tasks[0].Title != "Renew passport" {
+				t.Errorf("expected the high priority task first, got %v", titles(tasks))
+			}
+		})
+
+		t.Run("should forget dependencies of deleted tasks", func(t *testing.T) {
+			// Arrange
+			s := setupTestStorage(t)
+			root, subtasks := newPlan()
+			if err := s.CreateTaskTree(root); err != nil {
+				t.Fatalf("failed to create plan: %v", err)
+			}
+			if err := s.DeleteTask(subtasks[0].ID); err != nil {
+				t.Fatalf("failed to delete task: %v", err)
+			}
+
+			// Act
+			tasks, err := s.ActionableTasks()
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expected := []string{"Send invitations", "Order catering"}
+			if got := titles(tasks); !slices.Equal(got, expected) {
+				t.Errorf("expected %v, got %v", expected, got)
+			}
+		})
+	})
+
+	t.Run("AddDependency", func(t *testing.T) {
+		newTasks := func(t *testing.T, s *Storage, titles ...string) []*task.Task {
+			var tasks []*task.Task
+			for _, title := range titles {
+				created := createTestTask(title)
+				if err := s.CreateTask(created, nil); err != nil {
+					t.Fatalf("failed to create task: %v", err)
+				}
+				tasks = append(tasks, created)
+			}
+			return tasks
+		}
+
+		t.Run("should block a task until its dependency is completed", func(t *testing.T) {
+			// Arrange
+			s := setupTestStorage(t)
+			tasks := newTasks(t, s, "Code review", "Deploy")
+			review, deploy := tasks[0], tasks[1]
+
+			// Act
+			err := s.AddDependency(deploy.ID, review.ID)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			blockers, err := s.Blockers(deploy.ID)
+			if err != nil {
+				t.Fatalf("failed to get blockers: %v", err)
+			}
+			if len(blockers) != 1 || blockers[0].ID != review.ID {
+				t.Errorf("expected deploy to be blocked by review, got %v", blockers)
+			}
+			blocked, err := s.BlockedTaskIDs()
+			if err != nil {
+				t.Fatalf("failed to get blocked tasks: %v", err)
+			}
+			if !blocked[deploy.ID] || blocked[review.ID] {
+				t.Errorf("expected only deploy to be blocked, got %v", blocked)
+			}
+
+			if err := s.UpdateTaskCompleted(review.ID, true); err != nil {
+				t.Fatalf("failed to complete task: %v", err)
+			}
+			blockers, err = s.Blockers(deploy.ID)
+			if err != nil {
+				t.Fatalf("failed to get blockers: %v", err)
+			}
+			if len(blockers) != 0 {
+				t.Errorf("expected no blockers after completing review, got %v", blockers)
+			}
+		})
+
+		t.Run("should reject dependencies that form a cycle", func(t *testing.T) {
+			// Arrange
+			s := setupTestStorage(t)
+			tasks := newTasks(t, s, "Write code", "Code review", "Deploy")
+			code, review, deploy := tasks[0], tasks[1], tasks[2]
+			if err := s.AddDependency(review.ID, code.ID); err != nil {
+				t.Fatalf("failed to add dependency: %v", err)
+			}
+			if err := s.AddDependency(deploy.ID, review.ID); err != nil {
+				t.Fatalf("failed to add dependency: %v", err)
+			}
+
+			// Act
+			err := s.AddDependency(code.ID, deploy.ID)
+
+			// Assert
+			var cycleErr *CycleError
+			if !errors.As(err, &cycleErr) {
+				t.Fatalf("expected CycleError, got %v", err)
+			}
+			expected := []string{code.ID, deploy.ID, review.ID, code.ID}
+			if !slices.Equal(cycleErr.Path, expected) {
+				t.Errorf("expected cycle %v, got %v", expected, cycleErr.Path)
+			}
+			blockers, err := s.Blockers(code.ID)
+			if err != nil {
+				t.Fatalf("failed to get blockers: %v", err)
+			}
+			if len(blockers) != 0 {
+				t.Errorf("expected the cyclic dependency not to be stored, got %v", blockers)
+			}
+		})
+
+		t.Run("should reject a task depending on itself", func(t *testing.T) {
+			// Arrange
+			s := setupTestStorage(t)
+			tasks := newTasks(t, s, "Deploy")
+
+			// Act
+			err := s.AddDependency(tasks[0].ID, tasks[0].ID)
+
+			// Assert
+			var cycleErr *CycleError
+			if !errors.As(err, &cycleErr) {
+				t.Errorf("expected CycleError, got %v", err)
+			}
+		})
+
+		t.Run("should fail for unknown tasks", func(t *testing.T) {
+			// Arrange
+			s := setupTestStorage(t)
+			tasks := newTasks(t, s, "Deploy")
+
+			// Act
+			err := s.AddDependency(tasks[0].ID, "missing")
+
+			// Assert
+			if !errors.Is(err, sql.ErrNoRows) {
+				t.Errorf("expected sql.ErrNoRows, got %v", err)
+			}
+		})
+	})
+}
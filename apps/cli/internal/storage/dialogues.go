@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/tennashi/tabler/internal/clarification"
+)
+
+// SaveDialogue stores an unfinished clarification session, replacing any earlier save
+func (s *Storage) SaveDialogue(session *clarification.DialogueSession) error {
+	history, err := json.Marshal(session.History)
+	if err != nil {
+		return err
+	}
+	info, err := json.Marshal(session.ExtractedInfo)
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO dialogue_sessions (id, input, original_input, current_question, history, extracted_info, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (id) DO UPDATE SET
+		current_question = excluded.current_question,
+		history = excluded.history,
+		extracted_info = excluded.extracted_info,
+		updated_at = excluded.updated_at
+	`
+	_, err = s.db.Exec(query,
+		session.ID, session.Input, session.OriginalInput, session.CurrentQuestion,
+		string(history), string(info), session.CreatedAt.Unix(), session.UpdatedAt.Unix())
+	return err
+}
+
+// GetDialogue retrieves a saved clarification session
+func (s *Storage) GetDialogue(id string) (*clarification.DialogueSession, error) {
+	query := `
+	SELECT id, input, original_input, current_question, history, extracted_info, created_at, updated_at
+	FROM dialogue_sessions
+	WHERE id = ?
+	`
+	return scanDialogue(s.db.QueryRow(query, id))
+}
+
+// ListDialogues returns saved clarification sessions, most recently updated first
+func (s *Storage) ListDialogues() ([]*clarification.DialogueSession, error) {
+	query := `
+	SELECT id, input, original_input, current_question, history, extracted_info, created_at, updated_at
+	FROM dialogue_sessions
+	ORDER BY updated_at DESC, id DESC
+	`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var sessions []*clarification.DialogueSession
+	for rows.Next() {
+		session, err := scanDialogue(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// DeleteDialogue removes a saved clarification session
+func (s *Storage) DeleteDialogue(id string) error {
+	result, err := s.db.Exec(`DELETE FROM dialogue_sessions WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDialogue(row rowScanner) (*clarification.DialogueSession, error) {
+	var session clarification.DialogueSession
+	var history, info string
+	var createdAt, updatedAt int64
+
+	err := row.Scan(
+		&session.ID, &session.Input, &session.OriginalInput, &session.CurrentQuestion,
+		&history, &info, &createdAt, &updatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal([]byte(history), &session.History); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(info), &session.ExtractedInfo); err != nil {
+		return nil, err
+	}
+	if session.ExtractedInfo == nil {
+		session.ExtractedInfo = make(map[string]string)
+	}
+	session.CreatedAt = time.Unix(createdAt, 0).UTC()
+	session.UpdatedAt = time.Unix(updatedAt, 0).UTC()
+
+	return &session, nil
+}
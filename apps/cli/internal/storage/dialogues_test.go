@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/clarification"
+)
+
+func TestStorageDialogues(t *testing.T) {
+	newSession := func(id string, updatedAt time.Time) *clarification.DialogueSession {
+		return &clarification.DialogueSession{
+			ID:              id,
+			Input:           "prepare the thing #work",
+			OriginalInput:   "prepare the thing",
+			CurrentQuestion: "When is it due?",
+			History: []clarification.Exchange{
+				{Question: "What do you need to prepare?", Answer: "slides"},
+				{Question: "When is it due?"},
+			},
+			ExtractedInfo: map[string]string{"what": "slides"},
+			CreatedAt:     updatedAt,
+			UpdatedAt:     updatedAt,
+		}
+	}
+
+	t.Run("should round-trip a saved session and update it in place", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		started := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		session := newSession("dlg-1", started)
+		if err := s.SaveDialogue(session); err != nil {
+			t.Fatalf("failed to save dialogue: %v", err)
+		}
+		session.History[1].Answer = "Friday"
+		session.UpdatedAt = started.Add(time.Minute)
+
+		// Act
+		if err := s.SaveDialogue(session); err != nil {
+			t.Fatalf("failed to update dialogue: %v", err)
+		}
+		loaded, err := s.GetDialogue("dlg-1")
+		// Assert
+		if err != nil {
+			t.Fatalf("failed to get dialogue: %v", err)
+		}
+		if loaded.Input != "prepare the thing #work" || loaded.CurrentQuestion != "When is it due?" {
+			t.Errorf("unexpected session %+v", *loaded)
+		}
+		if len(loaded.History) != 2 || loaded.History[1].Answer != "Friday" {
+			t.Errorf("expected updated history, got %+v", loaded.History)
+		}
+		if loaded.ExtractedInfo["what"] != "slides" {
+			t.Errorf("expected extracted info to be kept, got %v", loaded.ExtractedInfo)
+		}
+		if !loaded.CreatedAt.Equal(started) || !loaded.UpdatedAt.Equal(session.UpdatedAt) {
+			t.Errorf("unexpected timestamps %v / %v", loaded.CreatedAt, loaded.UpdatedAt)
+		}
+	})
+
+	t.Run("should list most recently updated sessions first and delete them", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		started := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		for i, id := range []string{"dlg-old", "dlg-new"} {
+			if err := s.SaveDialogue(newSession(id, started.Add(time.Duration(i)*time.Hour))); err != nil {
+				t.Fatalf("failed to save dialogue: %v", err)
+			}
+		}
+
+		// Act
+		sessions, err := s.ListDialogues()
+		if err != nil {
+			t.Fatalf("failed to list dialogues: %v", err)
+		}
+		deleteErr := s.DeleteDialogue("dlg-new")
+		_, getErr := s.GetDialogue("dlg-new")
+
+		// Assert
+		if len(sessions) != 2 || sessions[0].ID != "dlg-new" {
+			t.Errorf("expected dlg-new first, got %d sessions", len(sessions))
+		}
+		if deleteErr != nil {
+			t.Errorf("failed to delete dialogue: %v", deleteErr)
+		}
+		if !errors.Is(getErr, sql.ErrNoRows) {
+			t.Errorf("expected deleted dialogue to be gone, got %v", getErr)
+		}
+	})
+}
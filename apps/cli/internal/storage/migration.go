@@ -19,6 +19,54 @@ func (s *Storage) RunMigrations() error {
 		}
 	}
 
+	if version < 2 {
+		if err := s.migrateTo2(); err != nil {
+			return fmt.Errorf("failed to migrate to version 2: %w", err)
+		}
+	}
+
+	if version < 3 {
+		if err := s.migrateTo3(); err != nil {
+			return fmt.Errorf("failed to migrate to version 3: %w", err)
+		}
+	}
+
+	if version < 4 {
+		if err := s.migrateTo4(); err != nil {
+			return fmt.Errorf("failed to migrate to version 4: %w", err)
+		}
+	}
+
+	if version < 5 {
+		if err := s.migrateTo5(); err != nil {
+			return fmt.Errorf("failed to migrate to version 5: %w", err)
+		}
+	}
+
+	if version < 6 {
+		if err := s.migrateTo6(); err != nil {
+			return fmt.Errorf("failed to migrate to version 6: %w", err)
+		}
+	}
+
+	if version < 7 {
+		if err := s.migrateTo7(); err != nil {
+			return fmt.Errorf("failed to migrate to version 7: %w", err)
+		}
+	}
+
+	if version < 8 {
+		if err := s.migrateTo8(); err != nil {
+			return fmt.Errorf("failed to migrate to version 8: %w", err)
+		}
+	}
+
+	if version < 9 {
+		if err := s.migrateTo9(); err != nil {
+			return fmt.Errorf("failed to migrate to version 9: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -34,9 +82,9 @@ func (s *Storage) getSchemaVersion() (int, error) {
 		return 0, err
 	}
 
-	// Get current version
+	// Get current version (each migration adds a row, so take the highest)
 	var version int
-	err := s.db.QueryRow("SELECT version FROM schema_version LIMIT 1").Scan(&version)
+	err := s.db.QueryRow("SELECT COALESCE(MAX(version), 0) FROM schema_version").Scan(&version)
 	if err != nil {
 		// No version yet, this is version 0
 		return 0, nil
@@ -69,3 +117,223 @@ func (s *Storage) migrateTo1() error {
 
 	return tx.Commit()
 }
+
+// migrateTo2 adds the ai_usage table for AI call accounting
+func (s *Storage) migrateTo2() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+	CREATE TABLE IF NOT EXISTS ai_usage (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		feature TEXT NOT NULL,
+		started_at INTEGER NOT NULL,
+		latency_ms INTEGER NOT NULL,
+		prompt_bytes INTEGER NOT NULL,
+		response_bytes INTEGER NOT NULL,
+		success INTEGER NOT NULL,
+		error TEXT
+	);
+	CREATE INDEX IF NOT EXISTS idx_ai_usage_started_at ON ai_usage(started_at);
+	`
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("INSERT OR REPLACE INTO schema_version (version) VALUES (2)"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrateTo3 adds the no_ai column for tasks that must never be sent to the AI provider
+func (s *Storage) migrateTo3() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+	ALTER TABLE tasks ADD COLUMN no_ai INTEGER DEFAULT 0;
+	`
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("INSERT OR REPLACE INTO schema_version (version) VALUES (3)"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrateTo4 adds the learned_patterns table for context learning
+func (s *Storage) migrateTo4() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+	CREATE TABLE IF NOT EXISTS learned_patterns (
+		pattern_type TEXT NOT NULL,
+		pattern_key TEXT NOT NULL,
+		pattern_value TEXT NOT NULL DEFAULT '',
+		frequency INTEGER NOT NULL DEFAULT 0,
+		first_seen INTEGER NOT NULL,
+		last_used INTEGER NOT NULL,
+		PRIMARY KEY (pattern_type, pattern_key, pattern_value)
+	);
+	`
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("INSERT OR REPLACE INTO schema_version (version) VALUES (4)"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrateTo5 adds the notes column for details gathered while creating a task
+func (s *Storage) migrateTo5() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+	ALTER TABLE tasks ADD COLUMN notes TEXT NOT NULL DEFAULT '';
+	`
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("INSERT OR REPLACE INTO schema_version (version) VALUES (5)"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrateTo6 adds the dialogue_sessions table for resumable clarification dialogues
+func (s *Storage) migrateTo6() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+	CREATE TABLE IF NOT EXISTS dialogue_sessions (
+		id TEXT PRIMARY KEY,
+		input TEXT NOT NULL,
+		original_input TEXT NOT NULL,
+		current_question TEXT NOT NULL DEFAULT '',
+		history TEXT NOT NULL DEFAULT '[]',
+		extracted_info TEXT NOT NULL DEFAULT '{}',
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+	`
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("INSERT OR REPLACE INTO schema_version (version) VALUES (6)"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrateTo7 adds effort estimates and plan order
+func (s *Storage) migrateTo7() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	queries := []string{
+		`ALTER TABLE tasks ADD COLUMN effort_minutes INTEGER NOT NULL DEFAULT 0`,
+		`ALTER TABLE tasks ADD COLUMN plan_order INTEGER NOT NULL DEFAULT 0`,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("INSERT OR REPLACE INTO schema_version (version) VALUES (7)"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrateTo8 adds task dependencies
+func (s *Storage) migrateTo8() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS task_dependencies (
+			task_id TEXT NOT NULL REFERENCES tasks(id),
+			depends_on_id TEXT NOT NULL REFERENCES tasks(id),
+			PRIMARY KEY (task_id, depends_on_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_task_dependencies_depends_on ON task_dependencies(depends_on_id)`,
+	}
+	for _, query := range queries {
+		if _, err := tx.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec("INSERT OR REPLACE INTO schema_version (version) VALUES (8)"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// migrateTo9 adds saved views
+func (s *Storage) migrateTo9() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `
+	CREATE TABLE IF NOT EXISTS saved_views (
+		name TEXT PRIMARY KEY,
+		query TEXT NOT NULL DEFAULT '',
+		sort TEXT NOT NULL DEFAULT '',
+		output TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	)
+	`
+	if _, err := tx.Exec(query); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("INSERT OR REPLACE INTO schema_version (version) VALUES (9)"); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
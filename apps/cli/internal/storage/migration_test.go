@@ -49,5 +49,24 @@ func TestMigrations(t *testing.T) {
 				t.Error("expected parent_task_id column to exist")
 			}
 		})
+
+		t.Run("should record latest schema version once", func(t *testing.T) {
+			// Arrange
+			s := setupTestStorage(t)
+
+			// Act - running migrations again must be a no-op
+			if err := s.RunMigrations(); err != nil {
+				t.Fatalf("failed to rerun migrations: %v", err)
+			}
+
+			// Assert
+			version, err := s.getSchemaVersion()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version != 9 {
+				t.Errorf("expected schema version 9, got %d", version)
+			}
+		})
 	})
 }
@@ -56,6 +56,64 @@ func TestStorageParentChild(t *testing.T) {
 		})
 	})
 
+	t.Run("CreateTaskTree", func(t *testing.T) {
+		t.Run("should store every level under its parent", func(t *testing.T) {
+			// Arrange
+			s := setupTestStorage(t)
+			grandchild := createTestTask("Compare venue quotes")
+			child := createTestTask("Book venue")
+			child.Tags = []string{"venue"}
+			child.Subtasks = []*task.Task{grandchild}
+			root := createTestTask("Organize conference")
+			root.Subtasks = []*task.Task{child, createTestTask("Invite speakers")}
+
+			// Act
+			err := s.CreateTaskTree(root)
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			children, err := s.GetChildren(root.ID)
+			if err != nil {
+				t.Fatalf("failed to get children: %v", err)
+			}
+			if len(children) != 2 {
+				t.Errorf("expected 2 children, got %d", len(children))
+			}
+			parent, err := s.GetParent(grandchild.ID)
+			if err != nil || parent == nil || parent.ID != child.ID {
+				t.Errorf("expected grandchild under %q, got %v (%v)", child.ID, parent, err)
+			}
+			_, tags, err := s.GetTask(child.ID)
+			if err != nil || len(tags) != 1 || tags[0] != "venue" {
+				t.Errorf("expected child tags [venue], got %v (%v)", tags, err)
+			}
+		})
+
+		t.Run("should store nothing when a subtask fails", func(t *testing.T) {
+			// Arrange
+			s := setupTestStorage(t)
+			existing := createTestTask("Existing task")
+			if err := s.CreateTask(existing, nil); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			root := createTestTask("Organize conference")
+			// Reusing an ID violates the primary key
+			root.Subtasks = []*task.Task{createTestTask("Book venue"), {ID: existing.ID, Title: "Duplicate"}}
+
+			// Act
+			err := s.CreateTaskTree(root)
+
+			// Assert
+			if err == nil {
+				t.Fatal("expected error for duplicate subtask ID")
+			}
+			if _, _, err := s.GetTask(root.ID); err == nil {
+				t.Error("expected the root task to be rolled back")
+			}
+		})
+	})
+
 	t.Run("GetChildren", func(t *testing.T) {
 		t.Run("should retrieve all children of a parent task", func(t *testing.T) {
 			// Arrange
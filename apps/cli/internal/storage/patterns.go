@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/tennashi/tabler/internal/learning"
+)
+
+// RecordPatterns counts one more use of each pattern at the given time
+func (s *Storage) RecordPatterns(patterns []*learning.Pattern, at time.Time) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	query := `
+	INSERT INTO learned_patterns (pattern_type, pattern_key, pattern_value, frequency, first_seen, last_used)
+	VALUES (?, ?, ?, 1, ?, ?)
+	ON CONFLICT (pattern_type, pattern_key, pattern_value)
+	DO UPDATE SET frequency = frequency + 1, last_used = excluded.last_used
+	`
+	for _, p := range patterns {
+		if _, err := tx.Exec(query, p.Type, p.Key, p.Value, at.Unix(), at.Unix()); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ListPatterns returns learned patterns of a type (all types when empty), most used first
+func (s *Storage) ListPatterns(patternType string) ([]*learning.Pattern, error) {
+	query := `
+	SELECT pattern_type, pattern_key, pattern_value, frequency, first_seen, last_used
+	FROM learned_patterns
+	WHERE ? = '' OR pattern_type = ?
+	ORDER BY frequency DESC, last_used DESC, pattern_type, pattern_key, pattern_value
+	`
+
+	rows, err := s.db.Query(query, patternType, patternType)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var patterns []*learning.Pattern
+	for rows.Next() {
+		var p learning.Pattern
+		var firstSeen, lastUsed int64
+
+		if err := rows.Scan(&p.Type, &p.Key, &p.Value, &p.Count, &firstSeen, &lastUsed); err != nil {
+			return nil, err
+		}
+
+		p.FirstSeen = time.Unix(firstSeen, 0).UTC()
+		p.LastUsed = time.Unix(lastUsed, 0).UTC()
+
+		patterns = append(patterns, &p)
+	}
+
+	return patterns, rows.Err()
+}
+
+// DeletePatterns removes every learned pattern and returns how many were removed
+func (s *Storage) DeletePatterns() (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM learned_patterns`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeletePatternsUnusedSince removes patterns not used since cutoff
+func (s *Storage) DeletePatternsUnusedSince(cutoff time.Time) (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM learned_patterns WHERE last_used < ?`, cutoff.Unix())
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/learning"
+)
+
+func TestStoragePatterns(t *testing.T) {
+	t.Run("should count repeated patterns and keep first and last use", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		first := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+		second := first.AddDate(0, 0, 1)
+		work := []*learning.Pattern{{Type: learning.TypeTag, Key: "work"}}
+
+		// Act
+		if err := s.RecordPatterns(work, first); err != nil {
+			t.Fatalf("failed to record patterns: %v", err)
+		}
+		if err := s.RecordPatterns(work, second); err != nil {
+			t.Fatalf("failed to record patterns: %v", err)
+		}
+		patterns, err := s.ListPatterns(learning.TypeTag)
+		// Assert
+		if err != nil {
+			t.Fatalf("failed to list patterns: %v", err)
+		}
+		if len(patterns) != 1 {
+			t.Fatalf("expected 1 pattern, got %d", len(patterns))
+		}
+		p := patterns[0]
+		if p.Count != 2 || !p.FirstSeen.Equal(first) || !p.LastUsed.Equal(second) {
+			t.Errorf("unexpected pattern %+v", *p)
+		}
+	})
+
+	t.Run("should delete patterns unused since cutoff", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		now := time.Now().UTC()
+		if err := s.RecordPatterns([]*learning.Pattern{{Type: learning.TypeTag, Key: "old"}}, now.AddDate(0, 0, -100)); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.RecordPatterns([]*learning.Pattern{{Type: learning.TypeTag, Key: "new"}}, now); err != nil {
+			t.Fatal(err)
+		}
+
+		// Act
+		removed, err := s.DeletePatternsUnusedSince(now.AddDate(0, 0, -90))
+		// Assert
+		if err != nil {
+			t.Fatalf("failed to delete patterns: %v", err)
+		}
+		if removed != 1 {
+			t.Errorf("expected 1 removed pattern, got %d", removed)
+		}
+		patterns, _ := s.ListPatterns("")
+		if len(patterns) != 1 || patterns[0].Key != "new" {
+			t.Errorf("expected only the new pattern to remain, got %d", len(patterns))
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tennashi/tabler/internal/parser"
+	"github.com/tennashi/tabler/internal/query"
+)
+
+// whereClause builds a WHERE clause matching tasks against a filter expression.
+// A nil expression matches every task. Tag values are normalized with rules.
+func whereClause(expr query.Expr, rules parser.TagRules) (string, []interface{}, error) {
+	if expr == nil {
+		return "", nil, nil
+	}
+
+	condition, args, err := compileQuery(expr, rules)
+	if err != nil {
+		return "", nil, err
+	}
+	return "WHERE " + condition, args, nil
+}
+
+// compileQuery turns a filter expression into an SQL condition on the tasks table
+func compileQuery(expr query.Expr, rules parser.TagRules) (string, []interface{}, error) {
+	switch e := expr.(type) {
+	case *query.And:
+		return compileBinary("AND", e.Left, e.Right, rules)
+	case *query.Or:
+		return compileBinary("OR", e.Left, e.Right, rules)
+	case *query.Not:
+		condition, args, err := compileQuery(e.Expr, rules)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + condition + ")", args, nil
+	case *query.Condition:
+		return compileCondition(e, rules)
+	default:
+		return "", nil, fmt.Errorf("unsupported query expression %T", expr)
+	}
+}
+
+func compileBinary(operator string, left, right query.Expr, rules parser.TagRules) (string, []interface{}, error) {
+	leftCondition, leftArgs, err := compileQuery(left, rules)
+	if err != nil {
+		return "", nil, err
+	}
+	rightCondition, rightArgs, err := compileQuery(right, rules)
+	if err != nil {
+		return "", nil, err
+	}
+	return "(" + leftCondition + " " + operator + " " + rightCondition + ")", append(leftArgs, rightArgs...), nil
+}
+
+// compileCondition handles a single comparison; != is compiled as the negation of =.
+// A tag condition also matches the descendants of the tag, so tag:work finds work/clienta.
+func compileCondition(c *query.Condition, rules parser.TagRules) (string, []interface{}, error) {
+	if c.Op == query.OpNotEqual {
+		equal := *c
+		equal.Op = query.OpEqual
+		condition, args, err := compileCondition(&equal, rules)
+		if err != nil {
+			return "", nil, err
+		}
+		return "NOT (" + condition + ")", args, nil
+	}
+
+	noDeadline := time.Time{}.Unix()
+	switch c.Field {
+	case query.FieldTag:
+		condition, args := tagTree("tt.tag", parser.NormalizeTag(c.Value, rules))
+		return `EXISTS (SELECT 1 FROM task_tags tt WHERE tt.task_id = tasks.id AND ` + condition + `)`, args, nil
+	case query.FieldTitle:
+		return `tasks.title LIKE ? ESCAPE '\'`, []interface{}{"%" + escapeLike(c.Value) + "%"}, nil
+	case query.FieldPriority:
+		return "tasks.priority " + string(c.Op) + " ?", []interface{}{c.Number}, nil
+	case query.FieldDue:
+		if c.Value == query.DueNone {
+			return "tasks.deadline = ?", []interface{}{noDeadline}, nil
+		}
+		day, nextDay := c.Date.Unix(), c.Date.AddDate(0, 0, 1).Unix()
+		switch c.Op {
+		case query.OpEqual:
+			return "(tasks.deadline >= ? AND tasks.deadline < ?)", []interface{}{day, nextDay}, nil
+		case query.OpLess:
+			return "(tasks.deadline != ? AND tasks.deadline < ?)", []interface{}{noDeadline, day}, nil
+		case query.OpLessEqual:
+			return "(tasks.deadline != ? AND tasks.deadline < ?)", []interface{}{noDeadline, nextDay}, nil
+		case query.OpGreater:
+			return "tasks.deadline >= ?", []interface{}{nextDay}, nil
+		case query.OpGreaterEqual:
+			return "tasks.deadline >= ?", []interface{}{day}, nil
+		}
+	case query.FieldStatus:
+		switch c.Value {
+		case query.StatusDone:
+			return "tasks.completed = 1", nil, nil
+		case query.StatusPending:
+			return "tasks.completed = 0", nil, nil
+		case query.StatusBlocked:
+			return `(tasks.completed = 0 AND EXISTS (
+				SELECT 1 FROM task_dependencies d
+				JOIN tasks dependency ON dependency.id = d.depends_on_id
+				WHERE d.task_id = tasks.id AND dependency.completed = 0
+			))`, nil, nil
+		}
+	}
+	return "", nil, fmt.Errorf("unsupported condition %s", c)
+}
+
+// escapeLike makes LIKE match wildcard characters literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
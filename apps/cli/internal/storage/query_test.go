@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/query"
+)
+
+func TestStorageListTasksFiltered(t *testing.T) {
+	newStorage := func(t *testing.T) *Storage {
+		s := setupTestStorage(t)
+		base := time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)
+		tasks := []struct {
+			title     string
+			tags      []string
+			priority  int
+			deadline  time.Time
+			completed bool
+		}{
+			{"Write report", []string{"work"}, 2, base.AddDate(0, 0, 2), false},
+			{"Review 100% coverage", []string{"work"}, 0, time.Time{}, false},
+			{"Ship release", []string{"work"}, 3, base.AddDate(0, 0, 10), true},
+			{"Water plants", []string{"home"}, 1, base, false},
+		}
+		ids := make(map[string]string)
+		for i, tt := range tasks {
+			created := createTestTask(tt.title)
+			created.Priority = tt.priority
+			created.Deadline = tt.deadline
+			created.Completed = tt.completed
+			created.CreatedAt = base.Add(time.Duration(i) * time.Hour)
+			if err := s.CreateTask(created, tt.tags); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			ids[tt.title] = created.ID
+		}
+		if err := s.AddDependency(ids["Write report"], ids["Water plants"]); err != nil {
+			t.Fatalf("failed to add dependency: %v", err)
+		}
+		return s
+	}
+
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{"tag:work", []string{"Write report", "Review 100% coverage", "Ship release"}},
+		{"tag:work and (priority>=2 or due<2030-05-03) and not done", []string{"Write report"}},
+		{"due<=2030-05-03", []string{"Write report", "Water plants"}},
+		{"due>2030-05-03", []string{"Ship release"}},
+		{"due:2030-05-01", []string{"Water plants"}},
+		{"due:none", []string{"Review 100% coverage"}},
+		{"due!=2030-05-01", []string{"Write report", "Review 100% coverage", "Ship release"}},
+		{"title:100%", []string{"Review 100% coverage"}},
+		{"title:WATER", []string{"Water plants"}},
+		{"blocked", []string{"Write report"}},
+		{"tag!=work pending", []string{"Water plants"}},
+		{"priority<1 or priority>2", []string{"Review 100% coverage", "Ship release"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("should match "+tt.expr, func(t *testing.T) {
+			// Arrange
+			s := newStorage(t)
+			expr, err := query.Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("failed to parse query: %v", err)
+			}
+
+			// Act
+			tasks, err := s.ListTasks(expr, []SortKey{{Field: "created"}})
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var titles []string
+			for _, listed := range tasks {
+				titles = append(titles, listed.Title)
+			}
+			if !slices.Equal(titles, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, titles)
+			}
+		})
+	}
+}
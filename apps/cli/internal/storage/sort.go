@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sortColumns maps the sort fields users can name to task columns
+var sortColumns = map[string]string{
+	"created":  "created_at",
+	"updated":  "updated_at",
+	"deadline": "deadline",
+	"priority": "priority",
+	"title":    "title COLLATE NOCASE",
+	"status":   "completed",
+}
+
+// SortKey orders tasks by one field
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+// ParseSort reads a comma separated sort spec such as "deadline,-priority,title",
+// where a leading '-' sorts that field in descending order
+func ParseSort(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, field := range strings.Split(spec, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		if field == "" {
+			continue
+		}
+
+		key := SortKey{Field: strings.TrimPrefix(field, "-"), Descending: strings.HasPrefix(field, "-")}
+		if _, ok := sortColumns[key.Field]; !ok {
+			return nil, fmt.Errorf("unknown sort field %q (available: %s)", key.Field, strings.Join(SortFields(), ", "))
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// SortFields returns the field names ParseSort accepts
+func SortFields() []string {
+	fields := make([]string, 0, len(sortColumns))
+	for field := range sortColumns {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// orderBy builds an ORDER BY clause from sort keys and its arguments. Tasks without
+// a deadline come last in either direction, and the newest task breaks ties.
+func orderBy(keys []SortKey) (string, []interface{}) {
+	var terms []string
+	var args []interface{}
+	for _, key := range keys {
+		column := sortColumns[key.Field]
+		if key.Field == "deadline" {
+			terms = append(terms, "deadline = ? ASC")
+			args = append(args, time.Time{}.Unix())
+		}
+
+		direction := "ASC"
+		if key.Descending {
+			direction = "DESC"
+		}
+		terms = append(terms, column+" "+direction)
+	}
+	terms = append(terms, "created_at DESC", "id DESC")
+
+	return "ORDER BY " + strings.Join(terms, ", "), args
+}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseSort(t *testing.T) {
+	t.Run("should read fields and directions", func(t *testing.T) {
+		// Act
+		keys, err := ParseSort("deadline, -Priority,title")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []SortKey{{Field: "deadline"}, {Field: "priority", Descending: true}, {Field: "title"}}
+		if !slices.Equal(keys, expected) {
+			t.Errorf("expected %v, got %v", expected, keys)
+		}
+	})
+
+	t.Run("should reject unknown fields", func(t *testing.T) {
+		// Act
+		_, err := ParseSort("deadline,size")
+
+		// Assert
+		if err == nil {
+			t.Error("expected an error for an unknown field")
+		}
+	})
+}
+
+func TestStorageListTasksSorted(t *testing.T) {
+	newStorage := func(t *testing.T) *Storage {
+		s := setupTestStorage(t)
+		base := time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)
+		tasks := []struct {
+			title    string
+			priority int
+			deadline time.Time
+		}{
+			{"Book venue", 1, base.AddDate(0, 0, 5)},
+			{"answer email", 3, time.Time{}},
+			{"Call plumber", 3, base.AddDate(0, 0, 1)},
+			{"Draft agenda", 2, base.AddDate(0, 0, 5)},
+		}
+		for i, tt := range tasks {
+			created := createTestTask(tt.title)
+			created.Priority = tt.priority
+			created.Deadline = tt.deadline
+			created.CreatedAt = base.Add(time.Duration(i) * time.Hour)
+			if err := s.CreateTask(created, nil); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+		}
+		return s
+	}
+
+	tests := []struct {
+		spec     string
+		expected []string
+	}{
+		{"", []string{"Draft agenda", "Call plumber", "answer email", "Book venue"}},
+		{"deadline,-priority", []string{"Call plumber", "Draft agenda", "Book venue", "answer email"}},
+		{"-deadline", []string{"Draft agenda", "Book venue", "Call plumber", "answer email"}},
+		{"-priority,title", []string{"answer email", "Call plumber", "Draft agenda", "Book venue"}},
+	}
+
+	for _, tt := range tests {
+		t.Run("should order by "+tt.spec, func(t *testing.T) {
+			// Arrange
+			s := newStorage(t)
+			keys, err := ParseSort(tt.spec)
+			if err != nil {
+				t.Fatalf("failed to parse sort: %v", err)
+			}
+
+			// Act
+			tasks, err := s.ListTasks(nil, keys)
+
+			// Assert
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var titles []string
+			for _, listed := range tasks {
+				titles = append(titles, listed.Title)
+			}
+			if !slices.Equal(titles, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, titles)
+			}
+		})
+	}
+}
@@ -6,11 +6,15 @@ import (
 	"time"
 
 	_ "github.com/mattn/go-sqlite3" // SQLite driver
+	"github.com/tennashi/tabler/internal/parser"
+	"github.com/tennashi/tabler/internal/query"
 	"github.com/tennashi/tabler/internal/task"
 )
 
 type Storage struct {
 	db *sql.DB
+	// tagRules normalize tags as they are written and filtered
+	tagRules parser.TagRules
 }
 
 func New(dbPath string) (*Storage, error) {
@@ -19,7 +23,12 @@ func New(dbPath string) (*Storage, error) {
 		return nil, err
 	}
 
-	return &Storage{db: db}, nil
+	return &Storage{db: db, tagRules: parser.DefaultTagRules()}, nil
+}
+
+// SetTagRules sets how tags are normalized when tasks are saved and filtered
+func (s *Storage) SetTagRules(rules parser.TagRules) {
+	s.tagRules = rules
 }
 
 func (s *Storage) Close() error {
@@ -65,14 +74,91 @@ func (s *Storage) CreateTask(t *task.Task, tags []string) error {
 		_ = tx.Rollback()
 	}()
 
+	if err := insertTask(tx, t, parser.NormalizeTags(tags, s.tagRules), ""); err != nil {
+		return err
+	}
+	if err := insertDependencies(tx, t); err != nil {
+		return err
+	}
+
+	// Commit transaction
+	return tx.Commit()
+}
+
+// CreateTaskTree creates a task with its subtasks, recursively, in one transaction.
+// Each task keeps its own Tags; nothing is stored if any task fails.
+func (s *Storage) CreateTaskTree(root *task.Task) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if err := s.insertTaskTree(tx, root, ""); err != nil {
+		return err
+	}
+	// Dependencies may point at later siblings, so add them once every task exists
+	if err := insertTreeDependencies(tx, root); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertTaskTree inserts t under parentID, followed by its subtasks
+func (s *Storage) insertTaskTree(tx *sql.Tx, t *task.Task, parentID string) error {
+	if err := insertTask(tx, t, parser.NormalizeTags(t.Tags, s.tagRules), parentID); err != nil {
+		return fmt.Errorf("failed to create task %q: %w", t.Title, err)
+	}
+
+	for _, subtask := range t.Subtasks {
+		if err := s.insertTaskTree(tx, subtask, t.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertTreeDependencies records the dependencies of t and all its subtasks
+func insertTreeDependencies(tx *sql.Tx, t *task.Task) error {
+	if err := insertDependencies(tx, t); err != nil {
+		return err
+	}
+	for _, subtask := range t.Subtasks {
+		if err := insertTreeDependencies(tx, subtask); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertDependencies records that t waits for each task in t.DependsOn
+func insertDependencies(tx *sql.Tx, t *task.Task) error {
+	for _, dependencyID := range t.DependsOn {
+		query := `INSERT OR IGNORE INTO task_dependencies (task_id, depends_on_id) VALUES (?, ?)`
+		if _, err := tx.Exec(query, t.ID, dependencyID); err != nil {
+			return fmt.Errorf("failed to add dependency of %q: %w", t.Title, err)
+		}
+	}
+	return nil
+}
+
+// insertTask inserts a task and its tags, under parentID unless it is empty
+func insertTask(tx *sql.Tx, t *task.Task, tags []string, parentID string) error {
 	// Insert task
 	query := `
-	INSERT INTO tasks (id, title, deadline, priority, completed, created_at, updated_at)
-	VALUES (?, ?, ?, ?, ?, ?, ?)
+	INSERT INTO tasks (
+		id, title, deadline, priority, completed, no_ai, notes,
+		effort_minutes, plan_order, created_at, updated_at, parent_task_id
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
-	_, err = tx.Exec(query,
+	_, err := tx.Exec(query,
 		t.ID, t.Title, t.Deadline.Unix(), t.Priority,
-		t.Completed, t.CreatedAt.Unix(), t.UpdatedAt.Unix())
+		t.Completed, t.NoAI, t.Notes, int64(t.Effort/time.Minute), t.Order,
+		t.CreatedAt.Unix(), t.UpdatedAt.Unix(),
+		sql.NullString{String: parentID, Valid: parentID != ""})
 	if err != nil {
 		return err
 	}
@@ -86,8 +172,7 @@ func (s *Storage) CreateTask(t *task.Task, tags []string) error {
 		}
 	}
 
-	// Commit transaction
-	return tx.Commit()
+	return nil
 }
 
 func (s *Storage) GetTask(id string) (*task.Task, []string, error) {
@@ -96,19 +181,22 @@ func (s *Storage) GetTask(id string) (*task.Task, []string, error) {
 	var deadlineUnix, createdAtUnix, updatedAtUnix int64
 	var completed bool
 
+	var effortMinutes int64
+
 	query := `
-	SELECT id, title, deadline, priority, completed, created_at, updated_at
+	SELECT id, title, deadline, priority, completed, no_ai, notes, effort_minutes, plan_order, created_at, updated_at
 	FROM tasks
 	WHERE id = ?
 	`
 
 	err := s.db.QueryRow(query, id).Scan(
 		&t.ID, &t.Title, &deadlineUnix, &t.Priority,
-		&completed, &createdAtUnix, &updatedAtUnix,
+		&completed, &t.NoAI, &t.Notes, &effortMinutes, &t.Order, &createdAtUnix, &updatedAtUnix,
 	)
 	if err != nil {
 		return nil, nil, err
 	}
+	t.Effort = time.Duration(effortMinutes) * time.Minute
 
 	// Convert Unix timestamps to time.Time
 	t.Deadline = time.Unix(deadlineUnix, 0).UTC()
@@ -142,30 +230,46 @@ func (s *Storage) GetTask(id string) (*task.Task, []string, error) {
 	return &t, tags, nil
 }
 
-func (s *Storage) ListTasks(_ map[string]interface{}) ([]*task.Task, error) {
-	query := `
-	SELECT id, title, deadline, priority, completed, created_at, updated_at
-	FROM tasks
-	ORDER BY created_at DESC, id DESC
-	`
-
-	rows, err := s.db.Query(query)
+// ListTasks returns the tasks matching filter in the given order. A nil filter
+// matches every task and no keys list the newest first.
+func (s *Storage) ListTasks(filter query.Expr, keys []SortKey) ([]*task.Task, error) {
+	where, args, err := whereClause(filter, s.tagRules)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		_ = rows.Close()
-	}()
+	order, orderArgs := orderBy(keys)
+
+	query := `
+	SELECT id, title, deadline, priority, completed, no_ai, notes, effort_minutes, plan_order, created_at, updated_at
+	FROM tasks
+	` + where + `
+	` + order
 
+	return s.queryTasks(query, append(args, orderArgs...)...)
+}
+
+// PendingTasks returns every task that is not completed, oldest first
+func (s *Storage) PendingTasks() ([]*task.Task, error) {
+	query := `
+	SELECT id, title, deadline, priority, completed, no_ai, notes, effort_minutes, plan_order, created_at, updated_at
+	FROM tasks
+	WHERE completed = 0
+	ORDER BY created_at ASC, plan_order ASC, id ASC
+	`
+	return s.queryTasks(query)
+}
+
+// scanTasks reads rows selected with the columns of ListTasks
+func scanTasks(rows *sql.Rows) ([]*task.Task, error) {
 	var tasks []*task.Task
 	for rows.Next() {
 		var t task.Task
-		var deadlineUnix, createdAtUnix, updatedAtUnix int64
+		var deadlineUnix, effortMinutes, createdAtUnix, updatedAtUnix int64
 		var completed bool
 
 		err := rows.Scan(
 			&t.ID, &t.Title, &deadlineUnix, &t.Priority,
-			&completed, &createdAtUnix, &updatedAtUnix,
+			&completed, &t.NoAI, &t.Notes, &effortMinutes, &t.Order, &createdAtUnix, &updatedAtUnix,
 		)
 		if err != nil {
 			return nil, err
@@ -175,6 +279,7 @@ func (s *Storage) ListTasks(_ map[string]interface{}) ([]*task.Task, error) {
 		t.Deadline = time.Unix(deadlineUnix, 0).UTC()
 		t.CreatedAt = time.Unix(createdAtUnix, 0).UTC()
 		t.UpdatedAt = time.Unix(updatedAtUnix, 0).UTC()
+		t.Effort = time.Duration(effortMinutes) * time.Minute
 		t.Completed = completed
 
 		tasks = append(tasks, &t)
@@ -222,10 +327,25 @@ func (s *Storage) DeleteTask(id string) error {
 		_ = tx.Rollback()
 	}()
 
+	if err := deleteTask(tx, id); err != nil {
+		return err
+	}
+
+	// Commit transaction
+	return tx.Commit()
+}
+
+// deleteTask removes a task with its tags and dependencies within tx
+func deleteTask(tx *sql.Tx, id string) error {
 	// Delete tags first (foreign key constraint)
 	tagQuery := `DELETE FROM task_tags WHERE task_id = ?`
-	_, err = tx.Exec(tagQuery, id)
-	if err != nil {
+	if _, err := tx.Exec(tagQuery, id); err != nil {
+		return err
+	}
+
+	// Delete dependencies in both directions
+	dependencyQuery := `DELETE FROM task_dependencies WHERE task_id = ? OR depends_on_id = ?`
+	if _, err := tx.Exec(dependencyQuery, id, id); err != nil {
 		return err
 	}
 
@@ -245,8 +365,7 @@ func (s *Storage) DeleteTask(id string) error {
 		return sql.ErrNoRows
 	}
 
-	// Commit transaction
-	return tx.Commit()
+	return nil
 }
 
 func (s *Storage) UpdateTaskFull(t *task.Task, tags []string) error {
@@ -262,13 +381,13 @@ func (s *Storage) UpdateTaskFull(t *task.Task, tags []string) error {
 	// Update task
 	query := `
 	UPDATE tasks 
-	SET title = ?, deadline = ?, priority = ?, completed = ?, updated_at = ?
+	SET title = ?, deadline = ?, priority = ?, completed = ?, no_ai = ?, notes = ?, updated_at = ?
 	WHERE id = ?
 	`
 
 	now := time.Now().UTC()
 	result, err := tx.Exec(query,
-		t.Title, t.Deadline.Unix(), t.Priority, t.Completed, now.Unix(), t.ID)
+		t.Title, t.Deadline.Unix(), t.Priority, t.Completed, t.NoAI, t.Notes, now.Unix(), t.ID)
 	if err != nil {
 		return err
 	}
@@ -290,7 +409,7 @@ func (s *Storage) UpdateTaskFull(t *task.Task, tags []string) error {
 	}
 
 	// Insert new tags
-	for _, tag := range tags {
+	for _, tag := range parser.NormalizeTags(tags, s.tagRules) {
 		tagQuery := `INSERT INTO task_tags (task_id, tag) VALUES (?, ?)`
 		_, err = tx.Exec(tagQuery, t.ID, tag)
 		if err != nil {
@@ -149,6 +149,46 @@ func TestStorage(t *testing.T) {
 				}
 			}
 		})
+
+		t.Run("should keep the no-AI flag", func(t *testing.T) {
+			// Arrange
+			storage := setupTestStorage(t)
+			now := time.Now().UTC()
+			privateTask := &task.Task{ID: "task-789", Title: "Private note", NoAI: true, CreatedAt: now, UpdatedAt: now}
+			if err := storage.CreateTask(privateTask, nil); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+
+			// Act
+			retrievedTask, _, err := storage.GetTask("task-789")
+			// Assert
+			if err != nil {
+				t.Fatalf("GetTask() returned error: %v", err)
+			}
+			if !retrievedTask.NoAI {
+				t.Error("expected NoAI to be true")
+			}
+		})
+
+		t.Run("should keep notes", func(t *testing.T) {
+			// Arrange
+			storage := setupTestStorage(t)
+			now := time.Now().UTC()
+			notedTask := &task.Task{ID: "task-790", Title: "Prepare slides", Notes: "Audience: team", CreatedAt: now, UpdatedAt: now}
+			if err := storage.CreateTask(notedTask, nil); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+
+			// Act
+			retrievedTask, _, err := storage.GetTask("task-790")
+			// Assert
+			if err != nil {
+				t.Fatalf("GetTask() returned error: %v", err)
+			}
+			if retrievedTask.Notes != "Audience: team" {
+				t.Errorf("expected notes %q, got %q", "Audience: team", retrievedTask.Notes)
+			}
+		})
 	})
 
 	t.Run("ListTasks", func(t *testing.T) {
@@ -199,7 +239,7 @@ func TestStorage(t *testing.T) {
 			}
 
 			// Act
-			tasks, err := storage.ListTasks(nil)
+			tasks, err := storage.ListTasks(nil, nil)
 			// Assert
 			if err != nil {
 				t.Errorf("ListTasks() returned error: %v", err)
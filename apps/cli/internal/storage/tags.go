@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tennashi/tabler/internal/parser"
+)
+
+// ErrTagExists is returned when renaming a tag to one that is already in use
+var ErrTagExists = errors.New("tag already exists")
+
+// TagUsage describes how a tag is used
+type TagUsage struct {
+	Tag string
+	// Count is the number of tasks with the tag
+	Count int
+	// LastUsed is when the newest task with the tag was created
+	LastUsed time.Time
+}
+
+// ListTagUsage returns every tag in use, most used first
+func (s *Storage) ListTagUsage() ([]*TagUsage, error) {
+	query := `
+	SELECT tt.tag, COUNT(*), MAX(t.created_at)
+	FROM task_tags tt
+	JOIN tasks t ON t.id = tt.task_id
+	GROUP BY tt.tag
+	ORDER BY COUNT(*) DESC, tt.tag ASC
+	`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var usages []*TagUsage
+	for rows.Next() {
+		var usage TagUsage
+		var lastUsed int64
+		if err := rows.Scan(&usage.Tag, &usage.Count, &lastUsed); err != nil {
+			return nil, err
+		}
+		usage.LastUsed = time.Unix(lastUsed, 0).UTC()
+		usages = append(usages, &usage)
+	}
+
+	return usages, rows.Err()
+}
+
+// RenameTag renames a tag and its descendants on every task, so renaming work
+// to job turns work/clienta into job/clienta, and returns how many tasks changed.
+// It returns sql.ErrNoRows if the tag is not used and ErrTagExists if the new
+// name is already used.
+func (s *Storage) RenameTag(from, to string) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	existingCondition, existingArgs := tagTree("tag", to)
+	var existing int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM task_tags WHERE `+existingCondition, existingArgs...).Scan(&existing); err != nil {
+		return 0, err
+	}
+	if existing > 0 {
+		return 0, fmt.Errorf("%s: %w", to, ErrTagExists)
+	}
+
+	condition, args := tagTree("tag", from)
+	var tasks int
+	if err := tx.QueryRow(`SELECT COUNT(DISTINCT task_id) FROM task_tags WHERE `+condition, args...).Scan(&tasks); err != nil {
+		return 0, err
+	}
+	if tasks == 0 {
+		return 0, fmt.Errorf("%s: %w", from, sql.ErrNoRows)
+	}
+
+	// substr counts characters, so keep everything after the old name
+	rename := `UPDATE task_tags SET tag = ? || substr(tag, ?) WHERE ` + condition
+	if _, err := tx.Exec(rename, append([]interface{}{to, utf8.RuneCountInString(from) + 1}, args...)...); err != nil {
+		return 0, err
+	}
+
+	return tasks, tx.Commit()
+}
+
+// TagMerge reports what MergeTags changed
+type TagMerge struct {
+	// Tags is the number of source tags, descendants included, replaced by into
+	Tags int
+	// Tasks is the number of tasks whose tags changed
+	Tasks int
+}
+
+// MergeTags replaces the source tags with into on every task, moving their
+// descendants under into as RenameTag does, and reports what changed. It
+// returns sql.ErrNoRows if there is nothing to merge.
+func (s *Storage) MergeTags(sources []string, into string) (*TagMerge, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var merged, conditions []string
+	var args [][]interface{}
+	for _, source := range sources {
+		if source == into {
+			continue
+		}
+		condition, sourceArgs := tagTree("tag", source)
+		// Keep into and its descendants when they sit under the source
+		if parser.TagIncludes(source, into) {
+			intoCondition, intoArgs := tagTree("tag", into)
+			condition += " AND NOT " + intoCondition
+			sourceArgs = append(sourceArgs, intoArgs...)
+		}
+		merged = append(merged, source)
+		conditions = append(conditions, condition)
+		args = append(args, sourceArgs)
+	}
+	if len(conditions) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	merge := &TagMerge{}
+	countQuery := `SELECT COUNT(DISTINCT tag), COUNT(DISTINCT task_id) FROM task_tags WHERE ` +
+		strings.Join(conditions, " OR ")
+	if err := tx.QueryRow(countQuery, slices.Concat(args...)...).Scan(&merge.Tags, &merge.Tasks); err != nil {
+		return nil, err
+	}
+	if merge.Tasks == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	for i, source := range merged {
+		// substr counts characters, so keep everything after the source name
+		insert := `
+		INSERT OR IGNORE INTO task_tags (task_id, tag)
+		SELECT task_id, ? || substr(tag, ?) FROM task_tags WHERE ` + conditions[i]
+		insertArgs := append([]interface{}{into, utf8.RuneCountInString(source) + 1}, args[i]...)
+		if _, err := tx.Exec(insert, insertArgs...); err != nil {
+			return nil, err
+		}
+
+		if _, err := tx.Exec(`DELETE FROM task_tags WHERE `+conditions[i], args[i]...); err != nil {
+			return nil, err
+		}
+	}
+
+	return merge, tx.Commit()
+}
+
+// DeleteTag removes a tag and its descendants from every task and returns how
+// many tasks had one of them
+func (s *Storage) DeleteTag(tag string) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	condition, args := tagTree("tag", tag)
+	var tasks int
+	if err := tx.QueryRow(`SELECT COUNT(DISTINCT task_id) FROM task_tags WHERE `+condition, args...).Scan(&tasks); err != nil {
+		return 0, err
+	}
+	if tasks == 0 {
+		return 0, sql.ErrNoRows
+	}
+
+	if _, err := tx.Exec(`DELETE FROM task_tags WHERE `+condition, args...); err != nil {
+		return 0, err
+	}
+
+	return tasks, tx.Commit()
+}
+
+// NormalizeTags rewrites every stored tag with the current rules, merging tags
+// that become the same and dropping those that become empty. It returns how
+// many tags changed.
+func (s *Storage) NormalizeTags() (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	rows, err := tx.Query(`SELECT DISTINCT tag FROM task_tags`)
+	if err != nil {
+		return 0, err
+	}
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		tags = append(tags, tag)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, tag := range tags {
+		normalized := parser.NormalizeTag(tag, s.tagRules)
+		if normalized == tag {
+			continue
+		}
+		if normalized != "" {
+			insert := `INSERT OR IGNORE INTO task_tags (task_id, tag) SELECT task_id, ? FROM task_tags WHERE tag = ?`
+			if _, err := tx.Exec(insert, normalized, tag); err != nil {
+				return 0, err
+			}
+		}
+		if _, err := tx.Exec(`DELETE FROM task_tags WHERE tag = ?`, tag); err != nil {
+			return 0, err
+		}
+		changed++
+	}
+
+	return changed, tx.Commit()
+}
+
+// tagTree returns an SQL condition matching a tag and its descendants in column.
+// Descendants sort between "tag/" and "tag0" ('0' follows '/'); unlike LIKE this
+// keeps case.
+func tagTree(column, tag string) (string, []interface{}) {
+	condition := "(" + column + " = ? OR (" + column + " > ? AND " + column + " < ?))"
+	return condition, []interface{}{tag, tag + parser.TagSeparator, tag + "0"}
+}
@@ -0,0 +1,305 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tennashi/tabler/internal/parser"
+	"github.com/tennashi/tabler/internal/query"
+)
+
+func TestStorageTags(t *testing.T) {
+	newStorage := func(t *testing.T) (*Storage, []string) {
+		s := setupTestStorage(t)
+		base := time.Date(2030, 5, 1, 0, 0, 0, 0, time.UTC)
+		tagged := [][]string{{"work", "urgent"}, {"work", "job"}, {"job"}}
+		var ids []string
+		for i, tags := range tagged {
+			created := createTestTask("Task")
+			created.CreatedAt = base.AddDate(0, 0, i)
+			if err := s.CreateTask(created, tags); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			ids = append(ids, created.ID)
+		}
+		return s, ids
+	}
+	tagsOf := func(t *testing.T, s *Storage, id string) []string {
+		_, tags, err := s.GetTask(id)
+		if err != nil {
+			t.Fatalf("failed to get task: %v", err)
+		}
+		slices.Sort(tags)
+		return tags
+	}
+
+	t.Run("should list tags with counts and last use", func(t *testing.T) {
+		// Arrange
+		s, _ := newStorage(t)
+
+		// Act
+		usages, err := s.ListTagUsage()
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(usages) != 3 {
+			t.Fatalf("expected 3 tags, got %d", len(usages))
+		}
+		if usages[0].Tag != "job" || usages[0].Count != 2 || usages[1].Tag != "work" || usages[2].Tag != "urgent" {
+			t.Errorf("unexpected order %v %v %v", *usages[0], *usages[1], *usages[2])
+		}
+		if expected := time.Date(2030, 5, 3, 0, 0, 0, 0, time.UTC); !usages[0].LastUsed.Equal(expected) {
+			t.Errorf("expected last use %v, got %v", expected, usages[0].LastUsed)
+		}
+	})
+
+	t.Run("should rename a tag unless the new name is taken", func(t *testing.T) {
+		// Arrange
+		s, ids := newStorage(t)
+
+		// Act
+		renamed, err := s.RenameTag("urgent", "asap")
+		_, takenErr := s.RenameTag("work", "job")
+
+		// Assert
+		if err != nil || renamed != 1 {
+			t.Fatalf("expected one task renamed, got %d, %v", renamed, err)
+		}
+		if got := tagsOf(t, s, ids[0]); !slices.Equal(got, []string{"asap", "work"}) {
+			t.Errorf("expected [asap work], got %v", got)
+		}
+		if !errors.Is(takenErr, ErrTagExists) {
+			t.Errorf("expected ErrTagExists, got %v", takenErr)
+		}
+	})
+
+	t.Run("should merge tags without duplicates", func(t *testing.T) {
+		// Arrange
+		s, ids := newStorage(t)
+
+		// Act
+		merged, err := s.MergeTags([]string{"work", "job"}, "career")
+
+		// Assert
+		if err != nil || merged.Tags != 2 || merged.Tasks != 3 {
+			t.Fatalf("expected two tags merged on three tasks, got %+v, %v", merged, err)
+		}
+		expected := [][]string{{"career", "urgent"}, {"career"}, {"career"}}
+		for i, id := range ids {
+			if got := tagsOf(t, s, id); !slices.Equal(got, expected[i]) {
+				t.Errorf("expected %v, got %v", expected[i], got)
+			}
+		}
+	})
+
+	t.Run("should merge into one of the source tags", func(t *testing.T) {
+		// Arrange
+		s, ids := newStorage(t)
+
+		// Act
+		merged, err := s.MergeTags([]string{"work", "job"}, "job")
+
+		// Assert
+		if err != nil || merged.Tags != 1 || merged.Tasks != 2 {
+			t.Fatalf("expected one tag merged on two tasks, got %+v, %v", merged, err)
+		}
+		if got := tagsOf(t, s, ids[0]); !slices.Equal(got, []string{"job", "urgent"}) {
+			t.Errorf("expected [job urgent], got %v", got)
+		}
+	})
+
+	t.Run("should report nothing to merge when only into is used", func(t *testing.T) {
+		// Arrange
+		s, _ := newStorage(t)
+
+		// Act
+		_, err := s.MergeTags([]string{"job", "missing"}, "job")
+
+		// Assert
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows, got %v", err)
+		}
+	})
+
+	t.Run("should delete a tag from every task", func(t *testing.T) {
+		// Arrange
+		s, ids := newStorage(t)
+
+		// Act
+		deleted, err := s.DeleteTag("work")
+		_, missingErr := s.DeleteTag("work")
+
+		// Assert
+		if err != nil || deleted != 2 {
+			t.Fatalf("expected two tasks changed, got %d, %v", deleted, err)
+		}
+		if got := tagsOf(t, s, ids[1]); !slices.Equal(got, []string{"job"}) {
+			t.Errorf("expected [job], got %v", got)
+		}
+		if !errors.Is(missingErr, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows, got %v", missingErr)
+		}
+	})
+
+	t.Run("should normalize tags when saving", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		created := createTestTask("Task")
+
+		// Act
+		err := s.CreateTask(created, []string{"Work,", "work", "Clients/Acme."})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := tagsOf(t, s, created.ID); !slices.Equal(got, []string{"clients/acme", "work"}) {
+			t.Errorf("expected [clients/acme work], got %v", got)
+		}
+	})
+
+	t.Run("should match descendants when filtering by a tag", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		for _, tag := range []string{"work", "work/clientA", "workshop", "home"} {
+			if err := s.CreateTask(createTestTask(tag), []string{tag}); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+		}
+		expr, err := query.Parse("tag:Work")
+		if err != nil {
+			t.Fatalf("failed to parse query: %v", err)
+		}
+
+		// Act
+		tasks, err := s.ListTasks(expr, []SortKey{{Field: "title"}})
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var titles []string
+		for _, listed := range tasks {
+			titles = append(titles, listed.Title)
+		}
+		if !slices.Equal(titles, []string{"work", "work/clientA"}) {
+			t.Errorf("expected [work work/clientA], got %v", titles)
+		}
+	})
+
+	t.Run("should rename the descendants of a tag", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		created := createTestTask("Task")
+		if err := s.CreateTask(created, []string{"work", "work/clienta", "workshop"}); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+
+		// Act
+		renamed, err := s.RenameTag("work", "job")
+
+		// Assert
+		if err != nil || renamed != 1 {
+			t.Fatalf("expected one task renamed, got %d, %v", renamed, err)
+		}
+		if got := tagsOf(t, s, created.ID); !slices.Equal(got, []string{"job", "job/clienta", "workshop"}) {
+			t.Errorf("expected [job job/clienta workshop], got %v", got)
+		}
+	})
+
+	t.Run("should merge the descendants of a tag", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		created := createTestTask("Task")
+		if err := s.CreateTask(created, []string{"work/clienta", "job/clienta", "job/clientb", "workshop"}); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+
+		// Act
+		merged, err := s.MergeTags([]string{"work", "job"}, "career")
+
+		// Assert
+		if err != nil || merged.Tags != 3 || merged.Tasks != 1 {
+			t.Fatalf("expected three tags merged on one task, got %+v, %v", merged, err)
+		}
+		expected := []string{"career/clienta", "career/clientb", "workshop"}
+		if got := tagsOf(t, s, created.ID); !slices.Equal(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("should merge a tag into one of its descendants", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		created := createTestTask("Task")
+		if err := s.CreateTask(created, []string{"work", "work/clienta", "work/clienta/report"}); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+
+		// Act
+		_, err := s.MergeTags([]string{"work"}, "work/clienta")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		expected := []string{"work/clienta", "work/clienta/report"}
+		if got := tagsOf(t, s, created.ID); !slices.Equal(got, expected) {
+			t.Errorf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("should delete the descendants of a tag", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		var ids []string
+		for _, tags := range [][]string{{"work", "work/clienta"}, {"work/clientb", "home"}, {"workshop"}} {
+			created := createTestTask("Task")
+			if err := s.CreateTask(created, tags); err != nil {
+				t.Fatalf("failed to create task: %v", err)
+			}
+			ids = append(ids, created.ID)
+		}
+
+		// Act
+		deleted, err := s.DeleteTag("work")
+
+		// Assert
+		if err != nil || deleted != 2 {
+			t.Fatalf("expected two tasks changed, got %d, %v", deleted, err)
+		}
+		expected := [][]string{nil, {"home"}, {"workshop"}}
+		for i, id := range ids {
+			if got := tagsOf(t, s, id); !slices.Equal(got, expected[i]) {
+				t.Errorf("expected %v, got %v", expected[i], got)
+			}
+		}
+	})
+
+	t.Run("should normalize stored tags", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		s.SetTagRules(parser.TagRules{})
+		created := createTestTask("Task")
+		if err := s.CreateTask(created, []string{"Work", "work,", "urgent", "!"}); err != nil {
+			t.Fatalf("failed to create task: %v", err)
+		}
+		s.SetTagRules(parser.DefaultTagRules())
+
+		// Act
+		changed, err := s.NormalizeTags()
+
+		// Assert
+		if err != nil || changed != 3 {
+			t.Fatalf("expected three tags changed, got %d, %v", changed, err)
+		}
+		if got := tagsOf(t, s, created.ID); !slices.Equal(got, []string{"urgent", "work"}) {
+			t.Errorf("expected [urgent work], got %v", got)
+		}
+	})
+}
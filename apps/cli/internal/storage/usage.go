@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"time"
+)
+
+// AIUsage is a recorded call to the AI provider
+type AIUsage struct {
+	Feature       string
+	StartedAt     time.Time
+	Latency       time.Duration
+	PromptBytes   int
+	ResponseBytes int
+	Success       bool
+	Error         string
+}
+
+// RecordAIUsage stores a single AI call
+func (s *Storage) RecordAIUsage(u *AIUsage) error {
+	query := `
+	INSERT INTO ai_usage (feature, started_at, latency_ms, prompt_bytes, response_bytes, success, error)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := s.db.Exec(query,
+		u.Feature, u.StartedAt.Unix(), u.Latency.Milliseconds(),
+		u.PromptBytes, u.ResponseBytes, u.Success, u.Error)
+	return err
+}
+
+// ListAIUsage returns AI calls started at or after since, oldest first
+func (s *Storage) ListAIUsage(since time.Time) ([]*AIUsage, error) {
+	query := `
+	SELECT feature, started_at, latency_ms, prompt_bytes, response_bytes, success, COALESCE(error, '')
+	FROM ai_usage
+	WHERE started_at >= ?
+	ORDER BY started_at, id
+	`
+
+	rows, err := s.db.Query(query, since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var usages []*AIUsage
+	for rows.Next() {
+		var u AIUsage
+		var startedAt, latencyMs int64
+
+		err := rows.Scan(
+			&u.Feature, &startedAt, &latencyMs,
+			&u.PromptBytes, &u.ResponseBytes, &u.Success, &u.Error,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		u.StartedAt = time.Unix(startedAt, 0).UTC()
+		u.Latency = time.Duration(latencyMs) * time.Millisecond
+
+		usages = append(usages, &u)
+	}
+
+	return usages, rows.Err()
+}
@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStorageAIUsage(t *testing.T) {
+	t.Run("should list usage recorded since a time", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		now := time.Now().UTC()
+
+		old := &AIUsage{Feature: "metadata", StartedAt: now.AddDate(0, 0, -10), Latency: time.Second, Success: true}
+		recent := &AIUsage{
+			Feature:       "decomposition",
+			StartedAt:     now.Add(-time.Hour),
+			Latency:       1500 * time.Millisecond,
+			PromptBytes:   120,
+			ResponseBytes: 80,
+			Success:       false,
+			Error:         "timeout",
+		}
+		for _, u := range []*AIUsage{old, recent} {
+			if err := s.RecordAIUsage(u); err != nil {
+				t.Fatalf("failed to record usage: %v", err)
+			}
+		}
+
+		// Act
+		usages, err := s.ListAIUsage(now.AddDate(0, 0, -7))
+		// Assert
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(usages) != 1 {
+			t.Fatalf("expected 1 usage, got %d", len(usages))
+		}
+		got := usages[0]
+		if got.Feature != "decomposition" || got.Latency != 1500*time.Millisecond || got.Success || got.Error != "timeout" {
+			t.Errorf("unexpected usage: %+v", got)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"database/sql"
+	"time"
+)
+
+// View is a saved list invocation
+type View struct {
+	Name string
+	// Query is the filter expression, empty for every task
+	Query string
+	// Sort is the sort spec, empty for the default order
+	Sort string
+	// Output is the output format, empty for the default
+	Output    string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SaveView stores a view, replacing any view with the same name
+func (s *Storage) SaveView(view *View) error {
+	query := `
+	INSERT INTO saved_views (name, query, sort, output, created_at, updated_at)
+	VALUES (?, ?, ?, ?, ?, ?)
+	ON CONFLICT (name) DO UPDATE SET
+		query = excluded.query,
+		sort = excluded.sort,
+		output = excluded.output,
+		updated_at = excluded.updated_at
+	`
+	_, err := s.db.Exec(query,
+		view.Name, view.Query, view.Sort, view.Output, view.CreatedAt.Unix(), view.UpdatedAt.Unix())
+	return err
+}
+
+// GetView retrieves a saved view by name
+func (s *Storage) GetView(name string) (*View, error) {
+	query := `
+	SELECT name, query, sort, output, created_at, updated_at
+	FROM saved_views
+	WHERE name = ?
+	`
+	return scanView(s.db.QueryRow(query, name))
+}
+
+// ListViews returns saved views sorted by name
+func (s *Storage) ListViews() ([]*View, error) {
+	query := `
+	SELECT name, query, sort, output, created_at, updated_at
+	FROM saved_views
+	ORDER BY name
+	`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var views []*View
+	for rows.Next() {
+		view, err := scanView(rows)
+		if err != nil {
+			return nil, err
+		}
+		views = append(views, view)
+	}
+
+	return views, rows.Err()
+}
+
+// DeleteView removes a saved view
+func (s *Storage) DeleteView(name string) error {
+	result, err := s.db.Exec(`DELETE FROM saved_views WHERE name = ?`, name)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanView(row rowScanner) (*View, error) {
+	var view View
+	var createdAt, updatedAt int64
+
+	if err := row.Scan(&view.Name, &view.Query, &view.Sort, &view.Output, &createdAt, &updatedAt); err != nil {
+		return nil, err
+	}
+
+	view.CreatedAt = time.Unix(createdAt, 0).UTC()
+	view.UpdatedAt = time.Unix(updatedAt, 0).UTC()
+	return &view, nil
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStorageViews(t *testing.T) {
+	t.Run("should replace a view saved under the same name", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		created := time.Date(2030, 5, 1, 9, 0, 0, 0, time.UTC)
+		view := &View{Name: "sprint", Query: "tag:sprint", CreatedAt: created, UpdatedAt: created}
+		if err := s.SaveView(view); err != nil {
+			t.Fatalf("failed to save view: %v", err)
+		}
+		updated := &View{Name: "sprint", Query: "tag:sprint and not done", Sort: "-priority", Output: "json",
+			CreatedAt: created.Add(time.Hour), UpdatedAt: created.Add(time.Hour)}
+
+		// Act
+		if err := s.SaveView(updated); err != nil {
+			t.Fatalf("failed to update view: %v", err)
+		}
+		loaded, err := s.GetView("sprint")
+
+		// Assert
+		if err != nil {
+			t.Fatalf("failed to get view: %v", err)
+		}
+		if loaded.Query != updated.Query || loaded.Sort != "-priority" || loaded.Output != "json" {
+			t.Errorf("unexpected view %+v", *loaded)
+		}
+		if !loaded.CreatedAt.Equal(created) || !loaded.UpdatedAt.Equal(updated.UpdatedAt) {
+			t.Errorf("unexpected timestamps %v / %v", loaded.CreatedAt, loaded.UpdatedAt)
+		}
+	})
+
+	t.Run("should list views by name and delete them", func(t *testing.T) {
+		// Arrange
+		s := setupTestStorage(t)
+		now := time.Now()
+		for _, name := range []string{"waiting", "sprint"} {
+			if err := s.SaveView(&View{Name: name, CreatedAt: now, UpdatedAt: now}); err != nil {
+				t.Fatalf("failed to save view: %v", err)
+			}
+		}
+
+		// Act
+		if err := s.DeleteView("waiting"); err != nil {
+			t.Fatalf("failed to delete view: %v", err)
+		}
+		views, err := s.ListViews()
+
+		// Assert
+		if err != nil {
+			t.Fatalf("failed to list views: %v", err)
+		}
+		if len(views) != 1 || views[0].Name != "sprint" {
+			t.Errorf("expected only the sprint view, got %v", views)
+		}
+		if err := s.DeleteView("waiting"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("expected sql.ErrNoRows for a deleted view, got %v", err)
+		}
+	})
+}
@@ -8,6 +8,13 @@ type Task struct {
 	Deadline  time.Time
 	Priority  int
 	Completed bool
+	NoAI      bool          // never send this task to the AI provider
+	Notes     string        // details gathered while creating the task, e.g. by clarification
+	Tags      []string      // tags a mode handler assigns to a new task
+	Subtasks  []*Task       // subtasks a mode handler plans under a new task
+	DependsOn []string      // IDs of tasks a mode handler says must be completed first
+	Effort    time.Duration // estimated effort, 0 when unknown
+	Order     int           // position among the subtasks of its parent, 0 outside a plan
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }